@@ -1,14 +1,18 @@
 package main
 
 import (
+	"cms/internal/auth"
 	"cms/internal/config"
 	"cms/internal/core"
 	"cms/internal/handlers"
+	"cms/internal/media"
 	"cms/internal/models"
+	"cms/internal/render"
+	"cms/internal/sanitize"
 	"cms/internal/storage"
+	"cms/internal/workflow"
 
 	"embed"
-	"encoding/gob"
 	"log"
 	"os"
 	"runtime/debug"
@@ -17,7 +21,6 @@ import (
 
 	// Session management
 	session "github.com/fasthttp/session/v2"
-	"github.com/fasthttp/session/v2/providers/memory"
 
 	"github.com/valyala/fasthttp"
 )
@@ -43,30 +46,88 @@ func main() {
 	// Initialize configuration
 	cfg := config.Load()
 
-	// Register custom types for session encoding (gob)
-	gob.Register(models.Content{})
-	gob.Register(map[string]models.Content{})
+	// Command line subcommands (run instead of the server)
+	if len(os.Args) > 1 && os.Args[1] == "token" {
+		os.Exit(runTokenCommand(cfg, os.Args[2:]))
+	}
+
+	// Open the database (sessions and, in persistent mode, content)
+	db, err := storage.OpenDB(cfg.DBPath)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
 
 	// Initialize session management
-	// 1. Create provider
-	provider, err := memory.New(memory.Config{})
+	// 1. Create provider (persisted in bbolt so logins survive restarts)
+	provider, err := storage.NewBoltSessionProvider(db)
 	if err != nil {
 		log.Fatalf("Failed to create session provider: %v", err)
 	}
 	// 2. Create session config
 	sessionConfig := session.NewDefaultConfig()
-	sessionConfig.CookieName = "cms_sessionid" // Customize cookie name
-	sessionConfig.Expiration = 24 * time.Hour  // Set session expiration (e.g., 24 hours)
-	sessionConfig.Secure = false               // Set to true if using HTTPS
-	// sessionConfig.Encoder = fasthttpgob.Encoder // Explicitly use gob
-	// sessionConfig.Decoder = fasthttpgob.Decoder // Explicitly use gob
+	sessionConfig.CookieName = cfg.SessionCookieName
+	sessionConfig.Expiration = cfg.SessionExpiration
+	sessionConfig.Secure = cfg.SessionSecure // Only sent over HTTPS when enabled
+	sessionConfig.Domain = cfg.SessionDomain
+	sessionConfig.CookieSameSite = cookieSameSite(cfg.SessionSameSite)
+	sessionConfig.IsSecureFunc = func(ctx *fasthttp.RequestCtx) bool {
+		// Honor TLS terminated by a trusted reverse proxy
+		return handlers.IsSecureRequest(ctx, cfg.TrustProxy)
+	}
 	// 3. Create session manager
 	sess := session.New(sessionConfig)
-	// 4. Set provider for the session manager
+	// 4. Set provider for the session manager (also starts expiry sweeping)
 	if err = sess.SetProvider(provider); err != nil {
 		log.Fatalf("Failed to set session provider: %v", err)
 	}
 
+	// Initialize user accounts
+	users, err := storage.NewUserStore(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize user store: %v", err)
+	}
+	if err := bootstrapAdmin(users, cfg); err != nil {
+		log.Fatalf("Failed to create initial admin: %v", err)
+	}
+	tokens, err := storage.NewTokenStore(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize API token store: %v", err)
+	}
+	// Custom content types, taxonomies and media are shared by every storage
+	// mode, like users; the sandbox keeps them read-only
+	types, err := storage.NewTypeStore(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize content type store: %v", err)
+	}
+	taxonomies, err := storage.NewTaxonomyStore(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize taxonomy store: %v", err)
+	}
+
+	// Uploaded files live on disk, their metadata in the database
+	mediaStore, err := storage.NewMediaStore(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize media store: %v", err)
+	}
+	blobs, err := media.NewDiskStore(cfg.MediaDir)
+	if err != nil {
+		log.Fatalf("Failed to initialize media directory: %v", err)
+	}
+
+	// Initialize the login limiter (optionally persisted so lockouts survive restarts)
+	var attemptStore auth.AttemptStore
+	if cfg.LoginPersist {
+		if attemptStore, err = storage.NewAttemptStore(db); err != nil {
+			log.Fatalf("Failed to initialize login attempt store: %v", err)
+		}
+	}
+	limiter, err := auth.NewLimiter(cfg.LoginLimitAttempt, cfg.LoginLockDuration, attemptStore)
+	if err != nil {
+		log.Fatalf("Failed to initialize login limiter: %v", err)
+	}
+	defer limiter.Close()
+
 	// Initialize Initial Data Reader
 	initialDataReader, errDb := storage.NewInitialDataReader(assets, "assets/db/initial.db")
 	if errDb != nil {
@@ -82,6 +143,52 @@ func main() {
 		log.Printf("Warning: Failed to close initial data reader cleanly: %v", errClose)
 	}
 
+	// Initialize the content backend for the configured storage mode
+	var backend storage.Backend
+	switch cfg.StorageMode {
+	case config.StorageModeSandbox:
+		// Every session gets a private copy of the initial content
+		sandboxBackend := storage.NewSandboxBackend(initialContent, cfg.SandboxMaxItems, cfg.RevisionLimit, cfg.SandboxTTL)
+		defer sandboxBackend.Close()
+		backend = sandboxBackend
+	case config.StorageModeMemory:
+		// One shared store, lost on restart
+		backend = storage.NewMemoryStore(initialContent, 0, cfg.RevisionLimit)
+	default:
+		contentStore, err := storage.NewBoltStore(db, cfg.RevisionLimit)
+		if err != nil {
+			log.Fatalf("Failed to initialize content store: %v", err)
+		}
+		// Seed the content store on first boot only
+		if _, err := contentStore.Seed(initialContent); err != nil {
+			log.Fatalf("Failed to seed content store: %v", err)
+		}
+		backend = contentStore
+	}
+
+	// Permanently delete items left in the trash past the retention period,
+	// and publish or archive scheduled items on time
+	var scheduler *storage.Scheduler
+	if stores, ok := backend.(storage.Enumerator); ok {
+		purger := storage.NewTrashPurger(stores, cfg.TrashRetention)
+		defer purger.Close()
+		scheduler = storage.NewScheduler(stores)
+		defer scheduler.Close()
+	}
+
+	// Initialize the HTML sanitizer for content bodies
+	sanitizer, err := sanitize.New(cfg.HTMLAllowedTags)
+	if err != nil {
+		log.Fatalf("Invalid HTML allowlist: %v", err)
+	}
+	renderer := render.New(sanitizer)
+
+	// Load the editorial workflow
+	wf, err := workflow.Load(cfg.WorkflowFile)
+	if err != nil {
+		log.Fatalf("Failed to load workflow: %v", err)
+	}
+
 	// Initialize router
 	router := core.NewRouter()
 
@@ -89,16 +196,45 @@ func main() {
 	// staticHandler := handlers.NewStaticHandler(assets, "assets/static")
 	// router.GET("/static/*filepath", staticHandler.Handle)
 
-	// API handlers for CRUD operations (Now need initialContent for cloning)
-	crudHandler := handlers.NewCRUDHandler(sess, cfg, initialContent)
+	// API handlers for CRUD operations
+	crudHandler := handlers.NewCRUDHandler(sess, cfg, backend, sanitizer, renderer, scheduler, wf, types, taxonomies, mediaStore, blobs)
 	router.GET("/api/content", crudHandler.List)
+	router.POST("/api/content/_bulk", crudHandler.Bulk)
 	router.GET("/api/content/{id}", crudHandler.Get)
+	router.GET("/api/content/by-slug/{slug}", crudHandler.BySlug) // Public route
 	router.POST("/api/content", crudHandler.Create)
+	router.POST("/api/content/preview", crudHandler.Preview)
 	router.PUT("/api/content/{id}", crudHandler.Update)
+	router.PATCH("/api/content/{id}", crudHandler.Patch)
 	router.DELETE("/api/content/{id}", crudHandler.Delete)
+	router.POST("/api/content/{id}/trash", crudHandler.Trash)
+	router.POST("/api/content/{id}/restore", crudHandler.Restore)
+	router.POST("/api/content/{id}/purge", crudHandler.Purge)
+	router.POST("/api/content/{id}/status", crudHandler.Transition)
+	router.GET("/api/search", crudHandler.Search)
+	router.GET("/api/content/{id}/revisions", crudHandler.Revisions)
+	router.GET("/api/content/{id}/revisions/{rev}", crudHandler.Revision)
+	router.POST("/api/content/{id}/revisions/{rev}/restore", crudHandler.RestoreRevision)
+	router.GET("/api/types", crudHandler.ListTypes)
+	router.POST("/api/types", crudHandler.CreateType)
+	router.GET("/api/types/{type}", crudHandler.GetType)
+	router.PUT("/api/types/{type}", crudHandler.UpdateType)
+	router.DELETE("/api/types/{type}", crudHandler.DeleteType)
+	router.GET("/api/types/{type}/items", crudHandler.ListItems)
+	router.POST("/api/types/{type}/items", crudHandler.CreateItem)
+	router.GET("/api/types/{type}/items/{id}", crudHandler.GetItem)
+	router.PUT("/api/types/{type}/items/{id}", crudHandler.UpdateItem)
+	router.DELETE("/api/types/{type}/items/{id}", crudHandler.DeleteItem)
+	router.GET("/api/tags", crudHandler.Tags)
+	router.GET("/api/categories", crudHandler.Categories)
+	router.GET("/api/media", crudHandler.ListMedia)
+	router.POST("/api/media", crudHandler.UploadMedia)
+	router.GET("/api/media/{id}", crudHandler.GetMedia)
+	router.PUT("/api/media/{id}", crudHandler.UpdateMedia)
+	router.DELETE("/api/media/{id}", crudHandler.DeleteMedia)
 
-	// HTML page handlers using templates (Now need initialContent for cloning)
-	pageHandler := handlers.NewPageHandler(sess, cfg, initialContent)
+	// HTML page handlers using templates
+	pageHandler := handlers.NewPageHandler(sess, cfg, backend, users, tokens, limiter, renderer, wf, types, taxonomies, mediaStore, blobs)
 	router.GET("/", pageHandler.Index) // Public route
 	// Login/Logout routes are now implemented
 	router.GET("/login", pageHandler.Login)      // Login form route
@@ -108,22 +244,55 @@ func main() {
 	// Protected routes (Middleware will handle protection)
 	router.GET("/content", pageHandler.List)
 	router.GET("/content/new", pageHandler.New)
+	router.GET("/content/trash", pageHandler.TrashPage)
+	router.GET("/content/review", pageHandler.ReviewQueue)
+	router.GET("/search", pageHandler.Search)
 	router.GET("/content/{id}", pageHandler.View)
 	router.GET("/content/{id}/edit", pageHandler.Edit)
-	router.GET("/admin", pageHandler.Admin)
+	router.GET("/types", pageHandler.TypesPage)
+	router.GET("/types/{type}", pageHandler.ItemsPage)
+	router.GET("/types/{type}/new", pageHandler.NewItem)
+	router.POST("/types/{type}/new", pageHandler.CreateItem)
+	router.GET("/types/{type}/{id}/edit", pageHandler.EditItem)
+	router.POST("/types/{type}/{id}/edit", pageHandler.UpdateItem)
+	router.POST("/types/{type}/{id}/delete", pageHandler.DeleteItem)
+	router.GET("/media", pageHandler.MediaPage)
+	router.GET("/media/{id}/{name}", pageHandler.ServeMedia) // Public route
+	router.GET("/admin", pageHandler.AdminUsers)
+	router.GET("/admin/users/new", pageHandler.NewUser)
+	router.POST("/admin/users", pageHandler.CreateUser)
+	router.GET("/admin/users/{username}/edit", pageHandler.EditUser)
+	router.POST("/admin/users/{username}", pageHandler.UpdateUser)
+	router.POST("/admin/users/{username}/delete", pageHandler.DeleteUser)
+	router.GET("/admin/taxonomies", pageHandler.Taxonomies)
+	router.POST("/admin/taxonomies/{taxonomy}", pageHandler.CreateTerm)
+	router.GET("/admin/taxonomies/{taxonomy}/{slug}/edit", pageHandler.EditTerm)
+	router.POST("/admin/taxonomies/{taxonomy}/{slug}", pageHandler.UpdateTerm)
+	router.POST("/admin/taxonomies/{taxonomy}/{slug}/delete", pageHandler.DeleteTerm)
 	router.GET("/settings", pageHandler.Settings)
+	router.POST("/settings/tokens", pageHandler.CreateToken)
+	router.POST("/settings/tokens/{id}/revoke", pageHandler.RevokeToken)
+	router.GET(cfg.PublicPrefix, pageHandler.PublicIndex)                       // Public route
+	router.GET(cfg.PublicPrefix+"/{slug}", pageHandler.PublicPost)              // Public route
+	router.GET(cfg.PublicPrefix+"/tag/{slug}", pageHandler.PublicTag)           // Public route
+	router.GET(cfg.PublicPrefix+"/category/{slug}", pageHandler.PublicCategory) // Public route
 	router.GET("/404", pageHandler.NotFound)
 	router.NotFound = pageHandler.NotFound // Keep NotFound accessible
 
-	// Import/Export handlers (pass session manager and config)
+	// Import/Export handlers
 	router.POST("/api/export", crudHandler.ExportJSON)
 	router.POST("/api/import", crudHandler.ImportJSON)
+	router.POST("/api/sandbox/reset", crudHandler.ResetSandbox)
 
 	// Authentication Middleware
 	// Note: static file handling might need adjustment depending on how they are served.
 	// If served via a separate handler before the router, AuthMiddleware might not see /static/ paths.
 	// Ensure public paths in AuthMiddleware match your routing setup.
-	authMiddleware := handlers.AuthMiddleware(router.Handler, sess, cfg)
+	authMiddleware := handlers.AuthMiddleware(router.Handler, sess, cfg, users, tokens)
+	// CSRF protection runs first so forged requests never reach a handler
+	csrfMiddleware := handlers.CSRFMiddleware(authMiddleware, sess, cfg)
+	// Request IDs come first so every response, errors included, carries one
+	requestIDMiddleware := handlers.RequestIDMiddleware(csrfMiddleware)
 
 	// Start time tracking (relevant if using timing middleware)
 	startTime := time.Now()
@@ -133,13 +302,13 @@ func main() {
 	// Placeholder for timing logic if re-introduced
 	_ = startTime // Use variables to avoid unused errors if timing is removed
 	_ = requestCount
-	_ = requestLock
+	_ = &requestLock
 	// trackTiming definition removed for now, can be added back if needed
 
 	// Start the server
 	server := &fasthttp.Server{
 		// Use the authentication middleware as the main handler
-		Handler: authMiddleware,
+		Handler: requestIDMiddleware,
 		// Handler: timedAuthHandler, // Uncomment if using timing middleware
 		Name: "cms",
 		// Fasthttp optimizations
@@ -148,12 +317,12 @@ func main() {
 		WriteBufferSize:    8192, // Increased from 4096
 		ReadTimeout:        cfg.ReadTimeout,
 		WriteTimeout:       cfg.WriteTimeout,
-		MaxRequestBodySize: 10 * 1024 * 1024, // 10MB
-		DisableKeepalive:   false,            // Enable keep-alive for connection reuse
-		MaxConnsPerIP:      100,              // Limit connections per IP to prevent abuse
-		TCPKeepalive:       true,             // Enable TCP keepalive
-		TCPKeepalivePeriod: 60 * time.Second, // Keep connections alive for 60 seconds
-		ReduceMemoryUsage:  true,             // Enable memory usage optimization
+		MaxRequestBodySize: max(10*1024*1024, int(cfg.MediaMaxSize)+1024*1024), // 10MB, or room for the largest upload
+		DisableKeepalive:   false,                                              // Enable keep-alive for connection reuse
+		MaxConnsPerIP:      100,                                                // Limit connections per IP to prevent abuse
+		TCPKeepalive:       true,                                               // Enable TCP keepalive
+		TCPKeepalivePeriod: 60 * time.Second,                                   // Keep connections alive for 60 seconds
+		ReduceMemoryUsage:  true,                                               // Enable memory usage optimization
 	}
 
 	log.Printf("Server starting on %s", cfg.Address)
@@ -182,3 +351,48 @@ func main() {
 		log.Fatalf("Server error: %v", err)
 	}
 }
+
+// bootstrapAdmin creates an admin account from AUTH_USER/AUTH_PASS when no
+// users exist yet. Later changes to these variables are ignored.
+func bootstrapAdmin(users *storage.UserStore, cfg *config.Config) error {
+	count, err := users.Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	if cfg.AuthUser == "" || cfg.AuthPass == "" {
+		log.Println("Warning: No users exist and AUTH_USER or AUTH_PASS environment variables are not set. Nobody will be able to log in.")
+		return nil
+	}
+
+	hash, err := auth.HashPassword(cfg.AuthPass)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if err := users.Create(models.User{
+		Username:     cfg.AuthUser,
+		PasswordHash: hash,
+		Role:         auth.RoleAdmin,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}); err != nil {
+		return err
+	}
+	log.Printf("Created initial admin user '%s' from AUTH_USER", cfg.AuthUser)
+	return nil
+}
+
+// cookieSameSite maps the configured SameSite mode to the fasthttp value.
+func cookieSameSite(mode string) fasthttp.CookieSameSite {
+	switch mode {
+	case "strict":
+		return fasthttp.CookieSameSiteStrictMode
+	case "none":
+		return fasthttp.CookieSameSiteNoneMode
+	default:
+		return fasthttp.CookieSameSiteLaxMode
+	}
+}
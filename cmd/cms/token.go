@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"cms/internal/auth"
+	"cms/internal/config"
+	"cms/internal/storage"
+)
+
+const tokenUsage = `Usage:
+  cms token create -user NAME -name LABEL -scopes content:read,content:write
+  cms token list [-user NAME]
+  cms token revoke ID
+
+Scopes: content:read, content:write, import, export, types:manage
+The server must be stopped, as the database is opened exclusively.
+`
+
+// runTokenCommand implements the `cms token` subcommands for managing API
+// tokens from the command line. It returns the process exit code.
+func runTokenCommand(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, tokenUsage)
+		return 2
+	}
+
+	db, err := storage.OpenDB(cfg.DBPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	tokens, err := storage.NewTokenStore(db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize API token store: %v\n", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "create":
+		users, err := storage.NewUserStore(db)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to initialize user store: %v\n", err)
+			return 1
+		}
+		return tokenCreate(users, tokens, args[1:])
+	case "list":
+		return tokenList(tokens, args[1:])
+	case "revoke":
+		return tokenRevoke(tokens, args[1:])
+	default:
+		fmt.Fprint(os.Stderr, tokenUsage)
+		return 2
+	}
+}
+
+func tokenCreate(users *storage.UserStore, tokens *storage.TokenStore, args []string) int {
+	fs := flag.NewFlagSet("token create", flag.ContinueOnError)
+	username := fs.String("user", "", "owner of the token")
+	name := fs.String("name", "", "label for the token")
+	scopeList := fs.String("scopes", string(auth.PermContentRead), "comma-separated scopes")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *username == "" || *name == "" {
+		fmt.Fprintln(os.Stderr, "Both -user and -name are required.")
+		return 2
+	}
+
+	user, err := users.Get(*username)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load user '%s': %v\n", *username, err)
+		return 1
+	}
+
+	var scopes []string
+	for _, scope := range strings.Split(*scopeList, ",") {
+		scope = strings.TrimSpace(scope)
+		if !auth.ValidScope(scope) {
+			fmt.Fprintf(os.Stderr, "Unknown scope '%s'.\n", scope)
+			return 2
+		}
+		if !auth.Can(user.Role, auth.Permission(scope)) {
+			fmt.Fprintf(os.Stderr, "Role '%s' cannot grant scope '%s'.\n", user.Role, scope)
+			return 2
+		}
+		scopes = append(scopes, scope)
+	}
+
+	token, raw, err := tokens.Issue(user.Username, *name, scopes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create token: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Created token %s for '%s'. It will not be shown again:\n", token.ID, user.Username)
+	fmt.Println(raw)
+	return 0
+}
+
+func tokenList(tokens *storage.TokenStore, args []string) int {
+	fs := flag.NewFlagSet("token list", flag.ContinueOnError)
+	username := fs.String("user", "", "only list tokens of this user")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	list, err := tokens.List(*username)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list tokens: %v\n", err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSER\tNAME\tSCOPES\tCREATED\tLAST USED")
+	for _, t := range list {
+		lastUsed := "never"
+		if !t.LastUsedAt.IsZero() {
+			lastUsed = t.LastUsedAt.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", t.ID, t.Username, t.Name,
+			strings.Join(t.Scopes, ","), t.CreatedAt.Format("2006-01-02 15:04"), lastUsed)
+	}
+	w.Flush()
+	return 0
+}
+
+func tokenRevoke(tokens *storage.TokenStore, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, tokenUsage)
+		return 2
+	}
+	if err := tokens.Delete(args[0], ""); err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			fmt.Fprintf(os.Stderr, "Token %s not found.\n", args[0])
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "Failed to revoke token: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Revoked token %s.\n", args[0])
+	return 0
+}
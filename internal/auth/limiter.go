@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// maxBackoffShift caps exponential backoff at lockDuration * 2^maxBackoffShift.
+const maxBackoffShift = 5
+
+// Attempt tracks failed logins for one limiter key.
+type Attempt struct {
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"last_failure"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// AttemptStore persists limiter state so lockouts survive restarts.
+type AttemptStore interface {
+	LoadAttempts() (map[string]Attempt, error)
+	SaveAttempt(key string, a Attempt) error
+	DeleteAttempt(key string) error
+}
+
+// Limiter throttles login attempts server-side. Failures are counted per key
+// (e.g. client IP and username); once a key reaches maxAttempts it is locked
+// for lockDuration, doubling with every further failure. A key is forgotten
+// after it has been quiet for twice its current lock duration.
+type Limiter struct {
+	mu           sync.Mutex
+	attempts     map[string]*Attempt
+	maxAttempts  int
+	lockDuration time.Duration
+	store        AttemptStore // nil for memory only
+	stop         chan struct{}
+}
+
+// NewLimiter creates a limiter, loading previous state from store if given.
+func NewLimiter(maxAttempts int, lockDuration time.Duration, store AttemptStore) (*Limiter, error) {
+	l := &Limiter{
+		attempts:     make(map[string]*Attempt),
+		maxAttempts:  maxAttempts,
+		lockDuration: lockDuration,
+		store:        store,
+		stop:         make(chan struct{}),
+	}
+	if store != nil {
+		saved, err := store.LoadAttempts()
+		if err != nil {
+			return nil, err
+		}
+		for key, a := range saved {
+			l.attempts[key] = &a
+		}
+	}
+	go l.sweepLoop()
+	return l, nil
+}
+
+// Check returns how long the longest lock among keys still lasts, or 0.
+func (l *Limiter) Check(keys ...string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	var wait time.Duration
+	for _, key := range keys {
+		if a, ok := l.attempts[key]; ok && a.LockedUntil.After(now) {
+			if d := a.LockedUntil.Sub(now); d > wait {
+				wait = d
+			}
+		}
+	}
+	return wait
+}
+
+// Fail records a failed attempt for every key. It returns the number of
+// attempts left before a lock and the lock imposed (0 if none).
+func (l *Limiter) Fail(keys ...string) (remaining int, lock time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	remaining = l.maxAttempts
+	for _, key := range keys {
+		a, ok := l.attempts[key]
+		if !ok || l.expired(a, now) {
+			a = &Attempt{}
+			l.attempts[key] = a
+		}
+		a.Failures++
+		a.LastFailure = now
+		if d := l.lockFor(a.Failures); d > 0 {
+			a.LockedUntil = now.Add(d)
+			if d > lock {
+				lock = d
+			}
+		}
+		if left := l.maxAttempts - a.Failures; left < remaining {
+			remaining = left
+		}
+		l.persist(key, a)
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, lock
+}
+
+// Reset clears the failures recorded for keys, e.g. after a successful login.
+func (l *Limiter) Reset(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if _, ok := l.attempts[key]; ok {
+			delete(l.attempts, key)
+			l.forget(key)
+		}
+	}
+}
+
+// Close stops the background sweeper.
+func (l *Limiter) Close() {
+	close(l.stop)
+}
+
+// lockFor returns the lock imposed after the given number of failures.
+func (l *Limiter) lockFor(failures int) time.Duration {
+	if failures < l.maxAttempts {
+		return 0
+	}
+	shift := failures - l.maxAttempts
+	if shift > maxBackoffShift {
+		shift = maxBackoffShift
+	}
+	return l.lockDuration << shift
+}
+
+// expired reports whether a has been quiet long enough to be forgotten.
+func (l *Limiter) expired(a *Attempt, now time.Time) bool {
+	window := l.lockFor(a.Failures)
+	if window == 0 {
+		window = l.lockDuration
+	}
+	return now.After(a.LockedUntil) && now.Sub(a.LastFailure) > 2*window
+}
+
+func (l *Limiter) persist(key string, a *Attempt) {
+	if l.store == nil {
+		return
+	}
+	if err := l.store.SaveAttempt(key, *a); err != nil {
+		log.Printf("Limiter: Error saving attempts for %s: %v", key, err)
+	}
+}
+
+func (l *Limiter) forget(key string) {
+	if l.store == nil {
+		return
+	}
+	if err := l.store.DeleteAttempt(key); err != nil {
+		log.Printf("Limiter: Error deleting attempts for %s: %v", key, err)
+	}
+}
+
+// sweepLoop periodically drops expired keys.
+func (l *Limiter) sweepLoop() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			l.sweep()
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+func (l *Limiter) sweep() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for key, a := range l.attempts {
+		if l.expired(a, now) {
+			delete(l.attempts, key)
+			l.forget(key)
+		}
+	}
+}
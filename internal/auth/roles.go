@@ -0,0 +1,50 @@
+package auth
+
+// Roles assignable to user accounts.
+const (
+	RoleAdmin  = "admin"  // Full access, including user management
+	RoleEditor = "editor" // Manages all content
+	RoleAuthor = "author" // Creates content and edits own items only
+	RoleViewer = "viewer" // Read-only access
+)
+
+// Roles lists all roles, from most to least privileged.
+var Roles = []string{RoleAdmin, RoleEditor, RoleAuthor, RoleViewer}
+
+// Permission names an action guarded by role checks.
+type Permission string
+
+// Permissions enforced by the middleware and handlers.
+const (
+	PermContentRead    Permission = "content:read"     // View content items
+	PermContentWrite   Permission = "content:write"    // Create items and edit own items
+	PermContentEditAny Permission = "content:edit_any" // Edit or delete items authored by others
+	PermImport         Permission = "import"           // Replace content from an export file
+	PermExport         Permission = "export"           // Download the content export
+	PermUsersManage    Permission = "users:manage"     // Manage user accounts
+	PermTypesManage    Permission = "types:manage"     // Define custom content types
+	PermTermsManage    Permission = "terms:manage"     // Manage tags and categories
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleAdmin:  {PermContentRead, PermContentWrite, PermContentEditAny, PermImport, PermExport, PermUsersManage, PermTypesManage, PermTermsManage},
+	RoleEditor: {PermContentRead, PermContentWrite, PermContentEditAny, PermImport, PermExport, PermTermsManage},
+	RoleAuthor: {PermContentRead, PermContentWrite},
+	RoleViewer: {PermContentRead},
+}
+
+// ValidRole reports whether role is a known role.
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Can reports whether role grants perm.
+func Can(role string, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// TokenPrefix marks API tokens so they are easy to recognize in configs and logs.
+const TokenPrefix = "cms_"
+
+// TokenScopes lists the permissions an API token can be granted.
+var TokenScopes = []Permission{PermContentRead, PermContentWrite, PermImport, PermExport, PermTypesManage}
+
+// ValidScope reports whether scope can be granted to an API token.
+func ValidScope(scope string) bool {
+	for _, s := range TokenScopes {
+		if string(s) == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateToken returns a new random API token and its hash. Only the hash
+// is stored; the token itself is shown to the user once.
+func GenerateToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token = TokenPrefix + hex.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hex SHA-256 hash used to look up a token.
+// Tokens are high-entropy, so a fast hash is sufficient.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	return hex.EncodeToString(sum[:])
+}
@@ -4,20 +4,47 @@ import (
 	"encoding/json"
 	"log"
 	"os"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// Storage modes selectable via STORAGE_MODE.
+const (
+	StorageModePersistent = "persistent" // Durable bbolt database at DBPath
+	StorageModeMemory     = "memory"     // Shared in-memory store, lost on restart
+	StorageModeSandbox    = "sandbox"    // Per-session copies of the seed content (public demo)
+)
+
 // Config holds the application configuration.
 type Config struct {
 	Address           string        `json:"address"`
 	Concurrency       int           `json:"concurrency"`
 	ReadTimeout       time.Duration `json:"read_timeout"`
 	WriteTimeout      time.Duration `json:"write_timeout"`
-	AuthUser          string        `json:"-"` // Loaded from ENV
-	AuthPass          string        `json:"-"` // Loaded from ENV
-	LoginLimitAttempt int           `json:"-"` // Loaded from ENV
-	LoginLockDuration time.Duration `json:"-"` // Loaded from ENV
+	DBPath            string        `json:"db_path"`
+	StorageMode       string        `json:"storage_mode"`
+	SandboxMaxItems   int           `json:"sandbox_max_items"`
+	SandboxTTL        time.Duration `json:"sandbox_ttl"`
+	RevisionLimit     int           `json:"revision_limit"`  // Revisions kept per item, 0 for unlimited
+	TrashRetention    time.Duration `json:"trash_retention"` // How long trashed items are kept, 0 to keep until purged by hand
+	PublicPrefix      string        `json:"public_prefix"`   // URL prefix of the public site, e.g. "/blog"
+	WorkflowFile      string        `json:"workflow_file"`   // JSON workflow definition, empty for the built-in one
+	MediaDir          string        `json:"media_dir"`       // Directory of uploaded files and their variants
+	MediaMaxSize      int64         `json:"media_max_size"`  // Largest accepted upload, in bytes
+	SessionCookieName string        `json:"session_cookie_name"`
+	SessionExpiration time.Duration `json:"session_expiration"`
+	SessionSecure     bool          `json:"session_secure"`
+	SessionSameSite   string        `json:"session_same_site"` // lax, strict or none
+	SessionDomain     string        `json:"session_domain"`
+	TrustProxy        bool          `json:"trust_proxy"`       // Take the client IP from X-Forwarded-For
+	HTMLAllowedTags   []string      `json:"html_allowed_tags"` // Extra HTML allowed in content, "tag" or "tag[attr|attr]"
+	AuthUser          string        `json:"-"`                 // Loaded from ENV, bootstraps the first admin
+	AuthPass          string        `json:"-"`                 // Loaded from ENV, bootstraps the first admin
+	LoginLimitAttempt int           `json:"-"`                 // Loaded from ENV
+	LoginLockDuration time.Duration `json:"-"`                 // Loaded from ENV
+	LoginPersist      bool          `json:"-"`                 // Loaded from ENV, keep login lockouts across restarts
 	// GCPercent   int           `json:"gc_percent"` // Removed
 	// MaxHeapSize int64         `json:"max_heap_size"` // Removed
 }
@@ -30,18 +57,26 @@ func Load() *Config {
 		Concurrency:       1024 * 16,
 		ReadTimeout:       5 * time.Second,
 		WriteTimeout:      10 * time.Second,
+		DBPath:            "data/cms.db",
+		StorageMode:       StorageModePersistent,
+		SandboxMaxItems:   50,
+		SandboxTTL:        24 * time.Hour,
+		RevisionLimit:     50,
+		TrashRetention:    30 * 24 * time.Hour,
+		PublicPrefix:      "/blog",
+		MediaDir:          "data/media",
+		MediaMaxSize:      8 << 20,
+		SessionCookieName: "cms_sessionid",
+		SessionExpiration: 24 * time.Hour,
+		SessionSameSite:   "lax",
 		LoginLimitAttempt: 5,             // Default login attempts
 		LoginLockDuration: 1 * time.Hour, // Default lockout duration
 	}
 
-	// Load Auth credentials from environment variables
+	// Load bootstrap admin credentials from environment variables
 	cfg.AuthUser = os.Getenv("AUTH_USER")
 	cfg.AuthPass = os.Getenv("AUTH_PASS")
 
-	if cfg.AuthUser == "" || cfg.AuthPass == "" {
-		log.Println("Warning: AUTH_USER or AUTH_PASS environment variables not set. Authentication will not work.")
-	}
-
 	// Load Login attempt limits from environment variables
 	if limitStr := os.Getenv("LOGIN_LIMIT_ATTEMPT"); limitStr != "" {
 		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
@@ -57,6 +92,13 @@ func Load() *Config {
 			log.Printf("Warning: Invalid LOGIN_LOCK_DURATION value '%s'. Using default: %v", lockDurStr, cfg.LoginLockDuration)
 		}
 	}
+	if persistStr := os.Getenv("LOGIN_PERSIST"); persistStr != "" {
+		if persist, err := strconv.ParseBool(persistStr); err == nil {
+			cfg.LoginPersist = persist
+		} else {
+			log.Printf("Warning: Invalid LOGIN_PERSIST value '%s'. Using default: %v", persistStr, cfg.LoginPersist)
+		}
+	}
 
 	// Check for PORT environment variable (common in cloud environments)
 	if port := os.Getenv("PORT"); port != "" {
@@ -90,6 +132,57 @@ func Load() *Config {
 				if fileCfg.WriteTimeout != 0 {
 					cfg.WriteTimeout = fileCfg.WriteTimeout
 				}
+				if fileCfg.DBPath != "" {
+					cfg.DBPath = fileCfg.DBPath
+				}
+				if fileCfg.StorageMode != "" {
+					cfg.StorageMode = fileCfg.StorageMode
+				}
+				if fileCfg.SandboxMaxItems != 0 {
+					cfg.SandboxMaxItems = fileCfg.SandboxMaxItems
+				}
+				if fileCfg.SandboxTTL != 0 {
+					cfg.SandboxTTL = fileCfg.SandboxTTL
+				}
+				if fileCfg.RevisionLimit != 0 {
+					cfg.RevisionLimit = fileCfg.RevisionLimit
+				}
+				if fileCfg.TrashRetention != 0 {
+					cfg.TrashRetention = fileCfg.TrashRetention
+				}
+				if fileCfg.PublicPrefix != "" {
+					cfg.PublicPrefix = fileCfg.PublicPrefix
+				}
+				if fileCfg.WorkflowFile != "" {
+					cfg.WorkflowFile = fileCfg.WorkflowFile
+				}
+				if fileCfg.MediaDir != "" {
+					cfg.MediaDir = fileCfg.MediaDir
+				}
+				if fileCfg.MediaMaxSize != 0 {
+					cfg.MediaMaxSize = fileCfg.MediaMaxSize
+				}
+				if fileCfg.SessionCookieName != "" {
+					cfg.SessionCookieName = fileCfg.SessionCookieName
+				}
+				if fileCfg.SessionExpiration != 0 {
+					cfg.SessionExpiration = fileCfg.SessionExpiration
+				}
+				if fileCfg.SessionSecure {
+					cfg.SessionSecure = true
+				}
+				if fileCfg.SessionSameSite != "" {
+					cfg.SessionSameSite = fileCfg.SessionSameSite
+				}
+				if fileCfg.SessionDomain != "" {
+					cfg.SessionDomain = fileCfg.SessionDomain
+				}
+				if fileCfg.TrustProxy {
+					cfg.TrustProxy = true
+				}
+				if len(fileCfg.HTMLAllowedTags) > 0 {
+					cfg.HTMLAllowedTags = fileCfg.HTMLAllowedTags
+				}
 			}
 		}
 	}
@@ -99,7 +192,150 @@ func Load() *Config {
 		cfg.Address = ":" + port
 	}
 
-	log.Printf("Config loaded: Address=%s, AuthUser=%s, Attempts=%d, Lockout=%v",
-		cfg.Address, cfg.AuthUser, cfg.LoginLimitAttempt, cfg.LoginLockDuration)
+	// Database location (ENV takes precedence over config.json)
+	if dbPath := os.Getenv("DB_PATH"); dbPath != "" {
+		cfg.DBPath = dbPath
+	}
+
+	// Storage mode and sandbox limits (ENV takes precedence over config.json)
+	if mode := os.Getenv("STORAGE_MODE"); mode != "" {
+		cfg.StorageMode = mode
+	}
+	switch cfg.StorageMode {
+	case StorageModePersistent, StorageModeMemory, StorageModeSandbox:
+	default:
+		log.Printf("Warning: Invalid STORAGE_MODE value '%s'. Using default: %s", cfg.StorageMode, StorageModePersistent)
+		cfg.StorageMode = StorageModePersistent
+	}
+	if maxStr := os.Getenv("SANDBOX_MAX_ITEMS"); maxStr != "" {
+		if maxItems, err := strconv.Atoi(maxStr); err == nil && maxItems >= 0 {
+			cfg.SandboxMaxItems = maxItems
+		} else {
+			log.Printf("Warning: Invalid SANDBOX_MAX_ITEMS value '%s'. Using default: %d", maxStr, cfg.SandboxMaxItems)
+		}
+	}
+	if ttlStr := os.Getenv("SANDBOX_TTL"); ttlStr != "" {
+		if ttl, err := time.ParseDuration(ttlStr); err == nil && ttl >= 0 {
+			cfg.SandboxTTL = ttl
+		} else {
+			log.Printf("Warning: Invalid SANDBOX_TTL value '%s'. Using default: %v", ttlStr, cfg.SandboxTTL)
+		}
+	}
+
+	// Revision history retention (ENV takes precedence over config.json)
+	if limitStr := os.Getenv("REVISION_LIMIT"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit >= 0 {
+			cfg.RevisionLimit = limit
+		} else {
+			log.Printf("Warning: Invalid REVISION_LIMIT value '%s'. Using default: %d", limitStr, cfg.RevisionLimit)
+		}
+	}
+	if cfg.RevisionLimit < 0 {
+		cfg.RevisionLimit = 0
+	}
+
+	// Trash retention (ENV takes precedence over config.json)
+	if retStr := os.Getenv("TRASH_RETENTION"); retStr != "" {
+		if ret, err := time.ParseDuration(retStr); err == nil && ret >= 0 {
+			cfg.TrashRetention = ret
+		} else {
+			log.Printf("Warning: Invalid TRASH_RETENTION value '%s'. Using default: %v", retStr, cfg.TrashRetention)
+		}
+	}
+
+	// Public site prefix (ENV takes precedence over config.json)
+	if prefix := os.Getenv("PUBLIC_PREFIX"); prefix != "" {
+		cfg.PublicPrefix = prefix
+	}
+	if !validPublicPrefix(cfg.PublicPrefix) {
+		log.Printf("Warning: Invalid PUBLIC_PREFIX value '%s'. Using default: /blog", cfg.PublicPrefix)
+		cfg.PublicPrefix = "/blog"
+	}
+
+	// Editorial workflow definition (ENV takes precedence over config.json)
+	if wf := os.Getenv("WORKFLOW_FILE"); wf != "" {
+		cfg.WorkflowFile = wf
+	}
+
+	// Media uploads (ENV takes precedence over config.json)
+	if dir := os.Getenv("MEDIA_DIR"); dir != "" {
+		cfg.MediaDir = dir
+	}
+	if sizeStr := os.Getenv("MEDIA_MAX_SIZE"); sizeStr != "" {
+		if size, err := strconv.ParseInt(sizeStr, 10, 64); err == nil && size > 0 {
+			cfg.MediaMaxSize = size
+		} else {
+			log.Printf("Warning: Invalid MEDIA_MAX_SIZE value '%s'. Using default: %d", sizeStr, cfg.MediaMaxSize)
+		}
+	}
+
+	// Session cookie settings (ENV takes precedence over config.json)
+	if name := os.Getenv("SESSION_COOKIE_NAME"); name != "" {
+		cfg.SessionCookieName = name
+	}
+	if expStr := os.Getenv("SESSION_EXPIRATION"); expStr != "" {
+		if exp, err := time.ParseDuration(expStr); err == nil && exp != 0 {
+			cfg.SessionExpiration = exp
+		} else {
+			log.Printf("Warning: Invalid SESSION_EXPIRATION value '%s'. Using default: %v", expStr, cfg.SessionExpiration)
+		}
+	}
+	if secureStr := os.Getenv("SESSION_SECURE"); secureStr != "" {
+		if secure, err := strconv.ParseBool(secureStr); err == nil {
+			cfg.SessionSecure = secure
+		} else {
+			log.Printf("Warning: Invalid SESSION_SECURE value '%s'. Using default: %v", secureStr, cfg.SessionSecure)
+		}
+	}
+	if sameSite := os.Getenv("SESSION_SAMESITE"); sameSite != "" {
+		cfg.SessionSameSite = sameSite
+	}
+	switch cfg.SessionSameSite {
+	case "lax", "strict", "none":
+	default:
+		log.Printf("Warning: Invalid SESSION_SAMESITE value '%s'. Using default: lax", cfg.SessionSameSite)
+		cfg.SessionSameSite = "lax"
+	}
+	if domain := os.Getenv("SESSION_DOMAIN"); domain != "" {
+		cfg.SessionDomain = domain
+	}
+
+	// Reverse proxy handling (ENV takes precedence over config.json)
+	if trustStr := os.Getenv("TRUST_PROXY"); trustStr != "" {
+		if trust, err := strconv.ParseBool(trustStr); err == nil {
+			cfg.TrustProxy = trust
+		} else {
+			log.Printf("Warning: Invalid TRUST_PROXY value '%s'. Using default: %v", trustStr, cfg.TrustProxy)
+		}
+	}
+
+	// Content HTML allowlist (ENV takes precedence over config.json)
+	if tags := os.Getenv("HTML_ALLOWED_TAGS"); tags != "" {
+		cfg.HTMLAllowedTags = strings.Split(tags, ",")
+	}
+
+	log.Printf("Config loaded: Address=%s, Storage=%s, DBPath=%s, AuthUser=%s, Attempts=%d, Lockout=%v",
+		cfg.Address, cfg.StorageMode, cfg.DBPath, cfg.AuthUser, cfg.LoginLimitAttempt, cfg.LoginLockDuration)
 	return cfg
 }
+
+// publicPrefixPattern matches one or more lower-case path segments.
+var publicPrefixPattern = regexp.MustCompile(`^(/[a-z0-9-]+)+$`)
+
+// reservedPrefixes are the first path segments used by the application.
+var reservedPrefixes = []string{"api", "content", "types", "media", "admin", "settings", "search", "login", "logout", "static", "404"}
+
+// validPublicPrefix reports whether prefix can serve the public site
+// without shadowing application routes.
+func validPublicPrefix(prefix string) bool {
+	if !publicPrefixPattern.MatchString(prefix) {
+		return false
+	}
+	first, _, _ := strings.Cut(prefix[1:], "/")
+	for _, r := range reservedPrefixes {
+		if first == r {
+			return false
+		}
+	}
+	return true
+}
@@ -71,6 +71,11 @@ func (r *Router) PUT(path string, handler fasthttp.RequestHandler) {
 	r.router.PUT(path, wrapHandler(handler, r.pool))
 }
 
+// PATCH registers a PATCH handler.
+func (r *Router) PATCH(path string, handler fasthttp.RequestHandler) {
+	r.router.PATCH(path, wrapHandler(handler, r.pool))
+}
+
 // DELETE registers a DELETE handler.
 func (r *Router) DELETE(path string, handler fasthttp.RequestHandler) {
 	r.router.DELETE(path, wrapHandler(handler, r.pool))
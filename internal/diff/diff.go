@@ -0,0 +1,125 @@
+// Package diff computes line-based differences between two texts.
+package diff
+
+import "strings"
+
+// maxCells caps the LCS table size (old lines * new lines). Larger inputs
+// fall back to a whole-text replacement instead of a minimal diff.
+const maxCells = 4_000_000
+
+// Op is the kind of change a line represents.
+type Op string
+
+// Line operations.
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+// Line is one line of a diff.
+type Line struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// Lines returns the line diff turning a into b, using a longest common
+// subsequence so unchanged lines are kept as Equal.
+func Lines(a, b string) []Line {
+	if a == "" && b == "" {
+		return nil
+	}
+	x, y := splitLines(a), splitLines(b)
+
+	// Trim the common prefix and suffix to keep the table small
+	pre := 0
+	for pre < len(x) && pre < len(y) && x[pre] == y[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(x)-pre && suf < len(y)-pre && x[len(x)-1-suf] == y[len(y)-1-suf] {
+		suf++
+	}
+
+	out := make([]Line, 0, len(x)+len(y))
+	for _, s := range x[:pre] {
+		out = append(out, Line{Equal, s})
+	}
+	out = append(out, middle(x[pre:len(x)-suf], y[pre:len(y)-suf])...)
+	for _, s := range x[len(x)-suf:] {
+		out = append(out, Line{Equal, s})
+	}
+	return out
+}
+
+// Changed reports whether a diff contains any insertion or deletion.
+func Changed(lines []Line) bool {
+	for _, l := range lines {
+		if l.Op != Equal {
+			return true
+		}
+	}
+	return false
+}
+
+// middle diffs the part of the texts between the common prefix and suffix.
+func middle(x, y []string) []Line {
+	out := make([]Line, 0, len(x)+len(y))
+	if len(x)*len(y) > maxCells {
+		for _, s := range x {
+			out = append(out, Line{Delete, s})
+		}
+		for _, s := range y {
+			out = append(out, Line{Insert, s})
+		}
+		return out
+	}
+
+	// lcs[i][j] is the LCS length of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			out = append(out, Line{Equal, x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, Line{Delete, x[i]})
+			i++
+		default:
+			out = append(out, Line{Insert, y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		out = append(out, Line{Delete, x[i]})
+	}
+	for ; j < len(y); j++ {
+		out = append(out, Line{Insert, y[j]})
+	}
+	return out
+}
+
+// splitLines splits text into lines, treating CRLF as LF and ignoring a
+// trailing newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
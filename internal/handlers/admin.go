@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"cms/internal/auth"
+	"cms/internal/models"
+	"cms/internal/storage"
+	"cms/internal/templates/pages"
+
+	"github.com/valyala/fasthttp"
+)
+
+// usernamePattern restricts usernames to URL-safe characters.
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{3,32}$`)
+
+// adminMessages maps ?message= keys to the flash text shown on /admin.
+var adminMessages = map[string]string{
+	"created": "User created.",
+	"updated": "User updated.",
+	"deleted": "User deleted.",
+}
+
+// AdminUsers handles GET /admin - lists user accounts.
+func (h *PageHandler) AdminUsers(ctx *fasthttp.RequestCtx) {
+	users, err := h.users.List()
+	if err != nil {
+		log.Printf("Admin Users: Error listing users: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	data := &models.UsersData{
+		BasePageData: h.newBasePageData(ctx, "Users", "Manage user accounts and roles"),
+		Users:        users,
+		Message:      adminMessages[string(ctx.QueryArgs().Peek("message"))],
+	}
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WriteAdminPage(ctx, data)
+}
+
+// NewUser handles GET /admin/users/new - renders the form to create a user.
+func (h *PageHandler) NewUser(ctx *fasthttp.RequestCtx) {
+	h.renderUserForm(ctx, models.User{Role: auth.RoleAuthor}, true, "")
+}
+
+// CreateUser handles POST /admin/users - creates a user account.
+func (h *PageHandler) CreateUser(ctx *fasthttp.RequestCtx) {
+	user := models.User{
+		Username: strings.TrimSpace(string(ctx.FormValue("username"))),
+		Role:     string(ctx.FormValue("role")),
+	}
+	password := string(ctx.FormValue("password"))
+
+	if !usernamePattern.MatchString(user.Username) {
+		h.renderUserForm(ctx, user, true, "Username must be 3-32 letters, numbers, dots, dashes or underscores.")
+		return
+	}
+	if !auth.ValidRole(user.Role) {
+		h.renderUserForm(ctx, user, true, "Unknown role.")
+		return
+	}
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		h.renderUserForm(ctx, user, true, err.Error())
+		return
+	}
+
+	now := time.Now().UTC()
+	user.PasswordHash = hash
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	if err := h.users.Create(user); err != nil {
+		if errors.Is(err, storage.ErrUserExists) {
+			h.renderUserForm(ctx, user, true, "A user with this name already exists.")
+			return
+		}
+		log.Printf("Admin CreateUser: Error creating user '%s': %v", user.Username, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Admin CreateUser: Created user '%s' (%s)", user.Username, user.Role)
+	ctx.Redirect("/admin?message=created", fasthttp.StatusSeeOther)
+}
+
+// EditUser handles GET /admin/users/{username}/edit - renders the edit form.
+func (h *PageHandler) EditUser(ctx *fasthttp.RequestCtx) {
+	user, ok := h.loadUser(ctx)
+	if !ok {
+		return
+	}
+	h.renderUserForm(ctx, user, false, "")
+}
+
+// UpdateUser handles POST /admin/users/{username} - changes a user's role
+// and, if given, password.
+func (h *PageHandler) UpdateUser(ctx *fasthttp.RequestCtx) {
+	user, ok := h.loadUser(ctx)
+	if !ok {
+		return
+	}
+
+	role := string(ctx.FormValue("role"))
+	if !auth.ValidRole(role) {
+		h.renderUserForm(ctx, user, false, "Unknown role.")
+		return
+	}
+	if user.Role == auth.RoleAdmin && role != auth.RoleAdmin {
+		if last, err := h.isLastAdmin(); err != nil {
+			log.Printf("Admin UpdateUser: Error counting admins: %v", err)
+			writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+			return
+		} else if last {
+			h.renderUserForm(ctx, user, false, "The last admin cannot be demoted.")
+			return
+		}
+	}
+	user.Role = role
+
+	if password := string(ctx.FormValue("password")); password != "" {
+		hash, err := auth.HashPassword(password)
+		if err != nil {
+			h.renderUserForm(ctx, user, false, err.Error())
+			return
+		}
+		user.PasswordHash = hash
+	}
+
+	user.UpdatedAt = time.Now().UTC()
+	if err := h.users.Update(user); err != nil {
+		log.Printf("Admin UpdateUser: Error updating user '%s': %v", user.Username, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Admin UpdateUser: Updated user '%s' (%s)", user.Username, user.Role)
+	ctx.Redirect("/admin?message=updated", fasthttp.StatusSeeOther)
+}
+
+// DeleteUser handles POST /admin/users/{username}/delete - removes a user.
+func (h *PageHandler) DeleteUser(ctx *fasthttp.RequestCtx) {
+	user, ok := h.loadUser(ctx)
+	if !ok {
+		return
+	}
+
+	if current, _ := currentUser(ctx); strings.EqualFold(current.Username, user.Username) {
+		h.renderUserForm(ctx, user, false, "You cannot delete your own account.")
+		return
+	}
+	if user.Role == auth.RoleAdmin {
+		if last, err := h.isLastAdmin(); err != nil {
+			log.Printf("Admin DeleteUser: Error counting admins: %v", err)
+			writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+			return
+		} else if last {
+			h.renderUserForm(ctx, user, false, "The last admin cannot be deleted.")
+			return
+		}
+	}
+
+	if err := h.users.Delete(user.Username); err != nil && !errors.Is(err, storage.ErrUserNotFound) {
+		log.Printf("Admin DeleteUser: Error deleting user '%s': %v", user.Username, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	if err := h.tokens.DeleteByUser(user.Username); err != nil {
+		log.Printf("Admin DeleteUser: Error revoking API tokens of '%s': %v", user.Username, err)
+	}
+
+	log.Printf("Admin DeleteUser: Deleted user '%s'", user.Username)
+	ctx.Redirect("/admin?message=deleted", fasthttp.StatusSeeOther)
+}
+
+// loadUser fetches the user named by the {username} route parameter,
+// responding with 404 when it does not exist.
+func (h *PageHandler) loadUser(ctx *fasthttp.RequestCtx) (models.User, bool) {
+	username, _ := ctx.UserValue("username").(string)
+	user, err := h.users.Get(username)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		h.NotFound(ctx)
+		return user, false
+	}
+	if err != nil {
+		log.Printf("Admin: Error loading user '%s': %v", username, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return user, false
+	}
+	return user, true
+}
+
+// isLastAdmin reports whether at most one admin account remains.
+func (h *PageHandler) isLastAdmin() (bool, error) {
+	users, err := h.users.List()
+	if err != nil {
+		return false, err
+	}
+	admins := 0
+	for _, u := range users {
+		if u.Role == auth.RoleAdmin {
+			admins++
+		}
+	}
+	return admins <= 1, nil
+}
+
+// renderUserForm renders the user create/edit form, with an optional error.
+func (h *PageHandler) renderUserForm(ctx *fasthttp.RequestCtx, user models.User, isNew bool, errorMessage string) {
+	title := "Add User"
+	if !isNew {
+		title = "Edit User: " + user.Username
+	}
+	data := &models.UserFormData{
+		BasePageData: h.newBasePageData(ctx, title, "Manage user account"),
+		User:         user,
+		IsNew:        isNew,
+		Roles:        auth.Roles,
+		ErrorMessage: errorMessage,
+	}
+	if errorMessage != "" {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+	}
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WriteUserFormPage(ctx, data)
+}
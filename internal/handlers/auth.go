@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"cms/internal/auth"
+	"cms/internal/models"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Request user values set by AuthMiddleware.
+const (
+	userCtxKey  = "user"      // The authenticated models.User
+	tokenCtxKey = "api_token" // The models.APIToken used, for bearer requests
+)
+
+// setCurrentUser attaches the authenticated user to the request.
+func setCurrentUser(ctx *fasthttp.RequestCtx, user models.User) {
+	ctx.SetUserValue(userCtxKey, user)
+}
+
+// currentUser returns the authenticated user set by AuthMiddleware.
+func currentUser(ctx *fasthttp.RequestCtx) (models.User, bool) {
+	user, ok := ctx.UserValue(userCtxKey).(models.User)
+	return user, ok
+}
+
+// setCurrentToken records the API token a request authenticated with.
+func setCurrentToken(ctx *fasthttp.RequestCtx, token models.APIToken) {
+	ctx.SetUserValue(tokenCtxKey, token)
+}
+
+// currentToken returns the API token the request authenticated with, if any.
+func currentToken(ctx *fasthttp.RequestCtx) (models.APIToken, bool) {
+	token, ok := ctx.UserValue(tokenCtxKey).(models.APIToken)
+	return token, ok
+}
+
+// can reports whether the current user's role grants perm.
+func can(ctx *fasthttp.RequestCtx, perm auth.Permission) bool {
+	user, ok := currentUser(ctx)
+	return ok && auth.Can(user.Role, perm)
+}
+
+// canModify reports whether the current user may edit or delete item:
+// editors and admins may modify anything, authors only their own items.
+func canModify(ctx *fasthttp.RequestCtx, item models.Content) bool {
+	return canModifyAuthored(ctx, item.Author)
+}
+
+// canModifyAuthored is canModify for anything with an author, such as
+// items of custom content types.
+func canModifyAuthored(ctx *fasthttp.RequestCtx, author string) bool {
+	user, ok := currentUser(ctx)
+	if !ok {
+		return false
+	}
+	if auth.Can(user.Role, auth.PermContentEditAny) {
+		return true
+	}
+	return auth.Can(user.Role, auth.PermContentWrite) && author != "" && strings.EqualFold(author, user.Username)
+}
+
+// clientIP returns the request's client address. With trustProxy set, the
+// last X-Forwarded-For entry is used when present: it is the one the proxy
+// appended, while earlier entries come from the client and can be forged.
+func clientIP(ctx *fasthttp.RequestCtx, trustProxy bool) string {
+	if trustProxy {
+		if xff := string(ctx.Request.Header.Peek("X-Forwarded-For")); xff != "" {
+			last := xff[strings.LastIndex(xff, ",")+1:]
+			if ip := net.ParseIP(strings.TrimSpace(last)); ip != nil {
+				return ip.String()
+			}
+		}
+	}
+	return ctx.RemoteIP().String()
+}
+
+// IsSecureRequest reports whether the request reached the server, or with
+// trustProxy set the reverse proxy in front of it, over HTTPS. Cookies are
+// only marked Secure on such requests.
+func IsSecureRequest(ctx *fasthttp.RequestCtx, trustProxy bool) bool {
+	if ctx.IsTLS() {
+		return true
+	}
+	return trustProxy && string(ctx.Request.Header.Peek("X-Forwarded-Proto")) == "https"
+}
+
+// loginKeys returns the limiter keys for a login attempt: the client IP's,
+// then the username's.
+func loginKeys(ip, username string) []string {
+	return []string{"ip:" + ip, "user:" + strings.ToLower(username)}
+}
+
+// lockoutMessage formats the message shown while logins are locked.
+func lockoutMessage(wait time.Duration) string {
+	if wait < time.Second {
+		wait = time.Second
+	}
+	return fmt.Sprintf("Too many failed login attempts. Please try again in %v.", wait.Round(time.Second))
+}
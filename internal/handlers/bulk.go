@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"cms/internal/models"
+	"cms/internal/storage"
+
+	"github.com/valyala/fasthttp"
+)
+
+// maxBulkOperations caps the operations in one bulk request.
+const maxBulkOperations = 100
+
+// Bulk operation kinds.
+const (
+	bulkCreate = "create" // Like POST /api/content
+	bulkUpdate = "update" // Like PUT /api/content/{id}
+	bulkDelete = "delete" // Like DELETE /api/content/{id}, i.e. trash
+	bulkStatus = "status" // Like POST /api/content/{id}/status
+)
+
+// bulkRequest is the body of POST /api/content/_bulk.
+type bulkRequest struct {
+	Operations []bulkOperation `json:"operations"`
+}
+
+type bulkOperation struct {
+	Op      string          `json:"op"`                 // See bulk* constants
+	ID      string          `json:"id,omitempty"`       // Target of update, delete and status
+	IfMatch string          `json:"if_match,omitempty"` // Optional ETag the target must still have
+	Item    *models.Content `json:"item,omitempty"`     // For create and update
+	Status  string          `json:"status,omitempty"`   // For status
+	Comment string          `json:"comment,omitempty"`  // For status
+}
+
+// bulkResult reports one operation, with the status code the same request
+// would have got on its own.
+type bulkResult struct {
+	Op     string `json:"op"`
+	ID     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	ETag   string `json:"etag,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type bulkResponse struct {
+	Committed bool         `json:"committed"`
+	Results   []bulkResult `json:"results"`
+}
+
+// bulkProblem is the problem details of a failed bulk request, extended
+// with the results of its operations.
+type bulkProblem struct {
+	models.Problem
+	bulkResponse
+}
+
+// bulkStep is a checked operation waiting for the batch to be saved.
+type bulkStep struct {
+	item     models.Content      // The item as the operation leaves it
+	write    *storage.BatchWrite // nil if nothing changes, e.g. trashing a trashed item
+	original *models.Content     // The stored item an update replaces, for its baseline revision
+	action   string              // Revision action, "" for none
+	comment  string
+}
+
+// Bulk handles POST /api/content/_bulk - runs a list of create, update,
+// delete and status operations as one transaction. Each operation is
+// checked as its single-item endpoint would check it and sees the
+// operations before it. If one fails, nothing is saved: the response has
+// that operation's status code, and the results mark the others 424
+// Failed Dependency.
+func (h *CRUDHandler) Bulk(ctx *fasthttp.RequestCtx) {
+	store, err := h.contentStore(ctx)
+	if err != nil {
+		log.Printf("CRUD Bulk: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	var req bulkRequest
+	if err := json.Unmarshal(ctx.PostBody(), &req); err != nil {
+		invalidJSON(ctx, err)
+		return
+	}
+	if len(req.Operations) == 0 {
+		writeError(ctx, "No operations", fasthttp.StatusBadRequest)
+		return
+	}
+	if len(req.Operations) > maxBulkOperations {
+		writeError(ctx, fmt.Sprintf("Too many operations; send at most %d per request", maxBulkOperations), fasthttp.StatusBadRequest)
+		return
+	}
+
+	view := &bulkView{store: store, pending: make(map[string]models.Content)}
+	results := make([]bulkResult, len(req.Operations))
+	steps := make([]bulkStep, len(req.Operations))
+	for i, op := range req.Operations {
+		results[i] = bulkResult{Op: op.Op, ID: op.ID}
+	}
+	for i, op := range req.Operations {
+		step, err := h.prepareBulk(ctx, view, op)
+		if err != nil {
+			bulkFailed(ctx, results, i, err)
+			return
+		}
+		if step.write != nil {
+			step.item = step.write.Item
+			if !step.write.Create {
+				step.item.Version++ // As the store will save it
+			}
+			view.pending[step.item.ID] = step.item
+		}
+		steps[i] = step
+	}
+
+	var writes []storage.BatchWrite
+	var writeOps []int // Operation index of each write
+	for i, step := range steps {
+		if step.write != nil {
+			writes = append(writes, *step.write)
+			writeOps = append(writeOps, i)
+		}
+	}
+	if len(writes) > 0 {
+		if err := store.Batch(writes); err != nil {
+			var batchErr *storage.BatchError
+			if !errors.As(err, &batchErr) {
+				respondError(ctx, "CRUD Bulk", fmt.Errorf("saving batch: %w", err))
+				return
+			}
+			i := writeOps[batchErr.Index]
+			bulkFailed(ctx, results, i, storeError(batchErr.Err, writes[batchErr.Index].Item))
+			return
+		}
+	}
+
+	for i, step := range steps {
+		if step.write != nil {
+			if step.original != nil {
+				recordBaseline(store, *step.original)
+			}
+			if step.action != "" {
+				recordRevision(ctx, store, step.item, step.action, step.comment)
+			}
+			h.wakeScheduler(step.item)
+		}
+		results[i].ID = step.item.ID
+		results[i].ETag = step.item.ETag()
+		results[i].Status = fasthttp.StatusNoContent
+		if req.Operations[i].Op == bulkCreate {
+			results[i].Status = fasthttp.StatusCreated
+		}
+	}
+
+	log.Printf("CRUD Bulk: Applied %d operations (%d writes)", len(steps), len(writes))
+	writeJSON(ctx, "CRUD Bulk", bulkResponse{Committed: true, Results: results})
+}
+
+// prepareBulk checks one operation against the store as the operations
+// before it leave it, and returns the write it makes.
+func (h *CRUDHandler) prepareBulk(ctx *fasthttp.RequestCtx, view *bulkView, op bulkOperation) (bulkStep, error) {
+	switch op.Op {
+	case bulkCreate, bulkUpdate, bulkDelete, bulkStatus:
+	default:
+		return bulkStep{}, &requestError{status: fasthttp.StatusBadRequest, msg: fmt.Sprintf("Unknown op '%s'; use create, update, delete or status", op.Op)}
+	}
+	if op.Op == bulkCreate {
+		if op.Item == nil {
+			return bulkStep{}, &requestError{status: fasthttp.StatusBadRequest, msg: "Missing item"}
+		}
+		id, err := generateID()
+		if err != nil {
+			return bulkStep{}, fmt.Errorf("generating ID: %w", err)
+		}
+		item := *op.Item
+		item.ID = id
+		if err := h.prepareCreate(ctx, view, &item); err != nil {
+			return bulkStep{}, err
+		}
+		return bulkStep{write: &storage.BatchWrite{Item: item, Create: true}, action: models.RevisionCreate}, nil
+	}
+
+	if op.ID == "" {
+		return bulkStep{}, &requestError{status: fasthttp.StatusBadRequest, msg: "Missing or invalid content ID"}
+	}
+	current, err := view.Get(op.ID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return bulkStep{}, &requestError{status: fasthttp.StatusNotFound, msg: "Content not found"}
+	}
+	if err != nil {
+		return bulkStep{}, fmt.Errorf("getting content for id %s: %w", op.ID, err)
+	}
+	if !canModify(ctx, current) {
+		return bulkStep{}, &requestError{status: fasthttp.StatusForbidden, msg: "Forbidden"}
+	}
+	if op.IfMatch != "" && !etagMatches(op.IfMatch, current.ETag(), false) {
+		return bulkStep{}, errEditConflict
+	}
+	_, staged := view.pending[op.ID]
+
+	if op.Op == bulkDelete {
+		if current.Trashed() {
+			return bulkStep{item: current}, nil
+		}
+		step := bulkStep{action: models.RevisionTrash}
+		if !staged {
+			step.original = &current
+		}
+		item := current
+		item.DeletedAt = time.Now().UTC()
+		item.UpdatedAt = item.DeletedAt
+		step.write = &storage.BatchWrite{Item: item}
+		return step, nil
+	}
+	if current.Trashed() {
+		return bulkStep{}, &requestError{status: fasthttp.StatusConflict, msg: "Content is in the trash; restore it first"}
+	}
+
+	step := bulkStep{action: models.RevisionStatus, comment: strings.TrimSpace(op.Comment)}
+	item := current
+	if op.Op == bulkUpdate {
+		if op.Item == nil {
+			return bulkStep{}, &requestError{status: fasthttp.StatusBadRequest, msg: "Missing item"}
+		}
+		item = *op.Item
+		if err := h.prepareUpdate(ctx, view, current, &item); err != nil {
+			return bulkStep{}, err
+		}
+		step.action, step.comment = models.RevisionUpdate, ""
+		if !staged {
+			step.original = &current
+		}
+	} else if err := h.prepareTransition(ctx, &item, strings.TrimSpace(op.Status), step.comment); err != nil {
+		return bulkStep{}, err
+	}
+	step.write = &storage.BatchWrite{Item: item}
+	return step, nil
+}
+
+// bulkFailed answers a bulk request whose operation i failed with err:
+// nothing was saved, so the other operations are marked 424. The response
+// describes the failure as problem details, with the results added.
+func bulkFailed(ctx *fasthttp.RequestCtx, results []bulkResult, i int, err error) {
+	var reqErr *requestError
+	if !errors.As(err, &reqErr) {
+		respondError(ctx, "CRUD Bulk", fmt.Errorf("operation %d: %w", i, err))
+		return
+	}
+	for j := range results {
+		results[j].Status = fasthttp.StatusFailedDependency
+		results[j].Error = fmt.Sprintf("Not saved: operation %d failed", i)
+	}
+	results[i].Status = reqErr.status
+	results[i].Error = reqErr.msg
+	p := completeProblem(ctx, reqErr.problem())
+	p.Detail = fmt.Sprintf("Operation %d failed: %s", i, p.Detail)
+	sendProblem(ctx, p.Status, bulkProblem{p, bulkResponse{Results: results}})
+}
+
+// bulkView is the content store as the operations checked so far will
+// leave it once saved.
+type bulkView struct {
+	store   storage.ContentStore
+	pending map[string]models.Content // Items written by earlier operations, as they will be saved
+}
+
+// Get returns the item as the earlier operations leave it.
+func (v *bulkView) Get(id string) (models.Content, error) {
+	if item, ok := v.pending[id]; ok {
+		return item, nil
+	}
+	return v.store.Get(id)
+}
+
+// GetBySlug implements slugLookup, so slugs generated for new items avoid
+// those claimed earlier in the batch.
+func (v *bulkView) GetBySlug(slug string) (models.Content, error) {
+	for _, item := range v.pending {
+		if item.Slug == slug {
+			return item, nil
+		}
+	}
+	return v.store.GetBySlug(slug)
+}
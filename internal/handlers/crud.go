@@ -5,13 +5,23 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"mime"
 	"time"
 
 	"cms/internal/config"
+	"cms/internal/media"
 	"cms/internal/models"
+	"cms/internal/patch"
+	"cms/internal/render"
+	"cms/internal/sanitize"
+	"cms/internal/slug"
+	"cms/internal/storage"
+	"cms/internal/validate"
+	"cms/internal/workflow"
 
 	session "github.com/fasthttp/session/v2"
 	"github.com/valyala/fasthttp"
@@ -20,329 +30,490 @@ import (
 
 // CRUDHandler handles API requests for content management.
 type CRUDHandler struct {
-	sess           *session.Session
-	cfg            *config.Config
-	initialContent map[string]models.Content // Added initial content map
-	parserPool     fastjson.ParserPool
+	storeResolver
+	cfg        *config.Config
+	sanitizer  *sanitize.Sanitizer
+	renderer   *render.Renderer
+	scheduler  *storage.Scheduler // Woken when a save changes a schedule, may be nil
+	workflow   *workflow.Workflow
+	types      *storage.TypeStore
+	taxonomies *storage.TaxonomyStore
+	media      *storage.MediaStore
+	blobs      media.BlobStore
+	parserPool fastjson.ParserPool
 }
 
 // NewCRUDHandler creates a new CRUD handler.
-func NewCRUDHandler(sess *session.Session, cfg *config.Config, initialContent map[string]models.Content) *CRUDHandler {
+func NewCRUDHandler(sess *session.Session, cfg *config.Config, backend storage.Backend, sanitizer *sanitize.Sanitizer, renderer *render.Renderer, scheduler *storage.Scheduler, wf *workflow.Workflow, types *storage.TypeStore, taxonomies *storage.TaxonomyStore, mediaStore *storage.MediaStore, blobs media.BlobStore) *CRUDHandler {
 	return &CRUDHandler{
-		sess:           sess,
-		cfg:            cfg,
-		initialContent: initialContent,
+		storeResolver: newStoreResolver(sess, backend, cfg),
+		cfg:           cfg,
+		sanitizer:     sanitizer,
+		renderer:      renderer,
+		scheduler:     scheduler,
+		workflow:      wf,
+		types:         types,
+		taxonomies:    taxonomies,
+		media:         mediaStore,
+		blobs:         blobs,
 		// parserPool is implicitly initialized
 	}
 }
 
-// Helper function to get user's content map from session or initialize it
-func (h *CRUDHandler) getUserContent(ctx *fasthttp.RequestCtx) (map[string]models.Content, error) {
-	store, err := h.sess.Get(ctx)
+// List handles GET /api/content - lists one page of content items outside
+// the trash (or inside it with ?trashed=true), filtered and sorted by the
+// query parameters. A `Link: <...>; rel="next"` header points to the next page.
+func (h *CRUDHandler) List(ctx *fasthttp.RequestCtx) {
+	query, err := parseListQuery(ctx.QueryArgs())
 	if err != nil {
-		return nil, fmt.Errorf("failed to get session: %w", err)
-	}
-
-	contentData := store.Get("user_content")
-	if contentData == nil {
-		// Not initialized, clone initial data and serialize to JSON
-		log.Println("getUserContent (CRUD): Initializing user content in session")
-		userContent := make(map[string]models.Content, len(h.initialContent))
-		for k, v := range h.initialContent {
-			userContent[k] = v // Shallow copy is okay if Content struct fields are simple types or immutable
-		}
-		// Serialize content to JSON bytes for storage
-		jsonBytes, err := json.Marshal(userContent)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal content for session: %w", err)
-		}
-		// Store the JSON rather than direct object
-		store.Set("user_content", jsonBytes)
-		// We need to save the session *now* so subsequent gets in the same request see it
-		if err := h.sess.Save(ctx, store); err != nil {
-			return nil, fmt.Errorf("failed to save session after initializing content: %w", err)
-		}
-		return userContent, nil
-	}
-
-	// Now handle different types that might be in session
-	var userContent map[string]models.Content
-
-	switch v := contentData.(type) {
-	case []byte:
-		// This is our expected format - JSON bytes
-		if err := json.Unmarshal(v, &userContent); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal content bytes from session: %w", err)
-		}
-	case string:
-		// Handle if somehow stored as string
-		if err := json.Unmarshal([]byte(v), &userContent); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal content string from session: %w", err)
-		}
-	case map[string]interface{}:
-		// Handle case where session serialization might change types
-		log.Println("getUserContent (CRUD): Attempting conversion from map[string]interface{}")
-		b, err := json.Marshal(v)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal map[string]interface{} for conversion: %w", err)
-		}
-		if err := json.Unmarshal(b, &userContent); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal into map[string]models.Content: %w", err)
-		}
-		// Update session with bytes to prevent future type conversions
-		jsonBytes, err := json.Marshal(userContent)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal content for session update: %w", err)
-		}
-		store.Set("user_content", jsonBytes)
-		if err := h.sess.Save(ctx, store); err != nil {
-			log.Printf("Warning: failed to update session after type conversion: %v", err)
-			// Non-fatal
-		}
-	default:
-		return nil, fmt.Errorf("unexpected type for user_content in session: %T", contentData)
+		writeError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
 	}
-
-	return userContent, nil
-}
-
-// Helper function to save user's content map back to session
-func (h *CRUDHandler) saveUserContent(ctx *fasthttp.RequestCtx, userContent map[string]models.Content) error {
-	store, err := h.sess.Get(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get session for saving: %w", err)
+	if err := expandCategories(h.taxonomies, &query); err != nil {
+		log.Printf("CRUD List: Error expanding category filter: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
 	}
 
-	// Serialize to JSON bytes instead of storing the object directly
-	jsonBytes, err := json.Marshal(userContent)
+	store, err := h.contentStore(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to marshal content map for session: %w", err)
+		log.Printf("CRUD List: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
 	}
 
-	store.Set("user_content", jsonBytes)
-	if err := h.sess.Save(ctx, store); err != nil {
-		return fmt.Errorf("failed to save session: %w", err)
+	result, err := store.Query(query)
+	if queryError(err) {
+		writeError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
 	}
-	return nil
-}
-
-// List handles GET /api/content - lists all content items for the user.
-func (h *CRUDHandler) List(ctx *fasthttp.RequestCtx) {
-	userContent, err := h.getUserContent(ctx)
 	if err != nil {
-		log.Printf("CRUD List: Error getting user content: %v", err)
-		ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+		log.Printf("CRUD List: Error listing content: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 		return
 	}
+	contents := result.Items
 
-	// Convert map to slice for response
-	contents := make([]models.Content, 0, len(userContent))
-	for _, item := range userContent {
-		contents = append(contents, item)
+	if result.NextCursor != "" {
+		ctx.Response.Header.Set("Link", "<"+listURL(ctx, "/api/content", result.NextCursor)+`>; rel="next"`)
 	}
-	// TODO: Add sorting if needed
 
 	ctx.SetContentType("application/json; charset=utf-8")
 	if err := json.NewEncoder(ctx).Encode(contents); err != nil {
 		log.Printf("CRUD List: Error encoding content list: %v", err)
 		if !ctx.Response.Header.IsHTTP11() {
-			ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+			writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 		}
 	}
 }
 
-// Get handles GET /api/content/{id} - retrieves a specific content item for the user.
+// Get handles GET /api/content/{id} - retrieves a specific content item.
 func (h *CRUDHandler) Get(ctx *fasthttp.RequestCtx) {
 	id, ok := ctx.UserValue("id").(string)
 	if !ok || id == "" {
-		ctx.Error("Missing or invalid content ID", fasthttp.StatusBadRequest)
+		writeError(ctx, "Missing or invalid content ID", fasthttp.StatusBadRequest)
 		return
 	}
 
-	userContent, err := h.getUserContent(ctx)
+	store, err := h.contentStore(ctx)
 	if err != nil {
-		log.Printf("CRUD Get: Error getting user content for id %s: %v", id, err)
-		ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+		log.Printf("CRUD Get: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 		return
 	}
 
-	item, found := userContent[id]
-	if !found {
-		ctx.Error("Content not found", fasthttp.StatusNotFound)
+	item, err := store.Get(id)
+	if errors.Is(err, storage.ErrNotFound) {
+		writeError(ctx, "Content not found", fasthttp.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("CRUD Get: Error getting content for id %s: %v", id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 		return
 	}
 
+	ctx.Response.Header.Set(fasthttp.HeaderETag, item.ETag())
+	if notModified(ctx, item) {
+		return
+	}
 	ctx.SetContentType("application/json; charset=utf-8")
 	if err := json.NewEncoder(ctx).Encode(item); err != nil {
 		log.Printf("CRUD Get: Error encoding item %s: %v", id, err)
 		if !ctx.Response.Header.IsHTTP11() {
-			ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+			writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 		}
 	}
 }
 
-// Create handles POST /api/content - creates a new content item in the user's session.
+// Create handles POST /api/content - creates a new content item.
 func (h *CRUDHandler) Create(ctx *fasthttp.RequestCtx) {
-	userContent, err := h.getUserContent(ctx)
+	store, err := h.contentStore(ctx)
 	if err != nil {
-		log.Printf("CRUD Create: Error getting user content: %v", err)
-		ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
-		return
-	}
-
-	// Check limit
-	if len(userContent) >= 50 {
-		log.Printf("CRUD Create: User content limit (50) reached.")
-		ctx.Error("Content limit reached. Please delete items before adding more.", fasthttp.StatusConflict) // 409 Conflict
+		log.Printf("CRUD Create: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 		return
 	}
 
 	id, err := generateID()
 	if err != nil {
 		log.Printf("CRUD Create: Error generating ID: %v", err)
-		ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 		return
 	}
 
 	body := ctx.PostBody()
 	if len(body) == 0 {
-		ctx.Error("Request body is empty", fasthttp.StatusBadRequest)
+		writeError(ctx, "Request body is empty", fasthttp.StatusBadRequest)
 		return
 	}
 
 	var newItem models.Content
 	if err := json.Unmarshal(body, &newItem); err != nil {
-		ctx.Error("Invalid JSON data: "+err.Error(), fasthttp.StatusBadRequest)
+		invalidJSON(ctx, err)
 		return
 	}
 
-	// Set mandatory fields
-	now := time.Now().UTC()
 	newItem.ID = id
-	newItem.CreatedAt = now
-	newItem.UpdatedAt = now
-	if newItem.Status == "" {
-		newItem.Status = "draft"
+	if err := h.prepareCreate(ctx, store, &newItem); err != nil {
+		respondError(ctx, "CRUD Create", err)
+		return
 	}
-	// TODO: Add more validation
-
-	userContent[id] = newItem
 
-	if err := h.saveUserContent(ctx, userContent); err != nil {
-		log.Printf("CRUD Create: Error saving user content for id %s: %v", id, err)
-		ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+	if err := store.Create(newItem); err != nil {
+		respondError(ctx, "CRUD Create", storeError(err, newItem))
 		return
 	}
+	recordRevision(ctx, store, newItem, models.RevisionCreate, "")
+	h.wakeScheduler(newItem)
 
+	ctx.Response.Header.Set(fasthttp.HeaderETag, newItem.ETag())
 	ctx.SetContentType("application/json; charset=utf-8")
 	ctx.SetStatusCode(fasthttp.StatusCreated)
 	fmt.Fprintf(ctx, `{"id":"%s"}`, id)
 }
 
-// Update handles PUT /api/content/{id} - updates an item in the user's session.
+// Update handles PUT /api/content/{id} - replaces an existing item. Fields
+// the client omits are cleared; use Patch to change only some of them.
 func (h *CRUDHandler) Update(ctx *fasthttp.RequestCtx) {
-	id, ok := ctx.UserValue("id").(string)
-	if !ok || id == "" {
-		ctx.Error("Missing or invalid content ID", fasthttp.StatusBadRequest)
+	store, originalItem, ok := h.loadEditable(ctx, "CRUD Update")
+	if !ok {
 		return
 	}
 
-	userContent, err := h.getUserContent(ctx)
-	if err != nil {
-		log.Printf("CRUD Update: Error getting user content for id %s: %v", id, err)
-		ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+	body := ctx.PostBody()
+	if len(body) == 0 {
+		writeError(ctx, "Request body is empty", fasthttp.StatusBadRequest)
+		return
+	}
+
+	var updatedItem models.Content
+	if err := json.Unmarshal(body, &updatedItem); err != nil {
+		invalidJSON(ctx, err)
 		return
 	}
+	h.saveUpdate(ctx, store, originalItem, updatedItem, "CRUD Update")
+}
 
-	_, found := userContent[id]
-	if !found {
-		ctx.Error("Content not found", fasthttp.StatusNotFound)
+// Patch handles PATCH /api/content/{id} - changes some fields of an item.
+// The body is a JSON Merge Patch or a JSON Patch, chosen by Content-Type,
+// applied to the item as Get returns it.
+func (h *CRUDHandler) Patch(ctx *fasthttp.RequestCtx) {
+	store, originalItem, ok := h.loadEditable(ctx, "CRUD Patch")
+	if !ok {
 		return
 	}
 
 	body := ctx.PostBody()
 	if len(body) == 0 {
-		ctx.Error("Request body is empty", fasthttp.StatusBadRequest)
+		writeError(ctx, "Request body is empty", fasthttp.StatusBadRequest)
+		return
+	}
+
+	doc, err := json.Marshal(originalItem)
+	if err != nil {
+		log.Printf("CRUD Patch: Error encoding item %s: %v", originalItem.ID, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	contentType, _, _ := mime.ParseMediaType(string(ctx.Request.Header.ContentType()))
+	var patched []byte
+	switch contentType {
+	case patch.MergePatchType:
+		patched, err = patch.Merge(doc, body)
+	case patch.JSONPatchType:
+		patched, err = patch.Apply(doc, body)
+	default:
+		writeError(ctx, "Unsupported Content-Type; use "+patch.MergePatchType+" or "+patch.JSONPatchType, fasthttp.StatusUnsupportedMediaType)
+		ctx.Response.Header.Set("Accept-Patch", patch.MergePatchType+", "+patch.JSONPatchType)
+		return
+	}
+	switch {
+	case errors.Is(err, patch.ErrInvalid):
+		writeError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	case errors.Is(err, patch.ErrTestFailed):
+		writeError(ctx, err.Error(), fasthttp.StatusConflict)
+		return
+	case errors.Is(err, patch.ErrNotApplicable):
+		writeError(ctx, err.Error(), fasthttp.StatusUnprocessableEntity)
+		return
+	case err != nil:
+		log.Printf("CRUD Patch: Error patching item %s: %v", originalItem.ID, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 		return
 	}
 
 	var updatedItem models.Content
-	if err := json.Unmarshal(body, &updatedItem); err != nil {
-		ctx.Error("Invalid JSON data: "+err.Error(), fasthttp.StatusBadRequest)
+	if err := json.Unmarshal(patched, &updatedItem); err != nil {
+		p := models.Problem{Type: models.ProblemValidation, Status: fasthttp.StatusUnprocessableEntity, Detail: "The patched item is invalid"}
+		if detail, field := jsonErrorDetail(err); field != "" {
+			p.Errors = []models.FieldError{{Field: field, Detail: detail}}
+		}
+		writeProblem(ctx, p)
 		return
 	}
+	h.saveUpdate(ctx, store, originalItem, updatedItem, "CRUD Patch")
+}
+
+// loadEditable fetches the item named by the {id} route parameter for an
+// update, like loadModifiable, and also checks If-Match and that the item
+// is not in the trash.
+func (h *CRUDHandler) loadEditable(ctx *fasthttp.RequestCtx, logPrefix string) (storage.ContentStore, models.Content, bool) {
+	store, item, ok := h.loadModifiable(ctx, logPrefix)
+	if !ok || !checkIfMatch(ctx, item) {
+		return nil, item, false
+	}
+	if item.Trashed() {
+		writeError(ctx, "Content is in the trash; restore it first", fasthttp.StatusConflict)
+		return nil, item, false
+	}
+	return store, item, true
+}
 
-	// Preserve original CreatedAt, ensure ID matches, set UpdatedAt
-	originalItem := userContent[id]
-	updatedItem.ID = id                            // Ensure ID is correct
-	updatedItem.CreatedAt = originalItem.CreatedAt // Keep original creation time
-	updatedItem.UpdatedAt = time.Now().UTC()
-	// TODO: More validation
+// saveUpdate validates updatedItem as the new version of originalItem and
+// stores it, responding with 204 and the new ETag.
+func (h *CRUDHandler) saveUpdate(ctx *fasthttp.RequestCtx, store storage.ContentStore, originalItem, updatedItem models.Content, logPrefix string) {
+	if err := h.prepareUpdate(ctx, store, originalItem, &updatedItem); err != nil {
+		respondError(ctx, logPrefix, err)
+		return
+	}
 
-	userContent[id] = updatedItem
+	recordBaseline(store, originalItem)
 
-	if err := h.saveUserContent(ctx, userContent); err != nil {
-		log.Printf("CRUD Update: Error saving user content for id %s: %v", id, err)
-		ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+	if err := store.Update(updatedItem); err != nil {
+		respondError(ctx, logPrefix, storeError(err, updatedItem))
 		return
 	}
+	updatedItem.Version++
+	recordRevision(ctx, store, updatedItem, models.RevisionUpdate, "")
+	h.wakeScheduler(updatedItem)
 
+	ctx.Response.Header.Set(fasthttp.HeaderETag, updatedItem.ETag())
 	ctx.SetStatusCode(fasthttp.StatusNoContent)
 }
 
-// Delete handles DELETE /api/content/{id} - deletes an item from the user's session.
-func (h *CRUDHandler) Delete(ctx *fasthttp.RequestCtx) {
-	id, ok := ctx.UserValue("id").(string)
-	if !ok || id == "" {
-		ctx.Error("Missing or invalid content ID", fasthttp.StatusBadRequest)
-		return
+// prepareCreate fills in the server-set fields of a new item and checks
+// it, settling its schedule, terms and slug. item.ID must be set.
+func (h *CRUDHandler) prepareCreate(ctx *fasthttp.RequestCtx, slugs slugLookup, item *models.Content) error {
+	// Set mandatory fields
+	now := time.Now().UTC()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+	item.Version = 1
+	if item.Status == "" {
+		item.Status = h.workflow.Initial
+	}
+	if user, ok := currentUser(ctx); ok {
+		item.Author = user.Username
+	}
+	check := *item
+	check.CreatedAt = time.Time{} // Stamped just now, so posts may be backdated
+	if err := h.validateItem(check); err != nil {
+		return err
 	}
+	if err := applySchedule(item, models.Content{}, now); err != nil {
+		return invalidField(scheduleField(err), err)
+	}
+	if err := h.transitionError(ctx, h.workflow.Initial, item.Status, ""); err != nil {
+		return err
+	}
+	return h.prepareSave(slugs, item, "")
+}
 
-	userContent, err := h.getUserContent(ctx)
-	if err != nil {
-		log.Printf("CRUD Delete: Error getting user content for id %s: %v", id, err)
-		ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
-		return
+// prepareUpdate makes updated the next version of original, keeping the
+// fields an edit may not change, and checks it like prepareCreate.
+func (h *CRUDHandler) prepareUpdate(ctx *fasthttp.RequestCtx, slugs slugLookup, original models.Content, updated *models.Content) error {
+	// Preserve original CreatedAt and Author, ensure ID matches, set UpdatedAt
+	updated.ID = original.ID               // Ensure ID is correct
+	updated.CreatedAt = original.CreatedAt // Keep original creation time
+	updated.Author = original.Author       // Ownership does not change on edit
+	updated.Version = original.Version     // The store rejects the save if another one got in first
+	updated.UpdatedAt = time.Now().UTC()
+	check := *updated
+	if check.PublishedAt.Equal(original.PublishedAt) {
+		check.CreatedAt = time.Time{} // Only a changed publish time must follow the creation
+	}
+	if err := h.validateItem(check); err != nil {
+		return err
+	}
+	if err := applySchedule(updated, original, updated.UpdatedAt); err != nil {
+		return invalidField(scheduleField(err), err)
+	}
+	if err := h.transitionError(ctx, original.Status, updated.Status, ""); err != nil {
+		return err
+	}
+	return h.prepareSave(slugs, updated, original.Slug)
+}
+
+// validateItem checks the fields of item against the content rules,
+// reporting all violations at once; see models.Content.Validate.
+func (h *CRUDHandler) validateItem(item models.Content) error {
+	var errs validate.Errors
+	if err := item.Validate(h.workflow.States); errors.As(err, &errs) {
+		return invalidFields(errs)
+	}
+	return nil
+}
+
+// prepareSave sanitizes the body and resolves the terms and slug of an
+// item about to be saved. currentSlug is the slug before an edit, "" for
+// new items.
+func (h *CRUDHandler) prepareSave(slugs slugLookup, item *models.Content, currentSlug string) error {
+	h.sanitizer.Clean(item)
+	if err := resolveTerms(h.taxonomies, item, true, !h.scoped); err != nil {
+		if termError(err) {
+			return invalidField(termField(err), err)
+		}
+		return fmt.Errorf("resolving terms for id %s: %w", item.ID, err)
+	}
+	if err := resolveSlug(slugs, item, currentSlug); err != nil {
+		if errors.Is(err, errInvalidSlug) {
+			return invalidField("slug", err)
+		}
+		return fmt.Errorf("generating slug for id %s: %w", item.ID, err)
+	}
+	return nil
+}
+
+// requestError is a failure the client caused, sent with its own status
+// and message. Other errors are logged and answered with 500.
+type requestError struct {
+	status int
+	msg    string
+	fields validate.Errors // The item fields at fault, if any; makes it a validation problem
+	typ    string          // Problem type URI, "" for about:blank
+}
+
+// errEditConflict rejects a write based on a stale copy of the item.
+var errEditConflict = &requestError{
+	status: fasthttp.StatusPreconditionFailed,
+	msg:    "Content has been changed since it was loaded; reload it and try again",
+	typ:    models.ProblemEditConflict,
+}
+
+// invalidFields returns a 400 requestError listing errs.
+func invalidFields(errs validate.Errors) error {
+	return &requestError{status: fasthttp.StatusBadRequest, msg: errs.Error(), fields: errs}
+}
+
+// invalidField returns a 400 requestError for a bad value of field.
+func invalidField(field string, err error) error {
+	return invalidFields(validate.Errors{field: err.Error()})
+}
+
+func (e *requestError) Error() string {
+	return e.msg
+}
+
+// problem describes the error as problem details.
+func (e *requestError) problem() models.Problem {
+	if len(e.fields) > 0 {
+		p := validationProblem(e.fields)
+		p.Status = e.status
+		return p
 	}
+	return models.Problem{Type: e.typ, Status: e.status, Detail: e.msg}
+}
 
-	_, found := userContent[id]
-	if !found {
-		ctx.Error("Content not found", fasthttp.StatusNotFound)
+// respondError sends err as the response; see requestError.
+func respondError(ctx *fasthttp.RequestCtx, logPrefix string, err error) {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		writeProblem(ctx, reqErr.problem())
 		return
 	}
+	log.Printf("%s: Error %v", logPrefix, err)
+	writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+}
 
-	delete(userContent, id)
+// storeError turns the failure to save item into a requestError where the
+// client can act on it, e.g. by choosing another slug.
+func storeError(err error, item models.Content) error {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return &requestError{status: fasthttp.StatusNotFound, msg: "Content not found"}
+	case errors.Is(err, storage.ErrLimitReached):
+		return &requestError{status: fasthttp.StatusConflict, msg: "Content limit reached. Please delete items before adding more."}
+	case errors.Is(err, storage.ErrSlugTaken):
+		return &requestError{status: fasthttp.StatusConflict, msg: "Slug '" + item.Slug + "' is already in use"}
+	case errors.Is(err, storage.ErrVersionConflict):
+		return errEditConflict
+	}
+	return fmt.Errorf("saving content for id %s: %w", item.ID, err)
+}
+
+// Delete handles DELETE /api/content/{id} - moves an item to the trash.
+func (h *CRUDHandler) Delete(ctx *fasthttp.RequestCtx) {
+	if h.trash(ctx, "CRUD Delete") {
+		ctx.SetStatusCode(fasthttp.StatusNoContent)
+	}
+}
 
-	if err := h.saveUserContent(ctx, userContent); err != nil {
-		log.Printf("CRUD Delete: Error saving user content after deleting id %s: %v", id, err)
-		ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+// Preview handles POST /api/content/preview - renders a body without saving it.
+// Expects {"format": "...", "content": "..."} and returns {"html": "..."}.
+func (h *CRUDHandler) Preview(ctx *fasthttp.RequestCtx) {
+	var req struct {
+		Format  string `json:"format"`
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(ctx.PostBody(), &req); err != nil {
+		invalidJSON(ctx, err)
+		return
+	}
+	if !models.ValidFormat(req.Format) {
+		respondError(ctx, "CRUD Preview", invalidField("format", fmt.Errorf("unknown format '%s'", req.Format)))
 		return
 	}
 
-	ctx.SetStatusCode(fasthttp.StatusNoContent)
+	out, err := h.renderer.Body(req.Format, req.Content)
+	if err != nil {
+		log.Printf("CRUD Preview: Error rendering %s: %v", req.Format, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	ctx.SetContentType("application/json; charset=utf-8")
+	if err := json.NewEncoder(ctx).Encode(map[string]string{"html": out}); err != nil {
+		log.Printf("CRUD Preview: Error encoding preview: %v", err)
+	}
 }
 
-// ExportJSON handles POST /api/export - exports the user's session content.
+// ExportJSON handles POST /api/export - exports all content.
 func (h *CRUDHandler) ExportJSON(ctx *fasthttp.RequestCtx) {
-	userContent, err := h.getUserContent(ctx)
+	store, err := h.contentStore(ctx)
 	if err != nil {
-		log.Printf("CRUD Export: Error getting user content: %v", err)
-		ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+		log.Printf("CRUD Export: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 		return
 	}
 
-	// Prepare data in the original export format (map bucket -> map id -> data)
-	exportData := map[string]map[string]json.RawMessage{
-		"content": make(map[string]json.RawMessage),
-	}
-	for id, item := range userContent {
-		itemJSON, err := json.Marshal(item)
-		if err != nil {
-			log.Printf("CRUD Export: Error marshaling item %s: %v", id, err)
-			// Skip this item or return error?
-			continue // Skipping for now
-		}
-		exportData["content"][id] = itemJSON
+	// Data is in the original export format (map bucket -> map id -> data)
+	exportData, err := store.Export()
+	if err != nil {
+		log.Printf("CRUD Export: Error exporting content: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
 	}
 
 	ctx.SetContentType("application/json; charset=utf-8")
@@ -351,29 +522,29 @@ func (h *CRUDHandler) ExportJSON(ctx *fasthttp.RequestCtx) {
 	if err := json.NewEncoder(ctx).Encode(exportData); err != nil {
 		log.Printf("CRUD Export: Error encoding database export: %v", err)
 		if !ctx.Response.Header.IsHTTP11() {
-			ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+			writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 		}
 	}
 }
 
-// ImportJSON handles POST /api/import - imports data into the user's session.
-// WARNING: This replaces existing session data.
+// ImportJSON handles POST /api/import - imports data into the content store.
+// WARNING: This replaces all existing content.
 func (h *CRUDHandler) ImportJSON(ctx *fasthttp.RequestCtx) {
 	if !ctx.IsPost() || !bytes.Contains(ctx.Request.Header.ContentType(), []byte("multipart/form-data")) {
-		ctx.Error("Invalid request method or content type. Use POST with multipart/form-data.", fasthttp.StatusBadRequest)
+		writeError(ctx, "Invalid request method or content type. Use POST with multipart/form-data.", fasthttp.StatusBadRequest)
 		return
 	}
 
 	form, err := ctx.MultipartForm()
 	if err != nil {
 		log.Printf("ImportJSON: Error parsing multipart form: %v", err)
-		ctx.Error("Failed to parse multipart form: "+err.Error(), fasthttp.StatusBadRequest)
+		writeError(ctx, "Failed to parse multipart form", fasthttp.StatusBadRequest)
 		return
 	}
 
 	fileHeaders := form.File["importFile"]
 	if len(fileHeaders) == 0 {
-		ctx.Error("No file uploaded with name 'importFile'", fasthttp.StatusBadRequest)
+		writeError(ctx, "No file uploaded with name 'importFile'", fasthttp.StatusBadRequest)
 		return
 	}
 	fileHeader := fileHeaders[0]
@@ -381,7 +552,7 @@ func (h *CRUDHandler) ImportJSON(ctx *fasthttp.RequestCtx) {
 	file, err := fileHeader.Open()
 	if err != nil {
 		log.Printf("ImportJSON: Error opening uploaded file: %v", err)
-		ctx.Error("Failed to open uploaded file", fasthttp.StatusInternalServerError)
+		writeError(ctx, "Failed to open uploaded file", fasthttp.StatusInternalServerError)
 		return
 	}
 	defer file.Close()
@@ -389,14 +560,15 @@ func (h *CRUDHandler) ImportJSON(ctx *fasthttp.RequestCtx) {
 	fileBytes, err := io.ReadAll(file)
 	if err != nil {
 		log.Printf("ImportJSON: Error reading uploaded file: %v", err)
-		ctx.Error("Failed to read uploaded file", fasthttp.StatusInternalServerError)
+		writeError(ctx, "Failed to read uploaded file", fasthttp.StatusInternalServerError)
 		return
 	}
 
 	var importFormat map[string]map[string]json.RawMessage
 	if err := json.Unmarshal(fileBytes, &importFormat); err != nil {
 		log.Printf("ImportJSON: Error unmarshaling import JSON from file: %v", err)
-		ctx.Error("Invalid JSON format in uploaded file: "+err.Error(), fasthttp.StatusBadRequest)
+		detail, _ := jsonErrorDetail(err)
+		writeError(ctx, "Invalid JSON format in uploaded file: "+detail, fasthttp.StatusBadRequest)
 		return
 	}
 
@@ -404,41 +576,101 @@ func (h *CRUDHandler) ImportJSON(ctx *fasthttp.RequestCtx) {
 	contentBucketData, ok := importFormat["content"]
 	if !ok {
 		log.Printf("ImportJSON: '%s' bucket not found in imported file.", "content")
-		ctx.Error(fmt.Sprintf("Invalid import file: '%s' bucket missing.", "content"), fasthttp.StatusBadRequest)
+		writeError(ctx, fmt.Sprintf("Invalid import file: '%s' bucket missing.", "content"), fasthttp.StatusBadRequest)
 		return
 	}
 
-	// Check limit before processing
-	if len(contentBucketData) > 50 {
-		log.Printf("ImportJSON: Import exceeds content limit (50). Found %d items.", len(contentBucketData))
-		ctx.Error(fmt.Sprintf("Import failed: File contains %d items, exceeding the limit of 50.", len(contentBucketData)), fasthttp.StatusConflict)
-		return
-	}
-
-	// Convert RawMessage map to models.Content map
-	importedContent := make(map[string]models.Content, len(contentBucketData))
+	// Validate every item before touching the store or the taxonomies. Rule
+	// violations are collected so the whole file is reported at once.
+	items := make(map[string]models.Content, len(contentBucketData))
+	invalid := validate.Errors{}
 	for id, rawData := range contentBucketData {
 		var item models.Content
 		if err := json.Unmarshal(rawData, &item); err != nil {
 			log.Printf("ImportJSON: Error unmarshaling item %s: %v", id, err)
-			ctx.Error(fmt.Sprintf("Error processing item '%s' in import file: %v", id, err), fasthttp.StatusBadRequest)
+			detail, field := jsonErrorDetail(err)
+			if field != "" {
+				detail = field + " " + detail
+			}
+			writeError(ctx, fmt.Sprintf("Error processing item '%s' in import file: %s", id, detail), fasthttp.StatusBadRequest)
 			return
 		}
-		// Optional: Validate imported item further?
-		importedContent[id] = item
+		if !slug.Valid(item.Slug) {
+			item.Slug = "" // Regenerated by the store, as for older exports
+		}
+		var errs validate.Errors
+		if err := item.Validate(h.workflow.States); errors.As(err, &errs) {
+			for field, msg := range errs {
+				invalid[id+"."+field] = msg
+			}
+			continue
+		}
+		items[id] = item
+	}
+	if len(invalid) > 0 {
+		p := validationProblem(invalid)
+		p.Detail = "Invalid items in import file"
+		writeProblem(ctx, p)
+		return
+	}
+
+	// Then sanitize them and resolve their terms
+	for id, item := range items {
+		h.sanitizer.Clean(&item)
+		// Missing tags are created; categories that do not exist here are dropped
+		if err := resolveTerms(h.taxonomies, &item, false, !h.scoped); err != nil {
+			if termError(err) {
+				writeError(ctx, fmt.Sprintf("Error processing item '%s' in import file: %v", id, err), fasthttp.StatusBadRequest)
+				return
+			}
+			log.Printf("ImportJSON: Error resolving terms of item %s: %v", id, err)
+			writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+			return
+		}
+		cleaned, err := json.Marshal(item)
+		if err != nil {
+			log.Printf("ImportJSON: Error marshaling item %s: %v", id, err)
+			writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+			return
+		}
+		contentBucketData[id] = cleaned
 	}
 
-	// Save the imported content map to the session, replacing existing
-	if err := h.saveUserContent(ctx, importedContent); err != nil {
-		log.Printf("ImportJSON: Error saving imported content to session: %v", err)
-		ctx.Error("Internal Server Error during import save", fasthttp.StatusInternalServerError)
+	store, err := h.contentStore(ctx)
+	if err != nil {
+		log.Printf("ImportJSON: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 		return
 	}
 
-	log.Printf("ImportJSON: Successfully imported %d items into session.", len(importedContent))
+	// Replace existing content with the imported items
+	if err := store.Import(importFormat); err != nil {
+		if errors.Is(err, storage.ErrLimitReached) {
+			log.Printf("ImportJSON: %v", err)
+			writeError(ctx, "Import failed: "+err.Error(), fasthttp.StatusConflict)
+			return
+		}
+		log.Printf("ImportJSON: Error saving imported content: %v", err)
+		writeError(ctx, "Internal Server Error during import save", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	h.scheduler.Wake() // Imported items may be due
+	log.Printf("ImportJSON: Successfully imported %d items.", len(contentBucketData))
 	ctx.Redirect("/content?imported=true", fasthttp.StatusSeeOther)
 }
 
+// ResetSandbox handles POST /api/sandbox/reset - restores the visitor's sandbox
+// to the initial content. Only available in sandbox storage mode.
+func (h *CRUDHandler) ResetSandbox(ctx *fasthttp.RequestCtx) {
+	if !h.resetSandbox(ctx) {
+		writeError(ctx, "Sandbox mode is not enabled", fasthttp.StatusNotFound)
+		return
+	}
+	log.Println("ResetSandbox: Sandbox reset to initial content")
+	ctx.Redirect("/content?reset=true", fasthttp.StatusSeeOther)
+}
+
 // generateID creates a cryptographically secure random hex ID.
 func generateID() (string, error) {
 	idBytes := make([]byte, 8) // 16 hex characters
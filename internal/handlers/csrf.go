@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"log"
+	"strings"
+
+	"cms/internal/config"
+
+	session "github.com/fasthttp/session/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// CSRF token locations. The token lives in the session (synchronizer token)
+// and is mirrored to a script-readable cookie so fetch calls can echo it in
+// the X-CSRF-Token header (double submit); HTML forms send it as a hidden field.
+const (
+	csrfSessionKey = "csrf_token"
+	csrfCookieName = "csrf_token"
+	csrfFormField  = "csrf_token"
+	csrfHeaderName = "X-CSRF-Token"
+	csrfCtxKey     = "csrf_token"
+)
+
+// CSRFMiddleware rejects state-changing requests that do not carry the
+// session's CSRF token. Safe requests for pages get a token issued so
+// templates can embed it. API calls authenticated with a bearer token are
+// exempt, as browsers never attach those automatically.
+func CSRFMiddleware(next fasthttp.RequestHandler, sess *session.Session, cfg *config.Config) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		path := string(ctx.Path())
+		if strings.HasPrefix(path, "/static/") {
+			next(ctx)
+			return
+		}
+		if _, ok := bearerToken(ctx); ok && strings.HasPrefix(path, "/api/") {
+			next(ctx)
+			return
+		}
+
+		safe := ctx.IsGet() || ctx.IsHead() || ctx.IsOptions()
+		if safe && (strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/media/")) {
+			// API reads and uploaded files render no forms; no token needed
+			// (and no cookie, so shared caches can keep files)
+			next(ctx)
+			return
+		}
+		if safe && publicPage(path, cfg) && len(ctx.Request.Header.Cookie(cfg.SessionCookieName)) == 0 {
+			// Anonymous readers of the public site get no session, so page
+			// views cost no database write and shared caches can keep them.
+			// Signed-in users still get a token for the header's forms.
+			next(ctx)
+			return
+		}
+
+		store, err := sess.Get(ctx)
+		if err != nil {
+			log.Printf("CSRFMiddleware: Error getting session: %v", err)
+			writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+			return
+		}
+		token, _ := store.Get(csrfSessionKey).(string)
+
+		if !safe {
+			submitted := string(ctx.Request.Header.Peek(csrfHeaderName))
+			if submitted == "" {
+				submitted = string(ctx.FormValue(csrfFormField))
+			}
+			if token == "" || subtle.ConstantTimeCompare([]byte(submitted), []byte(token)) != 1 {
+				log.Printf("CSRFMiddleware: Rejected %s %s: missing or invalid CSRF token", ctx.Method(), path)
+				writeError(ctx, "Invalid or missing CSRF token. Reload the page and try again.", fasthttp.StatusForbidden)
+				return
+			}
+			next(ctx)
+			return
+		}
+
+		if token == "" {
+			if token, err = generateCSRFToken(); err != nil {
+				log.Printf("CSRFMiddleware: Error generating token: %v", err)
+				writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+				return
+			}
+			id := string(store.GetSessionID()) // Copy before Save resets the store
+			store.Set(csrfSessionKey, token)
+			if err := sess.Save(ctx, store); err != nil {
+				log.Printf("CSRFMiddleware: Error saving session: %v", err)
+				writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+				return
+			}
+			// Let handlers later in this request load the same session
+			ctx.Request.Header.SetCookie(cfg.SessionCookieName, id)
+		}
+
+		if string(ctx.Request.Header.Cookie(csrfCookieName)) != token {
+			setCSRFCookie(ctx, cfg, token)
+		}
+		ctx.SetUserValue(csrfCtxKey, token)
+		next(ctx)
+	}
+}
+
+// publicPage reports whether path is a page anyone may read: the index and
+// the public site under cfg.PublicPrefix.
+func publicPage(path string, cfg *config.Config) bool {
+	return path == "/" || path == cfg.PublicPrefix || strings.HasPrefix(path, cfg.PublicPrefix+"/")
+}
+
+// csrfToken returns the CSRF token issued for the current request, if any.
+func csrfToken(ctx *fasthttp.RequestCtx) string {
+	token, _ := ctx.UserValue(csrfCtxKey).(string)
+	return token
+}
+
+// setCSRFCookie mirrors the token into a cookie readable by page scripts.
+// It is Secure under the same rule as the session cookie.
+func setCSRFCookie(ctx *fasthttp.RequestCtx, cfg *config.Config, token string) {
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+	cookie.SetKey(csrfCookieName)
+	cookie.SetValue(token)
+	cookie.SetPath("/")
+	cookie.SetDomain(cfg.SessionDomain)
+	cookie.SetSecure(cfg.SessionSecure && IsSecureRequest(ctx, cfg.TrustProxy))
+	cookie.SetSameSite(fasthttp.CookieSameSiteStrictMode)
+	ctx.Response.Header.SetCookie(cookie)
+}
+
+// generateCSRFToken creates a random hex token.
+func generateCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"strings"
+
+	"cms/internal/models"
+
+	"github.com/valyala/fasthttp"
+)
+
+// etagMatches reports whether an If-Match or If-None-Match header value
+// lists etag or is "*". Weak tags (W/"...") only match when weak is set,
+// as If-Match requires the strong comparison.
+func etagMatches(header, etag string, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if weak {
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfMatch evaluates the request's If-Match header against the item,
+// responding with 412 Precondition Failed if the client's copy is stale.
+// Requests without the header always pass.
+func checkIfMatch(ctx *fasthttp.RequestCtx, item models.Content) bool {
+	header := ctx.Request.Header.Peek(fasthttp.HeaderIfMatch)
+	if len(header) == 0 || etagMatches(string(header), item.ETag(), false) {
+		return true
+	}
+	writeProblem(ctx, errEditConflict.problem())
+	ctx.Response.Header.Set(fasthttp.HeaderETag, item.ETag()) // writeProblem resets the headers
+	return false
+}
+
+// notModified answers a conditional read with 304 Not Modified if the
+// client's If-None-Match header lists the item's current ETag.
+func notModified(ctx *fasthttp.RequestCtx, item models.Content) bool {
+	header := ctx.Request.Header.Peek(fasthttp.HeaderIfNoneMatch)
+	if len(header) == 0 || !etagMatches(string(header), item.ETag(), true) {
+		return false
+	}
+	ctx.NotModified()
+	return true
+}
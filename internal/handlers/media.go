@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"cms/internal/media"
+	"cms/internal/models"
+	"cms/internal/slug"
+	"cms/internal/storage"
+	"cms/internal/templates/pages"
+
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	maxAltLength      = 500
+	maxFilenameLength = 255
+)
+
+// mediaMessages maps ?message= keys to the flash text shown on the media page.
+var mediaMessages = map[string]string{
+	"uploaded": "File uploaded.",
+	"deleted":  "File deleted.",
+}
+
+// UploadMedia handles POST /api/media - stores the file sent as the
+// multipart field "file", with optional "alt" text. The type is sniffed
+// from the contents; only images and PDFs are accepted.
+func (h *CRUDHandler) UploadMedia(ctx *fasthttp.RequestCtx) {
+	if !h.sharedWritable(ctx) {
+		return
+	}
+	user, _ := currentUser(ctx)
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		writeError(ctx, "Failed to parse multipart form", fasthttp.StatusBadRequest)
+		return
+	}
+	files := form.File["file"]
+	if len(files) == 0 {
+		writeError(ctx, "No file uploaded with name 'file'", fasthttp.StatusBadRequest)
+		return
+	}
+	header := files[0]
+	if header.Size > h.cfg.MediaMaxSize {
+		writeError(ctx, fmt.Sprintf("File too large (limit %d bytes)", h.cfg.MediaMaxSize), fasthttp.StatusRequestEntityTooLarge)
+		return
+	}
+	if header.Size == 0 {
+		writeError(ctx, "Uploaded file is empty", fasthttp.StatusBadRequest)
+		return
+	}
+	alt := ""
+	if values := form.Value["alt"]; len(values) > 0 {
+		alt = strings.TrimSpace(values[0])
+	}
+	if utf8.RuneCountInString(alt) > maxAltLength {
+		writeError(ctx, fmt.Sprintf("Alt text must be at most %d characters", maxAltLength), fasthttp.StatusBadRequest)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		log.Printf("CRUD UploadMedia: Error opening uploaded file: %v", err)
+		writeError(ctx, "Failed to open uploaded file", fasthttp.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		log.Printf("CRUD UploadMedia: Error reading uploaded file: %v", err)
+		writeError(ctx, "Failed to read uploaded file", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	mime, err := media.Sniff(data)
+	if err != nil {
+		writeError(ctx, "Unsupported file type; upload JPEG, PNG, GIF, WebP or PDF files", fasthttp.StatusUnsupportedMediaType)
+		return
+	}
+	width, height, err := media.Dimensions(bytes.NewReader(data), mime)
+	if err != nil {
+		writeError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	id, err := generateID()
+	if err != nil {
+		log.Printf("CRUD UploadMedia: Error generating ID: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	filename := cleanFilename(header.Filename)
+	m := models.Media{
+		ID:        id,
+		Filename:  filename,
+		MIME:      mime,
+		Size:      int64(len(data)),
+		Width:     width,
+		Height:    height,
+		Alt:       alt,
+		Uploader:  user.Username,
+		URL:       mediaURL(id, filename, mime),
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := h.blobs.Put(media.OriginalKey(id), bytes.NewReader(data)); err != nil {
+		log.Printf("CRUD UploadMedia: Error storing file %s: %v", id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	if err := h.media.Put(m); err != nil {
+		log.Printf("CRUD UploadMedia: Error saving metadata of %s: %v", id, err)
+		if err := h.blobs.Delete(media.OriginalKey(id)); err != nil {
+			log.Printf("CRUD UploadMedia: Error removing file %s: %v", id, err)
+		}
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("CRUD UploadMedia: Stored '%s' (%s, %d bytes) as %s", filename, mime, m.Size, id)
+	ctx.SetStatusCode(fasthttp.StatusCreated)
+	writeJSON(ctx, "CRUD UploadMedia", m)
+}
+
+// ListMedia handles GET /api/media - lists uploads, newest first. ?type=image
+// limits the list to images; ?q= matches the file name or alt text.
+func (h *CRUDHandler) ListMedia(ctx *fasthttp.RequestCtx) {
+	items, err := h.media.List()
+	if err != nil {
+		log.Printf("CRUD ListMedia: Error listing media: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	imagesOnly := string(ctx.QueryArgs().Peek("type")) == "image"
+	q := strings.ToLower(strings.TrimSpace(string(ctx.QueryArgs().Peek("q"))))
+	matches := []models.Media{}
+	for _, m := range items {
+		if imagesOnly && !media.IsImage(m.MIME) {
+			continue
+		}
+		if q != "" && !strings.Contains(strings.ToLower(m.Filename), q) && !strings.Contains(strings.ToLower(m.Alt), q) {
+			continue
+		}
+		matches = append(matches, m)
+	}
+	writeJSON(ctx, "CRUD ListMedia", matches)
+}
+
+// GetMedia handles GET /api/media/{id} - retrieves the metadata of an upload.
+func (h *CRUDHandler) GetMedia(ctx *fasthttp.RequestCtx) {
+	m, ok := h.loadMedia(ctx, "CRUD GetMedia")
+	if !ok {
+		return
+	}
+	writeJSON(ctx, "CRUD GetMedia", m)
+}
+
+// UpdateMedia handles PUT /api/media/{id} - changes the alt text of an
+// upload. The file itself cannot be replaced.
+func (h *CRUDHandler) UpdateMedia(ctx *fasthttp.RequestCtx) {
+	if !h.sharedWritable(ctx) {
+		return
+	}
+	m, ok := h.loadMedia(ctx, "CRUD UpdateMedia")
+	if !ok {
+		return
+	}
+	if !canModifyAuthored(ctx, m.Uploader) {
+		writeError(ctx, "Forbidden", fasthttp.StatusForbidden)
+		return
+	}
+	var body struct {
+		Alt string `json:"alt"`
+	}
+	if err := json.Unmarshal(ctx.PostBody(), &body); err != nil {
+		invalidJSON(ctx, err)
+		return
+	}
+	m.Alt = strings.TrimSpace(body.Alt)
+	if utf8.RuneCountInString(m.Alt) > maxAltLength {
+		writeError(ctx, fmt.Sprintf("Alt text must be at most %d characters", maxAltLength), fasthttp.StatusBadRequest)
+		return
+	}
+	if err := h.media.Put(m); err != nil {
+		log.Printf("CRUD UpdateMedia: Error saving metadata of %s: %v", m.ID, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	writeJSON(ctx, "CRUD UpdateMedia", m)
+}
+
+// DeleteMedia handles DELETE /api/media/{id} - removes an upload and its
+// cached variants. Content still linking to it will show a broken link.
+func (h *CRUDHandler) DeleteMedia(ctx *fasthttp.RequestCtx) {
+	if !h.sharedWritable(ctx) {
+		return
+	}
+	m, ok := h.loadMedia(ctx, "CRUD DeleteMedia")
+	if !ok {
+		return
+	}
+	if !canModifyAuthored(ctx, m.Uploader) {
+		writeError(ctx, "Forbidden", fasthttp.StatusForbidden)
+		return
+	}
+	if err := h.media.Delete(m.ID); err != nil && !errors.Is(err, storage.ErrMediaNotFound) {
+		log.Printf("CRUD DeleteMedia: Error deleting metadata of %s: %v", m.ID, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	// The upload is gone once its metadata is; leftover files are only logged
+	for _, key := range []string{media.OriginalKey(m.ID), media.VariantsKey(m.ID)} {
+		if err := h.blobs.Delete(key); err != nil {
+			log.Printf("CRUD DeleteMedia: Error removing %s: %v", key, err)
+		}
+	}
+	log.Printf("CRUD DeleteMedia: Deleted %s ('%s')", m.ID, m.Filename)
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+// loadMedia fetches the upload named by the {id} route parameter, writing
+// a 404 or 500 response on failure.
+func (h *CRUDHandler) loadMedia(ctx *fasthttp.RequestCtx, logPrefix string) (models.Media, bool) {
+	id, _ := ctx.UserValue("id").(string)
+	m, err := h.media.Get(id)
+	if errors.Is(err, storage.ErrMediaNotFound) {
+		writeError(ctx, "Media not found", fasthttp.StatusNotFound)
+		return m, false
+	}
+	if err != nil {
+		log.Printf("%s: Error loading media %s: %v", logPrefix, id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return m, false
+	}
+	return m, true
+}
+
+// MediaPage handles GET /media - the media library, where files are
+// uploaded and browsed.
+func (h *PageHandler) MediaPage(ctx *fasthttp.RequestCtx) {
+	items, err := h.media.List()
+	if err != nil {
+		log.Printf("Media Page: Error listing media: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	data := &models.MediaData{
+		BasePageData: h.newBasePageData(ctx, "Media Library", "Uploaded images and documents"),
+		Items:        items,
+		Deletable:    make(map[string]bool),
+		MaxSize:      h.cfg.MediaMaxSize,
+		Message:      mediaMessages[string(ctx.QueryArgs().Peek("message"))],
+	}
+	for _, m := range items {
+		data.Deletable[m.ID] = h.sharedEditable() && canModifyAuthored(ctx, m.Uploader)
+	}
+	for _, p := range media.Presets {
+		data.Presets = append(data.Presets, p.Name)
+	}
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WriteMediaPage(ctx, data)
+}
+
+// ServeMedia handles GET /media/{id}/{name} - serves an uploaded file to
+// anyone, as published content embeds it. ?size= picks an image variant
+// (see media.Presets), made on first request and cached in the blob store.
+func (h *PageHandler) ServeMedia(ctx *fasthttp.RequestCtx) {
+	id, _ := ctx.UserValue("id").(string)
+	m, err := h.media.Get(id)
+	if errors.Is(err, storage.ErrMediaNotFound) {
+		h.NotFound(ctx)
+		return
+	}
+	if err != nil {
+		log.Printf("Serve Media: Error loading media %s: %v", id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	key, mime := media.OriginalKey(m.ID), m.MIME
+	if size := string(ctx.QueryArgs().Peek("size")); size != "" {
+		preset, ok := media.LookupPreset(size)
+		if !ok {
+			writeError(ctx, "Unknown size", fasthttp.StatusBadRequest)
+			return
+		}
+		// Other files, and images already small enough, are served as uploaded
+		if media.CanResize(m.MIME) && preset.Needed(m.Width, m.Height) {
+			if key, mime, err = h.variant(m, preset); err != nil {
+				log.Printf("Serve Media: Error making %s variant of %s: %v", preset.Name, m.ID, err)
+				writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	body, err := h.blobs.Open(key)
+	if errors.Is(err, media.ErrBlobNotFound) {
+		log.Printf("Serve Media: File %s of media %s is missing", key, m.ID)
+		h.NotFound(ctx)
+		return
+	}
+	if err != nil {
+		log.Printf("Serve Media: Error opening %s: %v", key, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	ctx.SetContentType(mime)
+	ctx.Response.Header.Set("X-Content-Type-Options", "nosniff")
+	// URLs are keyed by upload ID and files never change, so caches may keep them
+	ctx.Response.Header.Set(fasthttp.HeaderCacheControl, "public, max-age=31536000, immutable")
+	ctx.SetBodyStream(body, -1) // Closed by fasthttp once sent
+}
+
+// variant returns the blob key and MIME type of an image variant, making
+// and caching it if this is its first request.
+func (h *PageHandler) variant(m models.Media, preset media.Preset) (string, string, error) {
+	key := media.VariantKey(m.ID, preset.Name)
+	mime := "image/png"
+	if m.MIME == "image/jpeg" {
+		mime = m.MIME
+	}
+	cached, err := h.blobs.Open(key)
+	if err == nil {
+		cached.Close()
+		return key, mime, nil
+	}
+	if !errors.Is(err, media.ErrBlobNotFound) {
+		return "", "", err
+	}
+
+	original, err := h.blobs.Open(media.OriginalKey(m.ID))
+	if err != nil {
+		return "", "", err
+	}
+	defer original.Close()
+	data, mime, err := media.Resize(original, m.MIME, preset)
+	if err != nil {
+		return "", "", err
+	}
+	if err := h.blobs.Put(key, bytes.NewReader(data)); err != nil {
+		return "", "", err
+	}
+	return key, mime, nil
+}
+
+// cleanFilename reduces an uploaded file name to its base name without
+// control characters.
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "." || name == "/" {
+		name = ""
+	}
+	if utf8.RuneCountInString(name) > maxFilenameLength {
+		name = string([]rune(name)[:maxFilenameLength])
+	}
+	return name
+}
+
+// mediaURL builds the public URL of an upload: its ID, then a readable
+// name from the uploaded file name with the extension of its sniffed type.
+func mediaURL(id, filename, mime string) string {
+	name := slug.Make(strings.TrimSuffix(filename, filepath.Ext(filename)))
+	if name == "" {
+		name = "file"
+	}
+	return "/media/" + id + "/" + name + media.Extension(mime)
+}
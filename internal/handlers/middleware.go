@@ -1,19 +1,30 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"strings"
+	"time"
 
+	"cms/internal/auth"
 	"cms/internal/config"
+	"cms/internal/models"
+	"cms/internal/storage"
+	"cms/internal/templates/pages"
 
 	session "github.com/fasthttp/session/v2"
 	"github.com/valyala/fasthttp"
 )
 
-// AuthMiddleware checks if the user is authenticated via session.
+// tokenTouchInterval limits how often a token's last-used time is written.
+const tokenTouchInterval = time.Minute
+
+// AuthMiddleware checks if the user is authenticated via session and
+// that their role grants the permission required by the route.
+// API requests may instead authenticate with an `Authorization: Bearer` token.
 // If not authenticated, redirects to the login page.
 // Allows access to public paths.
-func AuthMiddleware(next fasthttp.RequestHandler, sess *session.Session, cfg *config.Config) fasthttp.RequestHandler {
+func AuthMiddleware(next fasthttp.RequestHandler, sess *session.Session, cfg *config.Config, users *storage.UserStore, tokens *storage.TokenStore) fasthttp.RequestHandler {
 	return func(ctx *fasthttp.RequestCtx) {
 		path := string(ctx.Path())
 
@@ -36,6 +47,13 @@ func AuthMiddleware(next fasthttp.RequestHandler, sess *session.Session, cfg *co
 		if strings.HasPrefix(path, "/static/") {
 			isPublic = true
 		}
+		// Published content can be fetched by slug and read on the public site without logging in,
+		// along with the uploaded files it embeds
+		if string(ctx.Method()) == fasthttp.MethodGet &&
+			(strings.HasPrefix(path, "/api/content/by-slug/") || strings.HasPrefix(path, "/media/") ||
+				path == cfg.PublicPrefix || strings.HasPrefix(path, cfg.PublicPrefix+"/")) {
+			isPublic = true
+		}
 
 		// If the path is public, allow access without checking session
 		if isPublic {
@@ -43,17 +61,40 @@ func AuthMiddleware(next fasthttp.RequestHandler, sess *session.Session, cfg *co
 			return
 		}
 
+		// API clients authenticate with a bearer token instead of the session
+		if raw, ok := bearerToken(ctx); ok && strings.HasPrefix(path, "/api/") {
+			tokenAuth(ctx, next, raw, users, tokens)
+			return
+		}
+
 		// Get session store for the current request
 		store, err := sess.Get(ctx)
 		if err != nil {
 			log.Printf("AuthMiddleware: Error getting session: %v", err)
-			ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+			writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 			return
 		}
 
 		// Check if the user is authenticated
 		authValue := store.Get("authenticated")
 		authenticated, ok := authValue.(bool)
+		username, _ := store.Get("username").(string)
+
+		// Load the account so role changes and deletions apply immediately
+		var user models.User
+		if ok && authenticated {
+			user, err = users.Get(username)
+			if errors.Is(err, storage.ErrUserNotFound) {
+				log.Printf("AuthMiddleware: Session user '%s' no longer exists", username)
+				authenticated = false
+				store.Delete("authenticated")
+				store.Delete("username")
+			} else if err != nil {
+				log.Printf("AuthMiddleware: Error loading user '%s': %v", username, err)
+				writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+				return
+			}
+		}
 
 		// Redirect to login if not authenticated
 		if !ok || !authenticated {
@@ -68,7 +109,148 @@ func AuthMiddleware(next fasthttp.RequestHandler, sess *session.Session, cfg *co
 			return
 		}
 
+		// Enforce the role permission required by the route
+		if perm := requiredPermission(string(ctx.Method()), path); perm != "" && !auth.Can(user.Role, perm) {
+			log.Printf("AuthMiddleware: User '%s' (%s) denied %s %s", user.Username, user.Role, ctx.Method(), path)
+			forbidden(ctx, user)
+			return
+		}
+
 		// If authenticated, proceed to the requested handler
+		setCurrentUser(ctx, user)
 		next(ctx)
 	}
 }
+
+// bearerToken extracts the token from an `Authorization: Bearer` header.
+func bearerToken(ctx *fasthttp.RequestCtx) (string, bool) {
+	header := string(ctx.Request.Header.Peek(fasthttp.HeaderAuthorization))
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// tokenAuth authenticates an API request by bearer token. The request is
+// allowed only if both the token's scopes and its owner's role grant the
+// route's permission.
+func tokenAuth(ctx *fasthttp.RequestCtx, next fasthttp.RequestHandler, raw string, users *storage.UserStore, tokens *storage.TokenStore) {
+	path := string(ctx.Path())
+	hash := auth.HashToken(raw)
+	token, err := tokens.GetByHash(hash)
+	if errors.Is(err, storage.ErrTokenNotFound) {
+		log.Printf("AuthMiddleware: Invalid API token for %s", path)
+		unauthorized(ctx)
+		return
+	}
+	if err != nil {
+		log.Printf("AuthMiddleware: Error loading API token: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	user, err := users.Get(token.Username)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		log.Printf("AuthMiddleware: API token %s belongs to missing user '%s'", token.ID, token.Username)
+		unauthorized(ctx)
+		return
+	}
+	if err != nil {
+		log.Printf("AuthMiddleware: Error loading user '%s': %v", token.Username, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	perm := requiredPermission(string(ctx.Method()), path)
+	if perm == "" || !token.Allows(perm) || !auth.Can(user.Role, perm) {
+		log.Printf("AuthMiddleware: API token %s (%s) denied %s %s", token.ID, user.Username, ctx.Method(), path)
+		writeError(ctx, "Forbidden", fasthttp.StatusForbidden)
+		return
+	}
+
+	if now := time.Now().UTC(); now.Sub(token.LastUsedAt) > tokenTouchInterval {
+		if err := tokens.Touch(hash, now); err != nil {
+			log.Printf("AuthMiddleware: Error updating last use of API token %s: %v", token.ID, err)
+		}
+	}
+
+	setCurrentUser(ctx, user)
+	setCurrentToken(ctx, token)
+	next(ctx)
+}
+
+// unauthorized responds with 401 and a bearer challenge.
+func unauthorized(ctx *fasthttp.RequestCtx) {
+	writeError(ctx, "Unauthorized", fasthttp.StatusUnauthorized)
+	ctx.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, `Bearer realm="cms"`) // writeError resets the headers
+}
+
+// requiredPermission maps a request to the permission its route requires.
+// An empty permission means any authenticated user may proceed; finer
+// checks (e.g. item ownership) happen in the handlers.
+func requiredPermission(method, path string) auth.Permission {
+	switch {
+	case path == "/admin/taxonomies" || strings.HasPrefix(path, "/admin/taxonomies/"):
+		return auth.PermTermsManage
+	case path == "/admin" || strings.HasPrefix(path, "/admin/"):
+		return auth.PermUsersManage
+	case path == "/api/import":
+		return auth.PermImport
+	case path == "/api/export":
+		return auth.PermExport
+	case path == "/api/sandbox/reset":
+		return auth.PermContentWrite
+	case path == "/api/search" || path == "/search" || path == "/api/tags" || path == "/api/categories":
+		return auth.PermContentRead
+	case path == "/api/types" || strings.HasPrefix(path, "/api/types/"):
+		if method == fasthttp.MethodGet {
+			return auth.PermContentRead
+		}
+		// /api/types and /api/types/{type} define types; deeper paths are items
+		if rest := strings.TrimPrefix(strings.TrimPrefix(path, "/api/types"), "/"); strings.Contains(rest, "/") {
+			return auth.PermContentWrite
+		}
+		return auth.PermTypesManage
+	case path == "/types" || strings.HasPrefix(path, "/types/"):
+		if method == fasthttp.MethodGet && !strings.HasSuffix(path, "/new") && !strings.HasSuffix(path, "/edit") {
+			return auth.PermContentRead
+		}
+		return auth.PermContentWrite
+	case path == "/media":
+		return auth.PermContentRead
+	case path == "/api/media" || strings.HasPrefix(path, "/api/media/"):
+		if method == fasthttp.MethodGet {
+			return auth.PermContentRead
+		}
+		return auth.PermContentWrite
+	case strings.HasPrefix(path, "/api/content"):
+		if method == fasthttp.MethodGet {
+			return auth.PermContentRead
+		}
+		return auth.PermContentWrite
+	case path == "/content/new" || (strings.HasPrefix(path, "/content/") && strings.HasSuffix(path, "/edit")):
+		return auth.PermContentWrite
+	case path == "/content" || strings.HasPrefix(path, "/content/"):
+		return auth.PermContentRead
+	}
+	return ""
+}
+
+// forbidden responds with 403: problem details for the API, an HTML page otherwise.
+func forbidden(ctx *fasthttp.RequestCtx, user models.User) {
+	if !wantsHTML(ctx) {
+		writeError(ctx, "Forbidden", fasthttp.StatusForbidden)
+		return
+	}
+	data := &models.BasePageData{
+		PageTitle:       "403 Forbidden",
+		PageDescription: "You do not have permission to access this page.",
+		AuthStatus:      true,
+		Username:        user.Username,
+		UserRole:        user.Role,
+	}
+	ctx.SetStatusCode(fasthttp.StatusForbidden)
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WriteForbiddenPage(ctx, data)
+}
@@ -2,13 +2,18 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
+	"cms/internal/auth"
 	"cms/internal/config"
+	"cms/internal/media"
 	"cms/internal/models"
+	"cms/internal/render"
+	"cms/internal/storage"
+	"cms/internal/workflow"
 
 	// Import the specific generated template packages
 	"cms/internal/templates/pages"
@@ -21,103 +26,54 @@ import (
 
 // PageHandler handles requests for HTML pages.
 type PageHandler struct {
-	sess           *session.Session
-	cfg            *config.Config
-	initialContent map[string]models.Content // Added initial content map
+	storeResolver
+	sess       *session.Session
+	cfg        *config.Config
+	users      *storage.UserStore
+	tokens     *storage.TokenStore
+	limiter    *auth.Limiter
+	renderer   *render.Renderer
+	workflow   *workflow.Workflow
+	types      *storage.TypeStore
+	taxonomies *storage.TaxonomyStore
+	media      *storage.MediaStore
+	blobs      media.BlobStore
 }
 
 // NewPageHandler creates a new page handler.
-func NewPageHandler(sess *session.Session, cfg *config.Config, initialContent map[string]models.Content) *PageHandler {
+func NewPageHandler(sess *session.Session, cfg *config.Config, backend storage.Backend, users *storage.UserStore, tokens *storage.TokenStore, limiter *auth.Limiter, renderer *render.Renderer, wf *workflow.Workflow, types *storage.TypeStore, taxonomies *storage.TaxonomyStore, mediaStore *storage.MediaStore, blobs media.BlobStore) *PageHandler {
 	return &PageHandler{
-		sess:           sess,
-		cfg:            cfg,
-		initialContent: initialContent,
+		storeResolver: newStoreResolver(sess, backend, cfg),
+		sess:          sess,
+		cfg:           cfg,
+		users:         users,
+		tokens:        tokens,
+		limiter:       limiter,
+		renderer:      renderer,
+		workflow:      wf,
+		types:         types,
+		taxonomies:    taxonomies,
+		media:         mediaStore,
+		blobs:         blobs,
 	}
 }
 
-// Helper function to get user's content map from session or initialize it
-func (h *PageHandler) getUserContent(ctx *fasthttp.RequestCtx) (map[string]models.Content, error) {
-	store, err := h.sess.Get(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get session: %w", err)
-	}
-
-	contentData := store.Get("user_content")
-	if contentData == nil {
-		// Not initialized, clone initial data and serialize to JSON
-		log.Println("getUserContent (Page): Initializing user content in session")
-		userContent := make(map[string]models.Content, len(h.initialContent))
-		for k, v := range h.initialContent {
-			userContent[k] = v // Shallow copy
-		}
-		// Serialize content to JSON bytes for storage
-		jsonBytes, err := json.Marshal(userContent)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal content for session: %w", err)
-		}
-		// Store the JSON rather than direct object
-		store.Set("user_content", jsonBytes)
-		if err := h.sess.Save(ctx, store); err != nil {
-			return nil, fmt.Errorf("failed to save session after initializing content: %w", err)
-		}
-		return userContent, nil
-	}
-
-	// Now handle different types that might be in session
-	var userContent map[string]models.Content
-
-	switch v := contentData.(type) {
-	case []byte:
-		// This is our expected format - JSON bytes
-		if err := json.Unmarshal(v, &userContent); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal content bytes from session: %w", err)
-		}
-	case string:
-		// Handle if somehow stored as string
-		if err := json.Unmarshal([]byte(v), &userContent); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal content string from session: %w", err)
-		}
-	case map[string]interface{}:
-		// Handle case where session serialization might change types
-		log.Println("getUserContent (Page): Attempting conversion from map[string]interface{}")
-		b, err := json.Marshal(v)
-		if err != nil {
-			return nil, fmt.Errorf("page: failed to marshal map[string]interface{}: %w", err)
-		}
-		if err := json.Unmarshal(b, &userContent); err != nil {
-			return nil, fmt.Errorf("page: failed to unmarshal into map[string]models.Content: %w", err)
-		}
-		// Update session with bytes to prevent future type conversions
-		jsonBytes, err := json.Marshal(userContent)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal content for session update: %w", err)
-		}
-		store.Set("user_content", jsonBytes)
-		if err := h.sess.Save(ctx, store); err != nil {
-			log.Printf("Warning: failed to update session after type conversion: %v", err)
-			// Non-fatal
-		}
-	default:
-		return nil, fmt.Errorf("page: unexpected type for user_content: %T", contentData)
-	}
-
-	return userContent, nil
-}
-
 // Helper function to populate BasePageData, including auth status
-// Now uses getUserContent to potentially initialize session data if needed
 func (h *PageHandler) newBasePageData(ctx *fasthttp.RequestCtx, title, description string) models.BasePageData {
 	authStatus := false
-	// We still need the session store for the auth flag
-	store, err := h.sess.Get(ctx)
-	if err == nil {
-		if authVal := store.Get("authenticated"); authVal != nil {
-			if authenticated, ok := authVal.(bool); ok {
-				authStatus = authenticated
+	var username, role string
+	if user, ok := currentUser(ctx); ok {
+		// Protected routes: AuthMiddleware already loaded the account
+		authStatus, username, role = true, user.Username, user.Role
+	} else if store, err := h.sess.Get(ctx); err == nil {
+		// Public routes: fall back to the session auth flag
+		if authenticated, ok := store.Get("authenticated").(bool); ok && authenticated {
+			authStatus = true
+			username, _ = store.Get("username").(string)
+			if user, err := h.users.Get(username); err == nil {
+				role = user.Role
 			}
 		}
-		// Trigger user content initialization if not already done (harmless if already done)
-		_, _ = h.getUserContent(ctx) // Ignore errors here, focus is on BasePageData
 	} else {
 		log.Printf("newBasePageData: Error getting session for %s: %v", string(ctx.Path()), err)
 	}
@@ -126,6 +82,10 @@ func (h *PageHandler) newBasePageData(ctx *fasthttp.RequestCtx, title, descripti
 		PageTitle:       title,
 		PageDescription: description,
 		AuthStatus:      authStatus,
+		SandboxMode:     h.scoped,
+		Username:        username,
+		UserRole:        role,
+		CSRF:            csrfToken(ctx),
 	}
 }
 
@@ -142,65 +102,173 @@ func (h *PageHandler) Index(ctx *fasthttp.RequestCtx) {
 	// Create the correct data type
 	data := &models.IndexData{
 		BasePageData: baseData,
-		// Initialize any other IndexData specific fields if they were added
+		PublicPrefix: h.cfg.PublicPrefix,
 	}
 	pages.WriteIndexPage(ctx, data) // Pass the pointer to models.IndexData
 }
 
-// List handles GET /content - renders the list of content items from session.
+// listMessages maps ?message= keys to the flash text shown on /content.
+var listMessages = map[string]string{
+	"trashed":      "Item moved to the trash.",
+	"bulk-trashed": "Selected items moved to the trash.",
+	"bulk-status":  "Status of the selected items updated.",
+}
+
+// List handles GET /content - renders the list of content items.
 func (h *PageHandler) List(ctx *fasthttp.RequestCtx) {
-	userContent, err := h.getUserContent(ctx)
+	store, err := h.contentStore(ctx)
 	if err != nil {
-		log.Printf("Page List: Error getting user content: %v", err)
-		ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+		log.Printf("Page List: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 		return
 	}
 
-	// Convert map to slice for template
-	contents := make([]models.Content, 0, len(userContent))
-	for _, item := range userContent {
-		contents = append(contents, item)
-	}
-	// TODO: Add sorting
-
+	args := ctx.QueryArgs()
 	data := &models.ListData{
 		BasePageData: h.newBasePageData(ctx, "Content List", "Your current content items"),
-		Items:        contents,
+		Filter: models.ListFilter{
+			Status:        string(args.Peek("status")),
+			Search:        string(args.Peek("q")),
+			CreatedAfter:  string(args.Peek("created_after")),
+			CreatedBefore: string(args.Peek("created_before")),
+			Sort:          string(args.Peek("sort")),
+			Tag:           string(args.Peek("tag")),
+			Category:      string(args.Peek("category")),
+		},
+		Statuses: h.workflow.States,
+	}
+	categories, err := h.taxonomies.Terms(models.TaxonomyCategories)
+	if err != nil {
+		log.Printf("Page List: Error listing categories: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	data.Categories = termEntries(categories, nil)
+	data.Message = listMessages[string(args.Peek("message"))]
+
+	query, err := parseListQuery(args)
+	if err != nil {
+		h.renderList(ctx, data, err.Error())
+		return
+	}
+	query.Trashed = false // The trash has its own page
+	if err := expandCategories(h.taxonomies, &query); err != nil {
+		log.Printf("Page List: Error expanding category filter: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	result, err := store.Query(query)
+	if queryError(err) {
+		h.renderList(ctx, data, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Page List: Error listing content: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	data.Items = result.Items
+	data.Selectable = make(map[string]bool, len(result.Items))
+	for _, item := range result.Items {
+		if canModify(ctx, item) {
+			data.Selectable[item.ID] = true
+		}
+	}
+	if result.NextCursor != "" {
+		data.NextURL = listURL(ctx, "/content", result.NextCursor)
+	}
+	if query.Cursor != "" {
+		data.FirstURL = listURL(ctx, "/content", "")
+	}
+	h.renderList(ctx, data, "")
+}
+
+// renderList renders the content list page, with an optional error.
+func (h *PageHandler) renderList(ctx *fasthttp.RequestCtx, data *models.ListData, errorMessage string) {
+	data.ErrorMessage = errorMessage
+	if errorMessage != "" {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
 	}
 	ctx.SetContentType("text/html; charset=utf-8")
 	pages.WriteListPage(ctx, data)
 }
 
-// View handles GET /content/{id} - renders a single content item from session.
+// View handles GET /content/{id} - renders a single content item.
 func (h *PageHandler) View(ctx *fasthttp.RequestCtx) {
 	id, ok := ctx.UserValue("id").(string)
 	if !ok || id == "" {
-		ctx.Error("Missing or invalid content ID", fasthttp.StatusBadRequest)
+		writeError(ctx, "Missing or invalid content ID", fasthttp.StatusBadRequest)
 		return
 	}
 
-	userContent, err := h.getUserContent(ctx)
+	store, err := h.contentStore(ctx)
 	if err != nil {
-		log.Printf("Page View: Error getting user content for id %s: %v", id, err)
-		ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+		log.Printf("Page View: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 		return
 	}
 
-	item, found := userContent[id]
-	if !found {
+	item, err := store.Get(id)
+	if errors.Is(err, storage.ErrNotFound) {
 		// Redirect to list or show 404? Redirecting to list for now.
-		log.Printf("Page View: Item %s not found in user session", id)
+		log.Printf("Page View: Item %s not found", id)
 		ctx.Redirect("/content?notfound="+id, fasthttp.StatusSeeOther)
 		return
 		// Or: Use NotFound handler
 		// h.NotFound(ctx)
 		// return
 	}
+	if err != nil {
+		log.Printf("Page View: Error getting content for id %s: %v", id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	if item.Trashed() {
+		ctx.Redirect("/content/trash", fasthttp.StatusSeeOther)
+		return
+	}
+
+	revs, err := store.Revisions(id)
+	if err != nil {
+		log.Printf("Page View: Error listing revisions of %s: %v", id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
 
 	data := &models.ViewData{
 		BasePageData: h.newBasePageData(ctx, item.Title, "View content item"),
 		Item:         item,
+		Body:         h.renderer.Item(item),
+		Revisions:    revs,
+		CanModify:    canModify(ctx, item),
+		Transitions:  h.transitions(ctx, item),
+		Tags:         lookupTerms(h.taxonomies, models.TaxonomyTags, item.Tags),
+		Categories:   lookupTerms(h.taxonomies, models.TaxonomyCategories, item.Categories),
+	}
+	if restored := ctx.QueryArgs().GetUintOrZero("restored"); restored > 0 {
+		data.Message = fmt.Sprintf("Restored revision %d.", restored)
 	}
+	if string(ctx.QueryArgs().Peek("message")) == "status" {
+		data.Message = "Status changed to " + workflow.StateLabel(item.Status) + "."
+	}
+
+	// Compare two revisions when requested with ?from=N&to=M
+	args := ctx.QueryArgs()
+	if from, to := args.GetUintOrZero("from"), args.GetUintOrZero("to"); from > 0 && to > 0 {
+		fromRev, err := store.Revision(id, from)
+		var toRev models.Revision
+		if err == nil {
+			toRev, err = store.Revision(id, to)
+		}
+		if err == nil {
+			data.Diff = compareRevisions(fromRev, toRev)
+		} else if !errors.Is(err, storage.ErrRevisionNotFound) {
+			log.Printf("Page View: Error loading revisions %d and %d of %s: %v", from, to, id, err)
+		}
+	}
+
 	ctx.SetContentType("text/html; charset=utf-8")
 	pages.WriteViewPage(ctx, data)
 }
@@ -213,50 +281,92 @@ func (h *PageHandler) New(ctx *fasthttp.RequestCtx) {
 		// ID will be generated on save (POST)
 	}
 
+	categories, err := h.taxonomies.Terms(models.TaxonomyCategories)
+	if err != nil {
+		log.Printf("Page New: Error listing categories: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
 	// Use the Edit page template, but mark it as 'new'
 	data := &models.EditData{
 		BasePageData: h.newBasePageData(ctx, "Create New Content", "Fill in the details for the new content item"),
 		Item:         newItem, // Pass the empty item
 		IsNew:        true,    // Indicate this is for creating a new item
+		Statuses:     h.workflow.Statuses(currentRole(ctx), h.workflow.Initial),
+		Categories:   termEntries(categories, nil),
 	}
 	ctx.SetContentType("text/html; charset=utf-8")
 	pages.WriteEditPage(ctx, data) // Reuse the Edit page template
 }
 
-// Edit handles GET /content/{id}/edit - renders the form to edit content from session.
+// Edit handles GET /content/{id}/edit - renders the form to edit content.
 func (h *PageHandler) Edit(ctx *fasthttp.RequestCtx) {
 	id, ok := ctx.UserValue("id").(string)
 	if !ok || id == "" {
-		ctx.Error("Missing or invalid content ID", fasthttp.StatusBadRequest)
+		writeError(ctx, "Missing or invalid content ID", fasthttp.StatusBadRequest)
 		return
 	}
 
-	userContent, err := h.getUserContent(ctx)
+	store, err := h.contentStore(ctx)
 	if err != nil {
-		log.Printf("Page Edit: Error getting user content for id %s: %v", id, err)
-		ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+		log.Printf("Page Edit: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 		return
 	}
 
-	item, found := userContent[id]
-	if !found {
-		log.Printf("Page Edit: Item %s not found in user session", id)
+	item, err := store.Get(id)
+	if errors.Is(err, storage.ErrNotFound) {
+		log.Printf("Page Edit: Item %s not found", id)
 		ctx.Redirect("/content?notfound="+id, fasthttp.StatusSeeOther)
 		return
 	}
+	if err != nil {
+		log.Printf("Page Edit: Error getting content for id %s: %v", id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	if item.Trashed() {
+		ctx.Redirect("/content/trash", fasthttp.StatusSeeOther)
+		return
+	}
+	if !canModify(ctx, item) {
+		user, _ := currentUser(ctx)
+		log.Printf("Page Edit: User '%s' may not edit item %s", user.Username, id)
+		forbidden(ctx, user)
+		return
+	}
+
+	revs, err := store.Revisions(id)
+	if err != nil {
+		log.Printf("Page Edit: Error listing revisions of %s: %v", id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	categories, err := h.taxonomies.Terms(models.TaxonomyCategories)
+	if err != nil {
+		log.Printf("Page Edit: Error listing categories: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
 
 	data := &models.EditData{
 		BasePageData: h.newBasePageData(ctx, "Edit: "+item.Title, "Edit content item"),
 		Item:         item,
 		IsNew:        false,
+		Statuses:     h.workflow.Statuses(currentRole(ctx), item.Status),
+		TagNames:     termNames(h.taxonomies, models.TaxonomyTags, item.Tags),
+		Categories:   termEntries(categories, nil),
+	}
+	// Show the reviewer's comment until the item is edited again
+	if len(revs) > 0 && revs[0].Action == models.RevisionStatus && revs[0].Comment != "" {
+		data.Feedback = &revs[0]
 	}
 	ctx.SetContentType("text/html; charset=utf-8")
 	pages.WriteEditPage(ctx, data)
 }
 
 // Login handles GET /login - renders the login form.
-// Logic for initializing user_content happens in getUserContent, called by newBasePageData.
-// Existing Login handler is mostly fine, might tweak message clearing slightly.
 func (h *PageHandler) Login(ctx *fasthttp.RequestCtx) {
 	var store *session.Store
 	var err error
@@ -301,127 +411,89 @@ func (h *PageHandler) Login(ctx *fasthttp.RequestCtx) {
 }
 
 // PostLogin handles POST /login - processes login attempt.
-// Need to ensure user_content is cleared/reset upon successful login.
+// Failed attempts are throttled server-side per client IP and per username,
+// so clearing the session cookie does not reset the counter.
 func (h *PageHandler) PostLogin(ctx *fasthttp.RequestCtx) {
 	username := string(ctx.FormValue("username"))
 	password := string(ctx.FormValue("password"))
+	ip := clientIP(ctx, h.cfg.TrustProxy)
+	keys := loginKeys(ip, username)
 
-	// Get session store first to handle attempts and lockout
 	store, err := h.sess.Get(ctx)
 	if err != nil {
 		log.Printf("PostLogin: Error getting session: %v", err)
-		ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 		return
 	}
 
 	// --- Check Lockout Status ---
-	var attempts int
-	var lastAttemptTime time.Time
-
-	// Get attempt count flexibly
-	if attemptsVal := store.Get("login_attempts"); attemptsVal != nil {
-		switch v := attemptsVal.(type) {
-		case int:
-			attempts = v
-		case float64: // Handle potential float64 if numbers go through JSON-like serialization
-			attempts = int(v)
-		case int64:
-			attempts = int(v)
-		case json.Number:
-			if i, err := v.Int64(); err == nil {
-				attempts = int(i)
-			} else {
-				log.Printf("PostLogin: Could not convert json.Number login_attempts '%s' to int64", v.String())
-			}
-		default:
-			log.Printf("PostLogin: Unexpected type for login_attempts in session: %T", attemptsVal)
-		}
-		log.Printf("PostLogin: Read attempts count from session: %d", attempts) // Debug log
-	}
-
-	// Get last attempt time
-	if lastAttemptVal := store.Get("last_login_attempt_time"); lastAttemptVal != nil {
-		if lastTimeStr, ok := lastAttemptVal.(string); ok {
-			lastAttemptTime, _ = time.Parse(time.RFC3339, lastTimeStr) // Ignore parse error for simplicity here
-		}
-	}
-
-	// Check if currently locked out
-	if attempts >= h.cfg.LoginLimitAttempt && !lastAttemptTime.IsZero() {
-		lockoutExpiry := lastAttemptTime.Add(h.cfg.LoginLockDuration)
-		if time.Now().Before(lockoutExpiry) {
-			// LOCKOUT ACTIVE
-			remaining := time.Until(lockoutExpiry).Round(time.Second)
-			log.Printf("PostLogin: Account locked for user '%s'. Redirecting to home. Time remaining: %v", username, remaining)
-
-			// Instead of showing message, redirect away immediately if already locked
-			ctx.Redirect("/", fasthttp.StatusSeeOther) // Redirect to home page
-			// Optionally, set a flash message for the home page if needed
-			// store.Set("flash_message", "Login attempt blocked due to too many failed attempts.")
-			// h.sess.Save(ctx, store) // Need to save if setting flash message
-			return
-		} else {
-			// Lockout expired, reset attempts before checking credentials
-			attempts = 0
-			store.Delete("login_attempts")
-			store.Delete("last_login_attempt_time")
-			store.Delete("login_lockout_message") // Clear any old lockout message
-			// No need to save yet, will be saved after credential check
+	if wait := h.limiter.Check(keys...); wait > 0 {
+		log.Printf("PostLogin: Login blocked for user '%s' from %s. Time remaining: %v", username, ip, wait.Round(time.Second))
+		store.Delete("login_error")
+		store.Set("login_lockout_message", lockoutMessage(wait))
+		if err := h.sess.Save(ctx, store); err != nil {
+			log.Printf("PostLogin: Error saving session with lockout message: %v", err)
 		}
+		ctx.Redirect("/login", fasthttp.StatusSeeOther)
+		return
 	}
 
 	// --- Check Credentials ---
-	if username == h.cfg.AuthUser && password == h.cfg.AuthPass {
+	user, err := h.users.Get(username)
+	if err != nil && !errors.Is(err, storage.ErrUserNotFound) {
+		log.Printf("PostLogin: Error loading user '%s': %v", username, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	// CheckPassword runs even for unknown users so timing does not reveal them
+	if auth.CheckPassword(user.PasswordHash, password) {
 		// --- Login Successful ---
-		log.Printf("PostLogin: Successful login for user '%s'", username)
-
-		// Get session store BEFORE regenerating
-		store, err := h.sess.Get(ctx)
-		if err != nil {
-			log.Printf("PostLogin Success: Error getting session before clearing: %v", err)
-			// Proceed, but might not clear old data if session was invalid
-		} else {
-			// Clear login attempt tracking & old user content
-			store.Delete("login_attempts")
-			store.Delete("last_login_attempt_time")
-			store.Delete("login_error")
-			store.Delete("login_lockout_message")
-			store.Delete("user_content") // <<--- IMPORTANT: Clear previous user content
-			// Save immediately after clearing and before regenerating
-			if errSave := h.sess.Save(ctx, store); errSave != nil {
-				log.Printf("PostLogin Success: Error saving session after clearing: %v", errSave)
-				// Non-fatal, try regenerating anyway
-			}
+		username = user.Username // Canonical casing
+		log.Printf("PostLogin: Successful login for user '%s' (%s)", username, user.Role)
+		// Only the account's counter is cleared; the IP's failures expire on
+		// their own, or one valid login would reset a password spray
+		h.limiter.Reset(keys[1])
+
+		// Clear any login messages before regenerating
+		store.Delete("login_error")
+		store.Delete("login_lockout_message")
+		if errSave := h.sess.Save(ctx, store); errSave != nil {
+			log.Printf("PostLogin Success: Error saving session after clearing: %v", errSave)
+			// Non-fatal, try regenerating anyway
 		}
 
+		// Drop the anonymous sandbox; the regenerated session starts fresh
+		h.resetSandbox(ctx)
+
 		// Regenerate session ID for security
 		if errRegen := h.sess.Regenerate(ctx); errRegen != nil {
 			log.Printf("PostLogin Success: Error regenerating session: %v", errRegen)
-			// Need to fetch the new store after failed regenerate?
-			// Let's assume we continue with the old store if regenerate fails,
-			// but log it. If Get fails below, it will be handled.
+			// Continue with the old session; Get below handles any failure
 		}
 
 		// Get the store again (might be new one after successful regenerate)
 		store, err = h.sess.Get(ctx)
 		if err != nil {
 			log.Printf("PostLogin Success: Error getting session after regenerate/clear: %v", err)
-			ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+			writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 			return
 		}
 
+		// Rotate the CSRF token; the next page view issues a fresh one
+		store.Delete(csrfSessionKey)
+
 		// Set authentication flag in the potentially new store
 		store.Set("authenticated", true)
-		store.Set("username", username) // Optional: store username
+		store.Set("username", username)
 
-		// Check for redirect URL (already deleted from old store if possible)
-		redirectURLVal := store.Get("redirect_url") // Check in current store
+		// Check for redirect URL
+		redirectURLVal := store.Get("redirect_url")
 		store.Delete("redirect_url")
 
 		// Save the session (contains auth flags, maybe cleared redirect_url)
 		if err = h.sess.Save(ctx, store); err != nil {
 			log.Printf("PostLogin Success: Error saving final session: %v", err)
-			ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+			writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
 			return
 		}
 
@@ -434,44 +506,31 @@ func (h *PageHandler) PostLogin(ctx *fasthttp.RequestCtx) {
 			ctx.Redirect("/content", fasthttp.StatusSeeOther) // Default redirect
 		}
 		return // Important: return after redirect
+	}
 
-	} else {
-		// --- Login Failed ---
-		attempts++ // Increment attempts
-		now := time.Now()
-		store.Set("login_attempts", attempts)
-		store.Set("last_login_attempt_time", now.Format(time.RFC3339))
-		store.Delete("login_lockout_message") // Clear any previous lockout message
-
-		log.Printf("PostLogin: Failed login attempt #%d for user '%s'", attempts, username)
-
-		// Set error message
-		errorMsg := "Invalid username or password."
-		if attempts >= h.cfg.LoginLimitAttempt {
-			lockoutExpiry := now.Add(h.cfg.LoginLockDuration)
-			remaining := time.Until(lockoutExpiry).Round(time.Second)
-			errorMsg = fmt.Sprintf("Too many failed login attempts. Please try again in %v.", remaining)
-			store.Set("login_lockout_message", errorMsg) // Also set lockout message for next GET
-			store.Delete("login_error")                  // Use lockout message instead of generic error
-			log.Printf("PostLogin: Account locked for user '%s'. Lockout duration: %v", username, h.cfg.LoginLockDuration)
-		} else {
-			remainingAttempts := h.cfg.LoginLimitAttempt - attempts
-			errorMsg = fmt.Sprintf("Invalid username or password. %d attempts remaining.", remainingAttempts)
-			store.Set("login_error", errorMsg) // Set error message for GET /login
-		}
+	// --- Login Failed ---
+	remaining, lock := h.limiter.Fail(keys...)
+	log.Printf("PostLogin: Failed login attempt for user '%s' from %s (%d attempts remaining)", username, ip, remaining)
 
-		// Save session with updated attempts/time/error
-		if err := h.sess.Save(ctx, store); err != nil {
-			log.Printf("PostLogin Failed: Error saving session with attempts: %v", err)
-		}
+	if lock > 0 {
+		log.Printf("PostLogin: Locked logins for user '%s' from %s. Lockout duration: %v", username, ip, lock)
+		store.Set("login_lockout_message", lockoutMessage(lock))
+		store.Delete("login_error") // Use lockout message instead of generic error
+	} else {
+		store.Set("login_error", fmt.Sprintf("Invalid username or password. %d attempts remaining.", remaining))
+		store.Delete("login_lockout_message")
+	}
 
-		// Redirect back to login form
-		ctx.Redirect("/login", fasthttp.StatusSeeOther)
+	// Save session with the message for GET /login
+	if err := h.sess.Save(ctx, store); err != nil {
+		log.Printf("PostLogin Failed: Error saving session with message: %v", err)
 	}
+
+	// Redirect back to login form
+	ctx.Redirect("/login", fasthttp.StatusSeeOther)
 }
 
 // Logout handles GET /logout - logs the user out.
-// Session destruction handles clearing user_content automatically.
 func (h *PageHandler) Logout(ctx *fasthttp.RequestCtx) {
 	// We don't strictly need to get the store before destroying,
 	// but it can be useful for logging the username if stored.
@@ -484,10 +543,13 @@ func (h *PageHandler) Logout(ctx *fasthttp.RequestCtx) {
 		log.Printf("Logout: Error getting session before destroy: %v", errGet)
 	}
 
+	// Free the session's sandbox before the session goes away
+	h.resetSandbox(ctx)
+
 	// Destroy the session
 	if err := h.sess.Destroy(ctx); err != nil {
 		log.Printf("Logout: Error destroying session: %v", err)
-		ctx.Error("Internal Server Error during logout", fasthttp.StatusInternalServerError)
+		writeError(ctx, "Internal Server Error during logout", fasthttp.StatusInternalServerError)
 		return
 	}
 
@@ -496,27 +558,16 @@ func (h *PageHandler) Logout(ctx *fasthttp.RequestCtx) {
 	ctx.Redirect("/login", fasthttp.StatusSeeOther)
 }
 
-// NotFound handles rendering the custom 404 page.
+// NotFound handles rendering the custom 404 page. API clients get problem
+// details instead.
 func (h *PageHandler) NotFound(ctx *fasthttp.RequestCtx) {
+	if !wantsHTML(ctx) {
+		writeError(ctx, "Not Found", fasthttp.StatusNotFound)
+		return
+	}
 	// data variable removed as it was unused
 	baseData := h.newBasePageData(ctx, "404 Not Found", "The requested page could not be found.")
 	ctx.SetStatusCode(fasthttp.StatusNotFound)
 	ctx.SetContentType("text/html; charset=utf-8")
 	pages.WriteNotFoundPage(ctx, &baseData) // Pass the base data directly
 }
-
-// Admin handles GET /admin - renders the placeholder admin page.
-func (h *PageHandler) Admin(ctx *fasthttp.RequestCtx) {
-	data := &models.BasePageData{}
-	*data = h.newBasePageData(ctx, "Admin Panel", "Admin section (under construction)")
-	ctx.SetContentType("text/html; charset=utf-8")
-	pages.WriteAdminPage(ctx, data)
-}
-
-// Settings handles GET /settings - renders the placeholder settings page.
-func (h *PageHandler) Settings(ctx *fasthttp.RequestCtx) {
-	data := &models.BasePageData{}
-	*data = h.newBasePageData(ctx, "Settings", "Settings page (under construction)")
-	ctx.SetContentType("text/html; charset=utf-8")
-	pages.WriteSettingsPage(ctx, data)
-}
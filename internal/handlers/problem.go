@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"cms/internal/models"
+	"cms/internal/templates/pages"
+	"cms/internal/validate"
+
+	"github.com/valyala/fasthttp"
+)
+
+// problemContentType is the media type of RFC 7807 problem details.
+const problemContentType = "application/problem+json"
+
+// writeError responds with status and msg, like ctx.Error: as problem
+// details to API clients and as the error page to browsers. msg is shown to
+// the client as is, so it must not carry internal errors.
+func writeError(ctx *fasthttp.RequestCtx, msg string, status int) {
+	writeProblem(ctx, models.Problem{Status: status, Detail: msg})
+}
+
+// writeProblem responds with p, filling in the members it leaves empty.
+// Anything already written is dropped, headers included, as with ctx.Error.
+func writeProblem(ctx *fasthttp.RequestCtx, p models.Problem) {
+	p = completeProblem(ctx, p)
+	if p.Status >= fasthttp.StatusInternalServerError {
+		log.Printf("Request %s: %s %s failed with %d", p.RequestID, ctx.Method(), ctx.Path(), p.Status)
+	}
+	if wantsHTML(ctx) {
+		writeErrorPage(ctx, p)
+		return
+	}
+	sendProblem(ctx, p.Status, p)
+}
+
+// completeProblem sets the type, title, instance and request ID of p.
+func completeProblem(ctx *fasthttp.RequestCtx, p models.Problem) models.Problem {
+	if p.Type == "" {
+		p.Type = models.ProblemBlank
+	}
+	if p.Title == "" {
+		p.Title = fasthttp.StatusMessage(p.Status)
+	}
+	if p.Detail == p.Title {
+		p.Detail = "" // e.g. a bare "Forbidden" adds nothing
+	}
+	if p.Instance == "" {
+		p.Instance = string(ctx.Path())
+	}
+	p.RequestID = requestID(ctx)
+	return p
+}
+
+// sendProblem writes body, a models.Problem or a struct extending one, as
+// application/problem+json.
+func sendProblem(ctx *fasthttp.RequestCtx, status int, body any) {
+	ctx.Response.Reset()
+	ctx.SetStatusCode(status)
+	ctx.SetContentType(problemContentType)
+	if err := json.NewEncoder(ctx).Encode(body); err != nil {
+		log.Printf("Problem: Error encoding response: %v", err)
+	}
+}
+
+// writeErrorPage renders p with the error page template.
+func writeErrorPage(ctx *fasthttp.RequestCtx, p models.Problem) {
+	user, ok := currentUser(ctx)
+	data := &models.ErrorData{
+		BasePageData: models.BasePageData{
+			PageTitle:       fmt.Sprintf("%d %s", p.Status, p.Title),
+			PageDescription: p.Detail,
+			AuthStatus:      ok,
+			Username:        user.Username,
+			UserRole:        user.Role,
+			CSRF:            csrfToken(ctx),
+		},
+		Problem: p,
+	}
+	ctx.Response.Reset()
+	ctx.SetStatusCode(p.Status)
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WriteErrorPage(ctx, data)
+}
+
+// wantsHTML reports whether an error should be shown as a page: always for
+// pages, and for API requests only when a browser submitted a form to them.
+// fetch calls accept */* and get problem details.
+func wantsHTML(ctx *fasthttp.RequestCtx) bool {
+	accept := string(ctx.Request.Header.Peek(fasthttp.HeaderAccept))
+	if strings.Contains(accept, "text/html") {
+		return true
+	}
+	if strings.HasPrefix(string(ctx.Path()), "/api/") {
+		return false
+	}
+	return !strings.Contains(accept, "json")
+}
+
+// validationProblem describes errs as a 400 validation problem with one
+// entry per field, sorted by field name.
+func validationProblem(errs validate.Errors) models.Problem {
+	p := models.Problem{Type: models.ProblemValidation, Status: fasthttp.StatusBadRequest, Detail: "Validation failed"}
+	for _, field := range slices.Sorted(maps.Keys(errs)) {
+		p.Errors = append(p.Errors, models.FieldError{Field: field, Detail: errs[field]})
+	}
+	return p
+}
+
+// invalidJSON responds with 400 to a request body that failed to decode
+// with err.
+func invalidJSON(ctx *fasthttp.RequestCtx, err error) {
+	p := models.Problem{Type: models.ProblemInvalidJSON, Status: fasthttp.StatusBadRequest}
+	var field string
+	p.Detail, field = jsonErrorDetail(err)
+	if field != "" {
+		p.Errors = []models.FieldError{{Field: field, Detail: p.Detail}}
+		p.Detail = "The request body has a field of the wrong type"
+	}
+	writeProblem(ctx, p)
+}
+
+// jsonErrorDetail describes a json.Unmarshal error for the client, and
+// names the field at fault, if any. The decoder's own messages name Go
+// types, so they are not passed on.
+func jsonErrorDetail(err error) (detail, field string) {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return fmt.Sprintf("Malformed JSON at byte %d", syntaxErr.Offset), ""
+	case errors.As(err, &typeErr) && typeErr.Field == "":
+		return "Expected " + jsonKind(typeErr.Type), ""
+	case errors.As(err, &typeErr):
+		return "must be " + jsonKind(typeErr.Type), typeErr.Field
+	}
+	return "Malformed JSON", ""
+}
+
+// jsonKind names the JSON value that decodes into t.
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	}
+	return "a different type"
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/url"
+	"time"
+
+	"cms/internal/models"
+	"cms/internal/storage"
+	"cms/internal/templates/pages"
+
+	"github.com/valyala/fasthttp"
+)
+
+// publicPageSize is the number of posts per page of the public index.
+const publicPageSize = 10
+
+// PublicIndex handles GET {prefix} - lists published items to readers,
+// most recently published first, without requiring login.
+func (h *PageHandler) PublicIndex(ctx *fasthttp.RequestCtx) {
+	store, err := h.publicStore(ctx)
+	if err != nil {
+		log.Printf("Public Index: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	cursor := string(ctx.QueryArgs().Peek("cursor"))
+	result, err := store.Query(storage.ListQuery{
+		PublicAt: time.Now().UTC(),
+		Sort:     "-" + storage.SortPublished,
+		Limit:    publicPageSize,
+		Cursor:   cursor,
+	})
+	if errors.Is(err, storage.ErrInvalidCursor) {
+		writeError(ctx, "Invalid cursor", fasthttp.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("Public Index: Error listing content: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	prefix := h.cfg.PublicPrefix
+	data := &models.PublicIndexData{
+		BasePageData: h.newBasePageData(ctx, "Latest Posts", "Recently published content"),
+		Prefix:       prefix,
+		Items:        result.Items,
+	}
+	if result.NextCursor != "" {
+		data.NextURL = prefix + "?cursor=" + url.QueryEscape(result.NextCursor)
+	}
+	if cursor != "" {
+		data.FirstURL = prefix
+	}
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WritePublicIndexPage(ctx, data)
+}
+
+// PublicPost handles GET {prefix}/{slug} - renders a published item to
+// readers. Drafts, items scheduled for later, expired and trashed items
+// are not found; a slug the item used before a rename redirects to its
+// current one.
+func (h *PageHandler) PublicPost(ctx *fasthttp.RequestCtx) {
+	s, _ := ctx.UserValue("slug").(string)
+
+	store, err := h.publicStore(ctx)
+	if err != nil {
+		log.Printf("Public Post: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	item, err := store.GetBySlug(s)
+	if errors.Is(err, storage.ErrNotFound) || (err == nil && !item.Public(time.Now().UTC())) {
+		h.NotFound(ctx)
+		return
+	}
+	if err != nil {
+		log.Printf("Public Post: Error getting content for slug %q: %v", s, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	if item.Slug != s {
+		ctx.Redirect(h.cfg.PublicPrefix+"/"+url.PathEscape(item.Slug), fasthttp.StatusMovedPermanently)
+		return
+	}
+
+	data := &models.PublicPostData{
+		BasePageData: h.newBasePageData(ctx, item.Title, "Published content"),
+		Prefix:       h.cfg.PublicPrefix,
+		Item:         item,
+		Body:         h.renderer.Item(item),
+		Tags:         lookupTerms(h.taxonomies, models.TaxonomyTags, item.Tags),
+		Categories:   lookupTerms(h.taxonomies, models.TaxonomyCategories, item.Categories),
+	}
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WritePublicPostPage(ctx, data)
+}
+
+// PublicTag handles GET {prefix}/tag/{slug} - lists the published items
+// with a tag, most recently published first.
+func (h *PageHandler) PublicTag(ctx *fasthttp.RequestCtx) {
+	h.publicArchive(ctx, models.TaxonomyTags, "tag")
+}
+
+// PublicCategory handles GET {prefix}/category/{slug} - lists the
+// published items in a category or any of its subcategories.
+func (h *PageHandler) PublicCategory(ctx *fasthttp.RequestCtx) {
+	h.publicArchive(ctx, models.TaxonomyCategories, "category")
+}
+
+// publicArchive renders one page of the public archive of a term, whose
+// URLs are {prefix}/{segment}/{slug}.
+func (h *PageHandler) publicArchive(ctx *fasthttp.RequestCtx, taxonomy, segment string) {
+	s, _ := ctx.UserValue("slug").(string)
+	term, err := h.taxonomies.Term(taxonomy, s)
+	if errors.Is(err, storage.ErrTermNotFound) {
+		h.NotFound(ctx)
+		return
+	}
+	if err != nil {
+		log.Printf("Public Archive: Error loading %s '%s': %v", taxonomy, s, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	cursor := string(ctx.QueryArgs().Peek("cursor"))
+	query := storage.ListQuery{
+		PublicAt: time.Now().UTC(),
+		Sort:     "-" + storage.SortPublished,
+		Limit:    publicPageSize,
+		Cursor:   cursor,
+	}
+	var children []models.Term
+	if taxonomy == models.TaxonomyTags {
+		query.Tag = term.Slug
+	} else {
+		query.Categories = []string{term.Slug}
+		err = expandCategories(h.taxonomies, &query)
+		var categories []models.Term
+		if err == nil {
+			categories, err = h.taxonomies.Terms(models.TaxonomyCategories)
+		}
+		for _, c := range categories {
+			if c.Parent == term.Slug {
+				children = append(children, c)
+			}
+		}
+	}
+	if err != nil {
+		log.Printf("Public Archive: Error loading subcategories of '%s': %v", term.Slug, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	store, err := h.publicStore(ctx)
+	if err != nil {
+		log.Printf("Public Archive: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	result, err := store.Query(query)
+	if errors.Is(err, storage.ErrInvalidCursor) {
+		writeError(ctx, "Invalid cursor", fasthttp.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("Public Archive: Error listing content for %s '%s': %v", taxonomy, term.Slug, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	prefix := h.cfg.PublicPrefix
+	archiveURL := prefix + "/" + segment + "/" + url.PathEscape(term.Slug)
+	data := &models.TermArchiveData{
+		BasePageData: h.newBasePageData(ctx, term.Name, term.Description),
+		Prefix:       prefix,
+		Taxonomy:     taxonomy,
+		Term:         term,
+		Children:     children,
+		Items:        result.Items,
+	}
+	if result.NextCursor != "" {
+		data.NextURL = archiveURL + "?cursor=" + url.QueryEscape(result.NextCursor)
+	}
+	if cursor != "" {
+		data.FirstURL = archiveURL
+	}
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WriteTermArchivePage(ctx, data)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cms/internal/storage"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Page sizes for content listings.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 200
+)
+
+// listQueryArgs are the query parameters understood by parseListQuery.
+var listQueryArgs = []string{"status", "q", "tag", "category", "created_after", "created_before", "sort", "limit", "cursor", "trashed"}
+
+// parseListQuery reads the listing query parameters: status, q, tag,
+// category, created_after, created_before, sort, limit, cursor and trashed.
+// The category filter is widened to subcategories by expandCategories.
+func parseListQuery(args *fasthttp.Args) (storage.ListQuery, error) {
+	q := storage.ListQuery{
+		Status:  string(args.Peek("status")),
+		Search:  string(args.Peek("q")),
+		Tag:     string(args.Peek("tag")),
+		Sort:    string(args.Peek("sort")),
+		Cursor:  string(args.Peek("cursor")),
+		Trashed: args.GetBool("trashed"),
+		Limit:   defaultPageSize,
+	}
+	if category := string(args.Peek("category")); category != "" {
+		q.Categories = []string{category}
+	}
+	if _, _, err := storage.ParseSort(q.Sort); err != nil {
+		return q, fmt.Errorf("invalid sort '%s': use updated_at, created_at, published_at or title, optionally prefixed with '-'", q.Sort)
+	}
+
+	var err error
+	if q.CreatedAfter, err = parseTimeArg(args, "created_after"); err != nil {
+		return q, err
+	}
+	if q.CreatedBefore, err = parseTimeArg(args, "created_before"); err != nil {
+		return q, err
+	}
+
+	if limitStr := string(args.Peek("limit")); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return q, fmt.Errorf("invalid limit '%s'", limitStr)
+		}
+		q.Limit = min(limit, maxPageSize)
+	}
+	return q, nil
+}
+
+// parseTimeArg parses an RFC 3339 timestamp or a YYYY-MM-DD date (UTC).
+// A missing parameter yields the zero time.
+func parseTimeArg(args *fasthttp.Args, name string) (time.Time, error) {
+	value := string(args.Peek(name))
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s '%s': use RFC 3339 or YYYY-MM-DD", name, value)
+}
+
+// queryError reports whether err came from a malformed query rather than
+// a storage failure.
+func queryError(err error) bool {
+	return errors.Is(err, storage.ErrInvalidSort) || errors.Is(err, storage.ErrInvalidCursor)
+}
+
+// listURL returns path with the current listing parameters and the given
+// cursor (none when empty).
+func listURL(ctx *fasthttp.RequestCtx, path, cursor string) string {
+	var args fasthttp.Args
+	for _, name := range listQueryArgs {
+		if value := ctx.QueryArgs().Peek(name); len(value) > 0 && name != "cursor" {
+			args.SetBytesV(name, value)
+		}
+	}
+	if cursor != "" {
+		args.Set("cursor", cursor)
+	}
+	if args.Len() == 0 {
+		return path
+	}
+	return path + "?" + args.String()
+}
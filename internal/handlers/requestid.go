@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Request ID header and user value. The ID ties an error response to the
+// server log lines of the request.
+const (
+	requestIDHeader    = "X-Request-ID"
+	requestIDCtxKey    = "request_id"
+	maxRequestIDLength = 64
+)
+
+// RequestIDMiddleware gives every request an ID, echoed in the X-Request-ID
+// response header and in error responses. An ID sent by the client or a
+// proxy in X-Request-ID is kept if it is short and plain enough to log.
+func RequestIDMiddleware(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		id := string(ctx.Request.Header.Peek(requestIDHeader))
+		if !validRequestID(id) {
+			var err error
+			if id, err = generateID(); err != nil {
+				log.Printf("RequestIDMiddleware: %v", err)
+				id = strconv.FormatUint(ctx.ID(), 16)
+			}
+		}
+		ctx.SetUserValue(requestIDCtxKey, id)
+		next(ctx)
+		// Set afterwards, as writeError and ctx.NotModified reset the headers
+		ctx.Response.Header.Set(requestIDHeader, id)
+	}
+}
+
+// requestID returns the ID RequestIDMiddleware gave the request.
+func requestID(ctx *fasthttp.RequestCtx) string {
+	id, _ := ctx.UserValue(requestIDCtxKey).(string)
+	return id
+}
+
+// validRequestID reports whether id is a non-empty run of letters, digits,
+// '-', '_' and '.', at most maxRequestIDLength long.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
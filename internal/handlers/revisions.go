@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"cms/internal/diff"
+	"cms/internal/models"
+	"cms/internal/storage"
+
+	"github.com/valyala/fasthttp"
+)
+
+// recordRevision stores a snapshot of item as saved by the current user,
+// with an optional comment. Failures are logged but do not fail the save
+// that triggered them.
+func recordRevision(ctx *fasthttp.RequestCtx, store storage.ContentStore, item models.Content, action, comment string) {
+	rev := models.Revision{
+		ContentID: item.ID,
+		Action:    action,
+		CreatedAt: item.UpdatedAt,
+		Comment:   comment,
+		Snapshot:  item,
+	}
+	if user, ok := currentUser(ctx); ok {
+		rev.Editor = user.Username
+	}
+	if _, err := store.AddRevision(rev); err != nil {
+		log.Printf("Revisions: Error recording %s revision of %s: %v", action, item.ID, err)
+	}
+}
+
+// recordBaseline stores the version of item being replaced when it has no
+// history yet (seeded or imported items), so the first edit can be undone.
+func recordBaseline(store storage.ContentStore, item models.Content) {
+	revs, err := store.Revisions(item.ID)
+	if err != nil {
+		log.Printf("Revisions: Error listing revisions of %s: %v", item.ID, err)
+		return
+	}
+	if len(revs) > 0 {
+		return
+	}
+	rev := models.Revision{
+		ContentID: item.ID,
+		Action:    models.RevisionCreate,
+		Editor:    item.Author,
+		CreatedAt: item.UpdatedAt,
+		Snapshot:  item,
+	}
+	if _, err := store.AddRevision(rev); err != nil {
+		log.Printf("Revisions: Error recording baseline revision of %s: %v", item.ID, err)
+	}
+}
+
+// Revisions handles GET /api/content/{id}/revisions - lists an item's
+// revisions, newest first.
+func (h *CRUDHandler) Revisions(ctx *fasthttp.RequestCtx) {
+	id, ok := ctx.UserValue("id").(string)
+	if !ok || id == "" {
+		writeError(ctx, "Missing or invalid content ID", fasthttp.StatusBadRequest)
+		return
+	}
+
+	store, err := h.contentStore(ctx)
+	if err != nil {
+		log.Printf("CRUD Revisions: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	if _, err := store.Get(id); errors.Is(err, storage.ErrNotFound) {
+		writeError(ctx, "Content not found", fasthttp.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Printf("CRUD Revisions: Error getting content for id %s: %v", id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	revs, err := store.Revisions(id)
+	if err != nil {
+		log.Printf("CRUD Revisions: Error listing revisions of %s: %v", id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	if revs == nil {
+		revs = []models.Revision{} // Encode as [] rather than null
+	}
+
+	ctx.SetContentType("application/json; charset=utf-8")
+	if err := json.NewEncoder(ctx).Encode(revs); err != nil {
+		log.Printf("CRUD Revisions: Error encoding revisions of %s: %v", id, err)
+	}
+}
+
+// Revision handles GET /api/content/{id}/revisions/{rev} - retrieves one revision.
+func (h *CRUDHandler) Revision(ctx *fasthttp.RequestCtx) {
+	id, number, ok := revisionParams(ctx)
+	if !ok {
+		return
+	}
+
+	store, err := h.contentStore(ctx)
+	if err != nil {
+		log.Printf("CRUD Revision: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	rev, err := store.Revision(id, number)
+	if errors.Is(err, storage.ErrRevisionNotFound) {
+		writeError(ctx, "Revision not found", fasthttp.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("CRUD Revision: Error getting revision %d of %s: %v", number, id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	ctx.SetContentType("application/json; charset=utf-8")
+	if err := json.NewEncoder(ctx).Encode(rev); err != nil {
+		log.Printf("CRUD Revision: Error encoding revision %d of %s: %v", number, id, err)
+	}
+}
+
+// RestoreRevision handles POST /api/content/{id}/revisions/{rev}/restore -
+// makes a revision's snapshot the current version. The restore is itself
+// recorded as a new revision, so it can be undone.
+func (h *CRUDHandler) RestoreRevision(ctx *fasthttp.RequestCtx) {
+	id, number, ok := revisionParams(ctx)
+	if !ok {
+		return
+	}
+
+	store, err := h.contentStore(ctx)
+	if err != nil {
+		log.Printf("CRUD Restore: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	current, err := store.Get(id)
+	if errors.Is(err, storage.ErrNotFound) {
+		writeError(ctx, "Content not found", fasthttp.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("CRUD Restore: Error getting content for id %s: %v", id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	if !canModify(ctx, current) {
+		writeError(ctx, "Forbidden", fasthttp.StatusForbidden)
+		return
+	}
+	if current.Trashed() {
+		writeError(ctx, "Content is in the trash; restore it first", fasthttp.StatusConflict)
+		return
+	}
+
+	rev, err := store.Revision(id, number)
+	if errors.Is(err, storage.ErrRevisionNotFound) {
+		writeError(ctx, "Revision not found", fasthttp.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("CRUD Restore: Error getting revision %d of %s: %v", number, id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	// The snapshot is saved like an edit of the current item: identity and
+	// ownership stay, and it must pass today's checks
+	restored := rev.Snapshot
+	restored.DeletedAt = time.Time{} // Restoring a trash revision brings back its content, not the trashing
+	// Categories deleted since the snapshot are dropped
+	if err := resolveTerms(h.taxonomies, &restored, false, !h.scoped); err != nil {
+		if termError(err) {
+			err = invalidField(termField(err), err)
+		} else {
+			err = fmt.Errorf("resolving terms for id %s: %w", id, err)
+		}
+		respondError(ctx, "CRUD Restore", err)
+		return
+	}
+	if err := h.prepareUpdate(ctx, store, current, &restored); err != nil {
+		respondError(ctx, "CRUD Restore", err)
+		return
+	}
+
+	if err := store.Update(restored); err != nil {
+		respondError(ctx, "CRUD Restore", storeError(err, restored))
+		return
+	}
+	restored.Version++
+	recordRevision(ctx, store, restored, models.RevisionRestore, "")
+	h.wakeScheduler(restored)
+
+	log.Printf("CRUD Restore: Restored %s to revision %d", id, number)
+	ctx.Redirect("/content/"+id+"?restored="+strconv.Itoa(number), fasthttp.StatusSeeOther)
+}
+
+// revisionParams reads the {id} and {rev} route parameters, responding with
+// 400 when either is invalid.
+func revisionParams(ctx *fasthttp.RequestCtx) (string, int, bool) {
+	id, ok := ctx.UserValue("id").(string)
+	if !ok || id == "" {
+		writeError(ctx, "Missing or invalid content ID", fasthttp.StatusBadRequest)
+		return "", 0, false
+	}
+	revStr, _ := ctx.UserValue("rev").(string)
+	number, err := strconv.Atoi(revStr)
+	if err != nil || number < 1 {
+		writeError(ctx, "Invalid revision number", fasthttp.StatusBadRequest)
+		return "", 0, false
+	}
+	return id, number, true
+}
+
+// compareRevisions builds the diff shown on the view page.
+func compareRevisions(from, to models.Revision) *models.RevisionDiff {
+	a, b := from.Snapshot, to.Snapshot
+	d := &models.RevisionDiff{
+		From: from,
+		To:   to,
+		Body: diff.Lines(a.Content, b.Content),
+	}
+	fields := []models.FieldChange{
+		{Field: "Title", From: a.Title, To: b.Title},
+		{Field: "Slug", From: a.Slug, To: b.Slug},
+		{Field: "Status", From: a.Status, To: b.Status},
+		{Field: "Format", From: a.Format, To: b.Format},
+		{Field: "Tags", From: strings.Join(a.Tags, ", "), To: strings.Join(b.Tags, ", ")},
+		{Field: "Categories", From: strings.Join(a.Categories, ", "), To: strings.Join(b.Categories, ", ")},
+	}
+	for _, f := range fields {
+		if f.From != f.To {
+			d.Fields = append(d.Fields, f)
+		}
+	}
+	return d
+}
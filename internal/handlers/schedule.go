@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"cms/internal/models"
+)
+
+// Errors returned by applySchedule.
+var (
+	errScheduleMissing = errors.New("scheduled items need a published_at time")
+	errScheduleExpiry  = errors.New("expires_at must be in the future and after published_at")
+)
+
+// scheduleField names the item field an applySchedule error is about.
+func scheduleField(err error) string {
+	if errors.Is(err, errScheduleExpiry) {
+		return "expires_at"
+	}
+	return "published_at"
+}
+
+// applySchedule settles the publish time and status of item before a save.
+// prev is the stored version (zero for new items). An omitted published_at
+// keeps the pending schedule or the time the item was first published.
+// Scheduled items whose time has come are published right away, and
+// published items with a future time become scheduled.
+func applySchedule(item *models.Content, prev models.Content, now time.Time) error {
+	if item.PublishedAt.IsZero() {
+		switch {
+		case prev.Status == models.StatusScheduled:
+			if item.Status == models.StatusScheduled {
+				item.PublishedAt = prev.PublishedAt
+			}
+		case prev.Status == models.StatusPublished:
+			item.PublishedAt = prev.PublishTime()
+		case !prev.PublishedAt.IsZero():
+			item.PublishedAt = prev.PublishedAt // Archived or back in draft
+		}
+	}
+
+	switch item.Status {
+	case models.StatusScheduled:
+		if item.PublishedAt.IsZero() {
+			return errScheduleMissing
+		}
+		if !item.PublishedAt.After(now) {
+			item.Status = models.StatusPublished
+		}
+	case models.StatusPublished:
+		if item.PublishedAt.IsZero() {
+			item.PublishedAt = now
+		} else if item.PublishedAt.After(now) {
+			item.Status = models.StatusScheduled
+		}
+	default:
+		return nil
+	}
+
+	if !item.ExpiresAt.IsZero() && (!item.ExpiresAt.After(now) || !item.ExpiresAt.After(item.PublishedAt)) {
+		return errScheduleExpiry
+	}
+	return nil
+}
+
+// wakeScheduler lets the scheduler know about item's publish or expiry
+// time, so the status changes when it falls due.
+func (h *CRUDHandler) wakeScheduler(item models.Content) {
+	if item.Status == models.StatusScheduled || !item.ExpiresAt.IsZero() {
+		h.scheduler.Wake()
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+
+	"cms/internal/models"
+	"cms/internal/templates/pages"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Search handles GET /api/search?q= - full-text search over content
+// outside the trash, best match first. Optional ?limit= caps the results.
+func (h *CRUDHandler) Search(ctx *fasthttp.RequestCtx) {
+	query := strings.TrimSpace(string(ctx.QueryArgs().Peek("q")))
+	if query == "" {
+		writeError(ctx, "Missing search query 'q'", fasthttp.StatusBadRequest)
+		return
+	}
+	limit := defaultPageSize
+	if limitStr := string(ctx.QueryArgs().Peek("limit")); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 {
+			writeError(ctx, "Invalid limit '"+limitStr+"'", fasthttp.StatusBadRequest)
+			return
+		}
+		limit = min(n, maxPageSize)
+	}
+
+	store, err := h.contentStore(ctx)
+	if err != nil {
+		log.Printf("CRUD Search: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	results, err := store.Search(query, limit)
+	if err != nil {
+		log.Printf("CRUD Search: Error searching for %q: %v", query, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	ctx.SetContentType("application/json; charset=utf-8")
+	if err := json.NewEncoder(ctx).Encode(results); err != nil {
+		log.Printf("CRUD Search: Error encoding results: %v", err)
+	}
+}
+
+// Search handles GET /search?q= - renders full-text search results.
+func (h *PageHandler) Search(ctx *fasthttp.RequestCtx) {
+	data := &models.SearchData{
+		BasePageData: h.newBasePageData(ctx, "Search", "Search content"),
+		Query:        strings.TrimSpace(string(ctx.QueryArgs().Peek("q"))),
+	}
+
+	if data.Query != "" {
+		store, err := h.contentStore(ctx)
+		if err != nil {
+			log.Printf("Page Search: Error resolving content store: %v", err)
+			writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+			return
+		}
+		if data.Results, err = store.Search(data.Query, defaultPageSize); err != nil {
+			log.Printf("Page Search: Error searching for %q: %v", data.Query, err)
+			writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+			return
+		}
+	}
+
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WriteSearchPage(ctx, data)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"cms/internal/auth"
+	"cms/internal/models"
+	"cms/internal/storage"
+	"cms/internal/templates/pages"
+
+	"github.com/valyala/fasthttp"
+)
+
+// maxTokenNameLength bounds API token names.
+const maxTokenNameLength = 64
+
+// settingsMessages maps ?message= keys to the flash text shown on /settings.
+var settingsMessages = map[string]string{
+	"revoked": "Token revoked.",
+}
+
+// Settings handles GET /settings - renders the user's settings and API tokens.
+func (h *PageHandler) Settings(ctx *fasthttp.RequestCtx) {
+	h.renderSettings(ctx, settingsMessages[string(ctx.QueryArgs().Peek("message"))], "", "")
+}
+
+// CreateToken handles POST /settings/tokens - issues an API token for the
+// current user. The token is displayed once and only its hash is stored.
+func (h *PageHandler) CreateToken(ctx *fasthttp.RequestCtx) {
+	user, _ := currentUser(ctx)
+	name := strings.TrimSpace(string(ctx.FormValue("name")))
+	if name == "" || len(name) > maxTokenNameLength {
+		h.renderSettings(ctx, "", "", "Token name is required (at most 64 characters).")
+		return
+	}
+
+	var scopes []string
+	for _, v := range ctx.PostArgs().PeekMulti("scopes") {
+		scope := string(v)
+		if !auth.ValidScope(scope) || !auth.Can(user.Role, auth.Permission(scope)) {
+			h.renderSettings(ctx, "", "", "Your role cannot grant the scope '"+scope+"'.")
+			return
+		}
+		scopes = append(scopes, scope)
+	}
+	if len(scopes) == 0 {
+		h.renderSettings(ctx, "", "", "Select at least one scope.")
+		return
+	}
+
+	token, raw, err := h.tokens.Issue(user.Username, name, scopes)
+	if err != nil {
+		log.Printf("Settings CreateToken: Error issuing token for '%s': %v", user.Username, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Settings CreateToken: Issued API token %s for '%s' (%s)", token.ID, user.Username, strings.Join(scopes, ","))
+	h.renderSettings(ctx, "Token created. Copy it now, it will not be shown again.", raw, "")
+}
+
+// RevokeToken handles POST /settings/tokens/{id}/revoke - revokes one of the
+// current user's API tokens.
+func (h *PageHandler) RevokeToken(ctx *fasthttp.RequestCtx) {
+	user, _ := currentUser(ctx)
+	id, _ := ctx.UserValue("id").(string)
+
+	if err := h.tokens.Delete(id, user.Username); err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			h.NotFound(ctx)
+			return
+		}
+		log.Printf("Settings RevokeToken: Error revoking token %s: %v", id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Settings RevokeToken: Revoked API token %s of '%s'", id, user.Username)
+	ctx.Redirect("/settings?message=revoked", fasthttp.StatusSeeOther)
+}
+
+// renderSettings renders the settings page with optional messages.
+func (h *PageHandler) renderSettings(ctx *fasthttp.RequestCtx, message, newToken, errorMessage string) {
+	user, _ := currentUser(ctx)
+	tokens, err := h.tokens.List(user.Username)
+	if err != nil {
+		log.Printf("Settings: Error listing tokens for '%s': %v", user.Username, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	// Offer only the scopes the user's role can grant
+	var scopes []string
+	for _, scope := range auth.TokenScopes {
+		if auth.Can(user.Role, scope) {
+			scopes = append(scopes, string(scope))
+		}
+	}
+
+	data := &models.SettingsData{
+		BasePageData: h.newBasePageData(ctx, "Settings", "Account settings and API tokens"),
+		Tokens:       tokens,
+		Scopes:       scopes,
+		NewToken:     newToken,
+		Message:      message,
+		ErrorMessage: errorMessage,
+	}
+	if errorMessage != "" {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+	}
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WriteSettingsPage(ctx, data)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"cms/internal/models"
+	"cms/internal/slug"
+	"cms/internal/storage"
+
+	"github.com/valyala/fasthttp"
+)
+
+// errInvalidSlug is returned by resolveSlug for malformed explicit slugs.
+var errInvalidSlug = errors.New("invalid slug: use lowercase letters, numbers and single hyphens, at most 80 characters")
+
+// slugLookup finds the item using a slug, as storage.ContentStore.GetBySlug does.
+type slugLookup interface {
+	GetBySlug(slug string) (models.Content, error)
+}
+
+// resolveSlug settles item.Slug before a save. An explicit slug must be
+// well-formed; uniqueness is left to the store. An empty slug keeps current
+// (the slug before an edit) or, for new items, is generated from the title
+// with a numeric suffix if needed.
+func resolveSlug(store slugLookup, item *models.Content, current string) error {
+	item.Slug = strings.TrimSpace(item.Slug)
+	switch {
+	case item.Slug != "":
+		if !slug.Valid(item.Slug) {
+			return errInvalidSlug
+		}
+		return nil
+	case current != "":
+		item.Slug = current
+		return nil
+	}
+
+	var lookupErr error
+	item.Slug = slug.Unique(slug.Make(item.Title), func(s string) bool {
+		other, err := store.GetBySlug(s)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			lookupErr = err
+			return false
+		}
+		// Slugs other items used before a rename are avoided too, so their
+		// redirects keep working
+		return err == nil && other.ID != item.ID
+	})
+	return lookupErr
+}
+
+// BySlug handles GET /api/content/by-slug/{slug} - retrieves a published
+// item whose publish time has come by slug without authentication. A slug the item used before a
+// rename answers with a 301 redirect to its current slug.
+func (h *CRUDHandler) BySlug(ctx *fasthttp.RequestCtx) {
+	s, _ := ctx.UserValue("slug").(string)
+
+	store, err := h.contentStore(ctx)
+	if err != nil {
+		log.Printf("CRUD BySlug: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	item, err := store.GetBySlug(s)
+	if err == nil && !item.Public(time.Now().UTC()) {
+		err = storage.ErrNotFound // Unpublished items are not revealed
+	}
+	if errors.Is(err, storage.ErrNotFound) {
+		writeError(ctx, "Content not found", fasthttp.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("CRUD BySlug: Error getting content for slug %q: %v", s, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	if item.Slug != s {
+		ctx.Redirect("/api/content/by-slug/"+url.PathEscape(item.Slug), fasthttp.StatusMovedPermanently)
+		return
+	}
+
+	ctx.SetContentType("application/json; charset=utf-8")
+	if err := json.NewEncoder(ctx).Encode(item); err != nil {
+		log.Printf("CRUD BySlug: Error encoding item %s: %v", item.ID, err)
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+
+	"cms/internal/config"
+	"cms/internal/storage"
+
+	session "github.com/fasthttp/session/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// storeResolver resolves the ContentStore serving the current request.
+// In sandbox mode every session (or API token) gets its own store; other
+// modes share one.
+type storeResolver struct {
+	sess    *session.Session
+	backend storage.Backend
+	scoped  bool // True when stores are keyed by session ID
+}
+
+func newStoreResolver(sess *session.Session, backend storage.Backend, cfg *config.Config) storeResolver {
+	return storeResolver{
+		sess:    sess,
+		backend: backend,
+		scoped:  cfg.StorageMode == config.StorageModeSandbox,
+	}
+}
+
+// contentStore returns the ContentStore for the current request.
+func (r *storeResolver) contentStore(ctx *fasthttp.RequestCtx) (storage.ContentStore, error) {
+	if !r.scoped {
+		return r.backend.Store("")
+	}
+
+	// Bearer requests have no session; each token gets its own sandbox
+	if token, ok := currentToken(ctx); ok {
+		return r.backend.Store("token:" + token.ID)
+	}
+
+	store, err := r.sess.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	scope := string(store.GetSessionID()) // Copy before Save resets the store
+
+	// A brand new session only gets its cookie on Save; persist it now so the
+	// sandbox created below is found again on the next request.
+	if store.Get("sandbox") == nil {
+		store.Set("sandbox", true)
+		if err := r.sess.Save(ctx, store); err != nil {
+			return nil, fmt.Errorf("failed to save session for sandbox: %w", err)
+		}
+	}
+
+	return r.backend.Store(scope)
+}
+
+// publicStore returns the ContentStore the public site reads. In the
+// sandbox that is the shared seed content, so anonymous readers and
+// crawlers get neither a session nor a sandbox of their own.
+func (r *storeResolver) publicStore(ctx *fasthttp.RequestCtx) (storage.ContentStore, error) {
+	if viewer, ok := r.backend.(storage.Viewer); ok && r.scoped {
+		return viewer.View(), nil
+	}
+	return r.contentStore(ctx)
+}
+
+// sharedEditable reports whether data that every session shares can be
+// changed: custom types and their items, taxonomy terms and media.
+// Sandboxes only isolate content, so there it is read-only.
+func (r *storeResolver) sharedEditable() bool {
+	return !r.scoped
+}
+
+// sharedWritable is sharedEditable for a request about to change shared
+// data, responding with 403 in the sandbox.
+func (r *storeResolver) sharedWritable(ctx *fasthttp.RequestCtx) bool {
+	if r.sharedEditable() {
+		return true
+	}
+	writeError(ctx, "Not available in the sandbox: custom types, taxonomies and media are shared by all visitors", fasthttp.StatusForbidden)
+	return false
+}
+
+// resetSandbox discards the sandbox bound to the current session, if any.
+// Returns false when the backend does not support resetting.
+func (r *storeResolver) resetSandbox(ctx *fasthttp.RequestCtx) bool {
+	resetter, ok := r.backend.(storage.Resetter)
+	if !ok || !r.scoped {
+		return false
+	}
+
+	if token, ok := currentToken(ctx); ok {
+		resetter.Reset("token:" + token.ID)
+		return true
+	}
+
+	store, err := r.sess.Get(ctx)
+	if err != nil {
+		log.Printf("resetSandbox: Error getting session: %v", err)
+		return true // Nothing to reset without a session
+	}
+	resetter.Reset(string(store.GetSessionID()))
+	return true
+}
@@ -0,0 +1,531 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"cms/internal/models"
+	"cms/internal/slug"
+	"cms/internal/storage"
+	"cms/internal/templates/pages"
+
+	"github.com/valyala/fasthttp"
+)
+
+const tagSuggestLimit = 10 // Tags returned by GET /api/tags?q=
+
+// taxonomyMessages maps ?message= keys to the flash text shown on /admin/taxonomies.
+var taxonomyMessages = map[string]string{
+	"created": "Term created.",
+	"updated": "Term updated.",
+	"deleted": "Term deleted and removed from its items.",
+}
+
+// Tags handles GET /api/tags - lists tags by name. With ?q= it returns
+// the few tags matching q, for autocompletion.
+func (h *CRUDHandler) Tags(ctx *fasthttp.RequestCtx) {
+	var tags []models.Term
+	var err error
+	if q := string(ctx.QueryArgs().Peek("q")); q != "" {
+		tags, err = h.taxonomies.Match(q, tagSuggestLimit)
+	} else {
+		tags, err = h.taxonomies.Terms(models.TaxonomyTags)
+	}
+	if err != nil {
+		log.Printf("CRUD Tags: Error listing tags: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	writeJSON(ctx, "CRUD Tags", nonNil(tags))
+}
+
+// Categories handles GET /api/categories - lists categories, each parent
+// followed by its children.
+func (h *CRUDHandler) Categories(ctx *fasthttp.RequestCtx) {
+	categories, err := h.taxonomies.Terms(models.TaxonomyCategories)
+	if err != nil {
+		log.Printf("CRUD Categories: Error listing categories: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	writeJSON(ctx, "CRUD Categories", nonNil(categories))
+}
+
+// Errors returned by resolveTerms for terms an item may not be saved with.
+var (
+	errInvalidTag      = errors.New("invalid tag")
+	errUnknownTag      = errors.New("unknown tag")
+	errUnknownCategory = errors.New("unknown category")
+)
+
+// resolveTerms turns the tags and categories of an item being saved, given
+// as names or slugs, into the slugs of existing terms. Missing tags are
+// created if createTags is set; otherwise, and for categories, missing
+// terms fail with errUnknownTag or errUnknownCategory, or are dropped when
+// strict is false (e.g. for old revisions and imports).
+func resolveTerms(taxonomies *storage.TaxonomyStore, item *models.Content, strict, createTags bool) error {
+	var tags []string
+	for _, name := range item.Tags {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		s := slug.Make(name)
+		if s == "" || utf8.RuneCountInString(name) > models.MaxTermName {
+			return fmt.Errorf("%w '%s'", errInvalidTag, name)
+		}
+		if slices.Contains(tags, s) {
+			continue
+		}
+		_, err := taxonomies.Term(models.TaxonomyTags, s)
+		if errors.Is(err, storage.ErrTermNotFound) && !createTags {
+			if strict {
+				return fmt.Errorf("%w '%s'; new tags cannot be created in the sandbox", errUnknownTag, name)
+			}
+			continue
+		}
+		if errors.Is(err, storage.ErrTermNotFound) {
+			err = taxonomies.CreateTerm(models.TaxonomyTags, models.Term{Slug: s, Name: name, CreatedAt: time.Now().UTC()})
+			if errors.Is(err, storage.ErrTermExists) {
+				err = nil // Created by a concurrent save
+			}
+		}
+		if err != nil {
+			return err
+		}
+		tags = append(tags, s)
+	}
+
+	var categories []string
+	for _, name := range item.Categories {
+		s := slug.Make(name)
+		if s == "" || slices.Contains(categories, s) {
+			continue
+		}
+		_, err := taxonomies.Term(models.TaxonomyCategories, s)
+		if errors.Is(err, storage.ErrTermNotFound) {
+			if strict {
+				return fmt.Errorf("%w '%s'", errUnknownCategory, name)
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		categories = append(categories, s)
+	}
+
+	item.Tags, item.Categories = tags, categories
+	return nil
+}
+
+// termError reports whether err from resolveTerms is the client's fault.
+func termError(err error) bool {
+	return errors.Is(err, errInvalidTag) || errors.Is(err, errUnknownTag) || errors.Is(err, errUnknownCategory)
+}
+
+// termField names the item field a termError is about.
+func termField(err error) string {
+	if errors.Is(err, errUnknownCategory) {
+		return "categories"
+	}
+	return "tags"
+}
+
+// expandCategories widens the category filter of q to the subcategories
+// of the requested category, so a category lists everything filed below it.
+func expandCategories(taxonomies *storage.TaxonomyStore, q *storage.ListQuery) error {
+	if len(q.Categories) != 1 {
+		return nil
+	}
+	slugs, err := taxonomies.Subtree(q.Categories[0])
+	if err != nil {
+		return err
+	}
+	q.Categories = slugs
+	return nil
+}
+
+// lookupTerms returns the terms with the given slugs, skipping any that no
+// longer exist.
+func lookupTerms(taxonomies *storage.TaxonomyStore, taxonomy string, slugs []string) []models.Term {
+	terms := make([]models.Term, 0, len(slugs))
+	for _, s := range slugs {
+		term, err := taxonomies.Term(taxonomy, s)
+		if err != nil {
+			if !errors.Is(err, storage.ErrTermNotFound) {
+				log.Printf("lookupTerms: Error loading %s '%s': %v", taxonomy, s, err)
+			}
+			continue
+		}
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// Taxonomies handles GET /admin/taxonomies - lists tags and categories
+// with the number of items filed under each.
+func (h *PageHandler) Taxonomies(ctx *fasthttp.RequestCtx) {
+	data := &models.TaxonomiesData{
+		Message: taxonomyMessages[string(ctx.QueryArgs().Peek("message"))],
+	}
+	h.renderTaxonomies(ctx, data, fasthttp.StatusOK)
+}
+
+// CreateTerm handles POST /admin/taxonomies/{taxonomy} - adds a tag or category.
+func (h *PageHandler) CreateTerm(ctx *fasthttp.RequestCtx) {
+	if !h.sharedWritable(ctx) {
+		return
+	}
+	taxonomy, ok := h.taxonomyParam(ctx)
+	if !ok {
+		return
+	}
+
+	term := termFromForm(ctx, taxonomy)
+	term.Slug = strings.TrimSpace(string(ctx.FormValue("slug")))
+	if term.Slug == "" {
+		term.Slug = slug.Make(term.Name)
+	}
+	reject := func(message string) {
+		data := &models.TaxonomiesData{ErrorMessage: message, Form: term, FormTaxonomy: taxonomy}
+		h.renderTaxonomies(ctx, data, fasthttp.StatusBadRequest)
+	}
+	if message := validateTerm(term); message != "" {
+		reject(message)
+		return
+	}
+
+	term.CreatedAt = time.Now().UTC()
+	err := h.taxonomies.CreateTerm(taxonomy, term)
+	switch {
+	case errors.Is(err, storage.ErrTermExists):
+		reject("A term with the slug '" + term.Slug + "' already exists.")
+		return
+	case errors.Is(err, storage.ErrInvalidParent):
+		reject("The parent category does not exist.")
+		return
+	case err != nil:
+		log.Printf("Admin CreateTerm: Error creating %s '%s': %v", taxonomy, term.Slug, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Admin CreateTerm: Created %s '%s'", taxonomy, term.Slug)
+	ctx.Redirect("/admin/taxonomies?message=created", fasthttp.StatusSeeOther)
+}
+
+// EditTerm handles GET /admin/taxonomies/{taxonomy}/{slug}/edit - renders
+// the form to rename or move a term.
+func (h *PageHandler) EditTerm(ctx *fasthttp.RequestCtx) {
+	taxonomy, term, ok := h.loadTerm(ctx)
+	if !ok {
+		return
+	}
+	h.renderTermForm(ctx, taxonomy, term, "")
+}
+
+// UpdateTerm handles POST /admin/taxonomies/{taxonomy}/{slug} - saves a
+// term's name, description and parent. The slug stays the same, so items
+// and links keep pointing at the term.
+func (h *PageHandler) UpdateTerm(ctx *fasthttp.RequestCtx) {
+	if !h.sharedWritable(ctx) {
+		return
+	}
+	taxonomy, current, ok := h.loadTerm(ctx)
+	if !ok {
+		return
+	}
+
+	term := termFromForm(ctx, taxonomy)
+	term.Slug = current.Slug
+	term.CreatedAt = current.CreatedAt
+	if message := validateTerm(term); message != "" {
+		h.renderTermForm(ctx, taxonomy, term, message)
+		return
+	}
+
+	err := h.taxonomies.UpdateTerm(taxonomy, term)
+	if errors.Is(err, storage.ErrInvalidParent) {
+		h.renderTermForm(ctx, taxonomy, term, "A category cannot be moved below itself or into a missing category.")
+		return
+	}
+	if err != nil {
+		log.Printf("Admin UpdateTerm: Error updating %s '%s': %v", taxonomy, term.Slug, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Admin UpdateTerm: Updated %s '%s'", taxonomy, term.Slug)
+	ctx.Redirect("/admin/taxonomies?message=updated", fasthttp.StatusSeeOther)
+}
+
+// DeleteTerm handles POST /admin/taxonomies/{taxonomy}/{slug}/delete -
+// removes a term and takes it off every item filed under it. Categories
+// with subcategories must be emptied first.
+func (h *PageHandler) DeleteTerm(ctx *fasthttp.RequestCtx) {
+	if !h.sharedWritable(ctx) {
+		return
+	}
+	taxonomy, term, ok := h.loadTerm(ctx)
+	if !ok {
+		return
+	}
+
+	err := h.taxonomies.DeleteTerm(taxonomy, term.Slug)
+	if errors.Is(err, storage.ErrTermHasChildren) {
+		data := &models.TaxonomiesData{ErrorMessage: "'" + term.Name + "' has subcategories. Move or delete them first."}
+		h.renderTaxonomies(ctx, data, fasthttp.StatusConflict)
+		return
+	}
+	if err != nil && !errors.Is(err, storage.ErrTermNotFound) {
+		log.Printf("Admin DeleteTerm: Error deleting %s '%s': %v", taxonomy, term.Slug, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	n, err := h.detachTerm(ctx, taxonomy, term.Slug)
+	if err != nil {
+		log.Printf("Admin DeleteTerm: Error removing %s '%s' from items: %v", taxonomy, term.Slug, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Admin DeleteTerm: Deleted %s '%s' and removed it from %d items", taxonomy, term.Slug, n)
+	ctx.Redirect("/admin/taxonomies?message=deleted", fasthttp.StatusSeeOther)
+}
+
+// detachTerm removes a deleted term from every item filed under it, in
+// every store the backend serves, trashed items included. Returns the
+// number of items changed.
+func (h *PageHandler) detachTerm(ctx *fasthttp.RequestCtx, taxonomy, term string) (int, error) {
+	var stores []storage.ContentStore
+	if enum, ok := h.backend.(storage.Enumerator); ok {
+		stores = enum.Stores()
+	} else {
+		store, err := h.contentStore(ctx)
+		if err != nil {
+			return 0, err
+		}
+		stores = []storage.ContentStore{store}
+	}
+
+	changed := 0
+	for _, store := range stores {
+		for _, trashed := range []bool{false, true} {
+			q := storage.ListQuery{Trashed: trashed}
+			if taxonomy == models.TaxonomyTags {
+				q.Tag = term
+			} else {
+				q.Categories = []string{term}
+			}
+			result, err := store.Query(q)
+			if err != nil {
+				return changed, err
+			}
+			for _, item := range result.Items {
+				item.SetTerms(taxonomy, withoutTerm(item.Terms(taxonomy), term))
+				if err := store.Update(item); err != nil && !errors.Is(err, storage.ErrNotFound) {
+					return changed, err
+				}
+				changed++
+			}
+		}
+	}
+	return changed, nil
+}
+
+// renderTaxonomies renders the tags and categories page with the given status.
+func (h *PageHandler) renderTaxonomies(ctx *fasthttp.RequestCtx, data *models.TaxonomiesData, status int) {
+	store, err := h.contentStore(ctx)
+	if err != nil {
+		log.Printf("Admin Taxonomies: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	items, err := store.List()
+	if err != nil {
+		log.Printf("Admin Taxonomies: Error listing content: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	tags, err := h.taxonomies.Terms(models.TaxonomyTags)
+	if err == nil {
+		var categories []models.Term
+		categories, err = h.taxonomies.Terms(models.TaxonomyCategories)
+		data.Categories = termEntries(categories, termCounts(items, models.TaxonomyCategories))
+	}
+	if err != nil {
+		log.Printf("Admin Taxonomies: Error listing terms: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	data.Tags = termEntries(tags, termCounts(items, models.TaxonomyTags))
+
+	data.BasePageData = h.newBasePageData(ctx, "Tags & Categories", "Group content by tags and categories")
+	ctx.SetStatusCode(status)
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WriteTaxonomiesPage(ctx, data)
+}
+
+// renderTermForm renders the term edit form, with an optional error.
+func (h *PageHandler) renderTermForm(ctx *fasthttp.RequestCtx, taxonomy string, term models.Term, errorMessage string) {
+	data := &models.TermFormData{
+		BasePageData: h.newBasePageData(ctx, "Edit: "+term.Name, "Edit a tag or category"),
+		Taxonomy:     taxonomy,
+		Term:         term,
+		ErrorMessage: errorMessage,
+	}
+	if taxonomy == models.TaxonomyCategories {
+		categories, err := h.taxonomies.Terms(models.TaxonomyCategories)
+		var below []string
+		if err == nil {
+			below, err = h.taxonomies.Subtree(term.Slug)
+		}
+		if err != nil {
+			log.Printf("Admin EditTerm: Error listing categories: %v", err)
+			writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+			return
+		}
+		// A category cannot move below itself
+		for _, entry := range termEntries(categories, nil) {
+			if !slices.Contains(below, entry.Term.Slug) {
+				data.Parents = append(data.Parents, entry)
+			}
+		}
+	}
+	if errorMessage != "" {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+	}
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WriteTermFormPage(ctx, data)
+}
+
+// taxonomyParam reads the {taxonomy} route parameter, responding with 404
+// for unknown taxonomies.
+func (h *PageHandler) taxonomyParam(ctx *fasthttp.RequestCtx) (string, bool) {
+	taxonomy, _ := ctx.UserValue("taxonomy").(string)
+	if !models.ValidTaxonomy(taxonomy) {
+		h.NotFound(ctx)
+		return "", false
+	}
+	return taxonomy, true
+}
+
+// loadTerm fetches the term named by the {taxonomy} and {slug} route
+// parameters, responding with 404 when it does not exist.
+func (h *PageHandler) loadTerm(ctx *fasthttp.RequestCtx) (string, models.Term, bool) {
+	taxonomy, ok := h.taxonomyParam(ctx)
+	if !ok {
+		return "", models.Term{}, false
+	}
+	s, _ := ctx.UserValue("slug").(string)
+	term, err := h.taxonomies.Term(taxonomy, s)
+	if errors.Is(err, storage.ErrTermNotFound) {
+		h.NotFound(ctx)
+		return "", term, false
+	}
+	if err != nil {
+		log.Printf("Admin: Error loading %s '%s': %v", taxonomy, s, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return "", term, false
+	}
+	return taxonomy, term, true
+}
+
+// termFromForm reads the name, description and (for categories) parent
+// fields of a term form.
+func termFromForm(ctx *fasthttp.RequestCtx, taxonomy string) models.Term {
+	term := models.Term{
+		Name:        strings.TrimSpace(string(ctx.FormValue("name"))),
+		Description: strings.TrimSpace(string(ctx.FormValue("description"))),
+	}
+	if taxonomy == models.TaxonomyCategories {
+		term.Parent = string(ctx.FormValue("parent"))
+	}
+	return term
+}
+
+// validateTerm returns why a term may not be saved, or "" if it may.
+func validateTerm(term models.Term) string {
+	switch {
+	case term.Name == "":
+		return "Name is required."
+	case utf8.RuneCountInString(term.Name) > models.MaxTermName:
+		return fmt.Sprintf("Name must be at most %d characters.", models.MaxTermName)
+	case !slug.Valid(term.Slug):
+		return "Slug must be lowercase letters, numbers and single hyphens, at most 80 characters."
+	case term.Parent == term.Slug:
+		return "A category cannot be its own parent."
+	}
+	return ""
+}
+
+// termEntries pairs terms with their depth in the category tree and their
+// item counts. Terms must be in the order returned by TaxonomyStore.Terms.
+func termEntries(terms []models.Term, counts map[string]int) []models.TermEntry {
+	depth := make(map[string]int, len(terms))
+	entries := make([]models.TermEntry, 0, len(terms))
+	for _, term := range terms {
+		d := 0
+		if parentDepth, ok := depth[term.Parent]; ok && term.Parent != "" {
+			d = parentDepth + 1
+		}
+		depth[term.Slug] = d
+		entries = append(entries, models.TermEntry{Term: term, Depth: d, Count: counts[term.Slug]})
+	}
+	return entries
+}
+
+// termCounts counts the items outside the trash filed under each term.
+func termCounts(items []models.Content, taxonomy string) map[string]int {
+	counts := make(map[string]int)
+	for _, item := range items {
+		if item.Trashed() {
+			continue
+		}
+		for _, term := range item.Terms(taxonomy) {
+			counts[term]++
+		}
+	}
+	return counts
+}
+
+// withoutTerm returns a copy of terms without term. Stores may share the
+// slices of the items they return, so they are never changed in place.
+func withoutTerm(terms []string, term string) []string {
+	out := make([]string, 0, len(terms))
+	for _, t := range terms {
+		if t != term {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// nonNil returns terms, or an empty slice so JSON lists encode as [].
+func nonNil(terms []models.Term) []models.Term {
+	if terms == nil {
+		return []models.Term{}
+	}
+	return terms
+}
+
+// termNames returns the names of the terms with the given slugs, falling
+// back to the slug for terms that no longer exist.
+func termNames(taxonomies *storage.TaxonomyStore, taxonomy string, slugs []string) []string {
+	names := make([]string, 0, len(slugs))
+	for _, s := range slugs {
+		if term, err := taxonomies.Term(taxonomy, s); err == nil {
+			names = append(names, term.Name)
+		} else {
+			names = append(names, s)
+		}
+	}
+	return names
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"cms/internal/models"
+	"cms/internal/storage"
+	"cms/internal/templates/pages"
+
+	"github.com/valyala/fasthttp"
+)
+
+// trashMessages maps ?message= keys to the flash text shown on /content/trash.
+var trashMessages = map[string]string{
+	"restored": "Item restored.",
+	"purged":   "Item permanently deleted.",
+}
+
+// Trash handles POST /api/content/{id}/trash - moves an item to the trash
+// from an HTML form.
+func (h *CRUDHandler) Trash(ctx *fasthttp.RequestCtx) {
+	if h.trash(ctx, "CRUD Trash") {
+		formDone(ctx, "/content?message=trashed")
+	}
+}
+
+// Restore handles POST /api/content/{id}/restore - takes an item out of
+// the trash, honoring If-Match.
+func (h *CRUDHandler) Restore(ctx *fasthttp.RequestCtx) {
+	store, item, ok := h.loadModifiable(ctx, "CRUD Restore")
+	if !ok {
+		return
+	}
+	if !checkIfMatch(ctx, item) {
+		return
+	}
+	if !item.Trashed() {
+		writeError(ctx, "Content is not in the trash", fasthttp.StatusConflict)
+		return
+	}
+
+	recordBaseline(store, item)
+	item.DeletedAt = time.Time{}
+	item.UpdatedAt = time.Now().UTC()
+	if err := store.Update(item); err != nil {
+		respondError(ctx, "CRUD Restore", storeError(err, item))
+		return
+	}
+	item.Version++
+	recordRevision(ctx, store, item, models.RevisionUntrash, "")
+	h.wakeScheduler(item)
+	log.Printf("CRUD Restore: Restored %s from the trash", item.ID)
+	formDone(ctx, "/content/trash?message=restored")
+}
+
+// Purge handles POST /api/content/{id}/purge - permanently deletes a
+// trashed item and its revisions, honoring If-Match.
+func (h *CRUDHandler) Purge(ctx *fasthttp.RequestCtx) {
+	store, item, ok := h.loadModifiable(ctx, "CRUD Purge")
+	if !ok {
+		return
+	}
+	if !checkIfMatch(ctx, item) {
+		return
+	}
+	if !item.Trashed() {
+		writeError(ctx, "Content is not in the trash; move it to the trash first", fasthttp.StatusConflict)
+		return
+	}
+
+	// Version-checked, so an item restored since it was loaded is kept
+	if err := store.Delete(item.ID, item.Version); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		respondError(ctx, "CRUD Purge", storeError(err, item))
+		return
+	}
+
+	log.Printf("CRUD Purge: Permanently deleted %s", item.ID)
+	formDone(ctx, "/content/trash?message=purged")
+}
+
+// trash sets DeletedAt on the item named by the {id} route parameter,
+// honoring If-Match. Trashing an item that is already in the trash is a no-op. It reports
+// whether the item is now in the trash; otherwise a response has been sent.
+func (h *CRUDHandler) trash(ctx *fasthttp.RequestCtx, logPrefix string) bool {
+	store, item, ok := h.loadModifiable(ctx, logPrefix)
+	if !ok {
+		return false
+	}
+	if !checkIfMatch(ctx, item) {
+		return false
+	}
+	if item.Trashed() {
+		return true
+	}
+
+	recordBaseline(store, item)
+	item.DeletedAt = time.Now().UTC()
+	item.UpdatedAt = item.DeletedAt
+	if err := store.Update(item); err != nil {
+		respondError(ctx, logPrefix, storeError(err, item))
+		return false
+	}
+	item.Version++
+	recordRevision(ctx, store, item, models.RevisionTrash, "")
+	log.Printf("%s: Moved %s to the trash", logPrefix, item.ID)
+	return true
+}
+
+// loadModifiable fetches the item named by the {id} route parameter and
+// checks that the current user may modify it, responding with 400, 403,
+// 404 or 500 otherwise.
+func (h *CRUDHandler) loadModifiable(ctx *fasthttp.RequestCtx, logPrefix string) (storage.ContentStore, models.Content, bool) {
+	id, ok := ctx.UserValue("id").(string)
+	if !ok || id == "" {
+		writeError(ctx, "Missing or invalid content ID", fasthttp.StatusBadRequest)
+		return nil, models.Content{}, false
+	}
+
+	store, err := h.contentStore(ctx)
+	if err != nil {
+		log.Printf("%s: Error resolving content store: %v", logPrefix, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return nil, models.Content{}, false
+	}
+
+	item, err := store.Get(id)
+	if errors.Is(err, storage.ErrNotFound) {
+		writeError(ctx, "Content not found", fasthttp.StatusNotFound)
+		return nil, item, false
+	}
+	if err != nil {
+		log.Printf("%s: Error getting content for id %s: %v", logPrefix, id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return nil, item, false
+	}
+	if !canModify(ctx, item) {
+		writeError(ctx, "Forbidden", fasthttp.StatusForbidden)
+		return nil, item, false
+	}
+	return store, item, true
+}
+
+// formDone finishes an action that HTML forms post to: browser requests are
+// redirected to location, bearer-token API calls get 204 No Content.
+func formDone(ctx *fasthttp.RequestCtx, location string) {
+	if _, ok := currentToken(ctx); ok {
+		ctx.SetStatusCode(fasthttp.StatusNoContent)
+		return
+	}
+	ctx.Redirect(location, fasthttp.StatusSeeOther)
+}
+
+// TrashPage handles GET /content/trash - lists trashed items with restore
+// and permanent-delete actions.
+func (h *PageHandler) TrashPage(ctx *fasthttp.RequestCtx) {
+	store, err := h.contentStore(ctx)
+	if err != nil {
+		log.Printf("Page Trash: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	result, err := store.Query(storage.ListQuery{Trashed: true})
+	if err != nil {
+		log.Printf("Page Trash: Error listing content: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	data := &models.TrashData{
+		BasePageData: h.newBasePageData(ctx, "Trash", "Deleted content items"),
+		Message:      trashMessages[string(ctx.QueryArgs().Peek("message"))],
+	}
+	for _, item := range result.Items {
+		entry := models.TrashEntry{Item: item, CanModify: canModify(ctx, item)}
+		if h.cfg.TrashRetention > 0 {
+			entry.PurgeAt = item.DeletedAt.Add(h.cfg.TrashRetention)
+		}
+		data.Entries = append(data.Entries, entry)
+	}
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WriteTrashPage(ctx, data)
+}
@@ -0,0 +1,646 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"cms/internal/auth"
+	"cms/internal/models"
+	"cms/internal/schema"
+	"cms/internal/storage"
+	"cms/internal/templates/pages"
+
+	"github.com/valyala/fasthttp"
+)
+
+// maxItemColumns limits the fields shown in the item list table.
+const maxItemColumns = 4
+
+// typeMessages maps ?message= keys to the flash text shown on the type pages.
+var typeMessages = map[string]string{
+	"created": "Item created.",
+	"updated": "Item updated.",
+	"deleted": "Item deleted.",
+}
+
+// ListTypes handles GET /api/types - lists the custom content types.
+func (h *CRUDHandler) ListTypes(ctx *fasthttp.RequestCtx) {
+	types, err := h.types.Types()
+	if err != nil {
+		log.Printf("CRUD ListTypes: Error listing types: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	if types == nil {
+		types = []schema.Type{}
+	}
+	writeJSON(ctx, "CRUD ListTypes", types)
+}
+
+// GetType handles GET /api/types/{type} - retrieves a type definition.
+func (h *CRUDHandler) GetType(ctx *fasthttp.RequestCtx) {
+	t, ok := h.loadType(ctx, "CRUD GetType")
+	if !ok {
+		return
+	}
+	writeJSON(ctx, "CRUD GetType", t)
+}
+
+// CreateType handles POST /api/types - defines a new content type from a
+// JSON body, or YAML with a YAML Content-Type.
+func (h *CRUDHandler) CreateType(ctx *fasthttp.RequestCtx) {
+	if !h.sharedWritable(ctx) {
+		return
+	}
+	t, err := schema.Parse(ctx.PostBody(), isYAMLRequest(ctx))
+	if err != nil {
+		writeValidationError(ctx, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	t.CreatedAt = now
+	t.UpdatedAt = now
+	if err := h.types.CreateType(t); err != nil {
+		if errors.Is(err, storage.ErrTypeExists) {
+			writeError(ctx, "Content type already exists", fasthttp.StatusConflict)
+			return
+		}
+		log.Printf("CRUD CreateType: Error creating type %s: %v", t.Name, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("CRUD CreateType: Created type %s with %d fields", t.Name, len(t.Fields))
+	ctx.SetStatusCode(fasthttp.StatusCreated)
+	writeJSON(ctx, "CRUD CreateType", t)
+}
+
+// UpdateType handles PUT /api/types/{type} - replaces a type definition.
+// The name cannot change. Existing items are checked against the new
+// fields the next time they are saved.
+func (h *CRUDHandler) UpdateType(ctx *fasthttp.RequestCtx) {
+	if !h.sharedWritable(ctx) {
+		return
+	}
+	current, ok := h.loadType(ctx, "CRUD UpdateType")
+	if !ok {
+		return
+	}
+
+	t, err := schema.Parse(ctx.PostBody(), isYAMLRequest(ctx))
+	if err != nil && !(t.Name == "" && onlyNameError(err)) {
+		writeValidationError(ctx, err)
+		return
+	}
+	if t.Name == "" {
+		// The name comes from the URL when the body leaves it out
+		t.Name = current.Name
+		if err := t.Check(); err != nil {
+			writeValidationError(ctx, err)
+			return
+		}
+	}
+	if t.Name != current.Name {
+		writeError(ctx, "Content type name cannot be changed", fasthttp.StatusBadRequest)
+		return
+	}
+
+	t.CreatedAt = current.CreatedAt
+	t.UpdatedAt = time.Now().UTC()
+	if err := h.types.UpdateType(t); err != nil {
+		log.Printf("CRUD UpdateType: Error updating type %s: %v", t.Name, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("CRUD UpdateType: Updated type %s", t.Name)
+	writeJSON(ctx, "CRUD UpdateType", t)
+}
+
+// DeleteType handles DELETE /api/types/{type} - removes a type that has no items.
+func (h *CRUDHandler) DeleteType(ctx *fasthttp.RequestCtx) {
+	if !h.sharedWritable(ctx) {
+		return
+	}
+	name, _ := ctx.UserValue("type").(string)
+	err := h.types.DeleteType(name)
+	switch {
+	case errors.Is(err, storage.ErrTypeNotFound):
+		writeError(ctx, "Content type not found", fasthttp.StatusNotFound)
+	case errors.Is(err, storage.ErrTypeInUse):
+		writeError(ctx, "Content type still has items; delete them first", fasthttp.StatusConflict)
+	case err != nil:
+		log.Printf("CRUD DeleteType: Error deleting type %s: %v", name, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+	default:
+		log.Printf("CRUD DeleteType: Deleted type %s", name)
+		ctx.SetStatusCode(fasthttp.StatusNoContent)
+	}
+}
+
+// ListItems handles GET /api/types/{type}/items - lists the items of a
+// type, most recently updated first.
+func (h *CRUDHandler) ListItems(ctx *fasthttp.RequestCtx) {
+	t, ok := h.loadType(ctx, "CRUD ListItems")
+	if !ok {
+		return
+	}
+	items, err := h.types.Items(t.Name)
+	if err != nil {
+		log.Printf("CRUD ListItems: Error listing %s items: %v", t.Name, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	if items == nil {
+		items = []models.Item{}
+	}
+	writeJSON(ctx, "CRUD ListItems", items)
+}
+
+// GetItem handles GET /api/types/{type}/items/{id} - retrieves an item.
+func (h *CRUDHandler) GetItem(ctx *fasthttp.RequestCtx) {
+	_, item, ok := h.loadItem(ctx, "CRUD GetItem")
+	if !ok {
+		return
+	}
+	writeJSON(ctx, "CRUD GetItem", item)
+}
+
+// CreateItem handles POST /api/types/{type}/items - creates an item from
+// a body like {"values": {"price": 10}}. Invalid values are reported per
+// field with 400.
+func (h *CRUDHandler) CreateItem(ctx *fasthttp.RequestCtx) {
+	if !h.sharedWritable(ctx) {
+		return
+	}
+	t, ok := h.loadType(ctx, "CRUD CreateItem")
+	if !ok {
+		return
+	}
+	values, ok := decodeItemValues(ctx)
+	if !ok {
+		return
+	}
+	values, err := t.Validate(values)
+	if err != nil {
+		writeValidationError(ctx, err)
+		return
+	}
+
+	id, err := generateID()
+	if err != nil {
+		log.Printf("CRUD CreateItem: Error generating ID: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	now := time.Now().UTC()
+	item := models.Item{ID: id, Type: t.Name, Values: values, CreatedAt: now, UpdatedAt: now}
+	if user, ok := currentUser(ctx); ok {
+		item.Author = user.Username
+	}
+	if err := h.types.CreateItem(item); err != nil {
+		log.Printf("CRUD CreateItem: Error creating %s item: %v", t.Name, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("CRUD CreateItem: Created %s item %s", t.Name, id)
+	ctx.SetStatusCode(fasthttp.StatusCreated)
+	writeJSON(ctx, "CRUD CreateItem", item)
+}
+
+// UpdateItem handles PUT /api/types/{type}/items/{id} - replaces an
+// item's values. Fields left out are cleared or reset to their default.
+func (h *CRUDHandler) UpdateItem(ctx *fasthttp.RequestCtx) {
+	if !h.sharedWritable(ctx) {
+		return
+	}
+	t, item, ok := h.loadItem(ctx, "CRUD UpdateItem")
+	if !ok {
+		return
+	}
+	if !canModifyAuthored(ctx, item.Author) {
+		writeError(ctx, "Forbidden", fasthttp.StatusForbidden)
+		return
+	}
+	values, ok := decodeItemValues(ctx)
+	if !ok {
+		return
+	}
+	values, err := t.Validate(values)
+	if err != nil {
+		writeValidationError(ctx, err)
+		return
+	}
+
+	item.Values = values
+	item.UpdatedAt = time.Now().UTC()
+	if err := h.types.UpdateItem(item); err != nil {
+		log.Printf("CRUD UpdateItem: Error updating %s item %s: %v", t.Name, item.ID, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("CRUD UpdateItem: Updated %s item %s", t.Name, item.ID)
+	writeJSON(ctx, "CRUD UpdateItem", item)
+}
+
+// DeleteItem handles DELETE /api/types/{type}/items/{id} - removes an item.
+func (h *CRUDHandler) DeleteItem(ctx *fasthttp.RequestCtx) {
+	if !h.sharedWritable(ctx) {
+		return
+	}
+	t, item, ok := h.loadItem(ctx, "CRUD DeleteItem")
+	if !ok {
+		return
+	}
+	if !canModifyAuthored(ctx, item.Author) {
+		writeError(ctx, "Forbidden", fasthttp.StatusForbidden)
+		return
+	}
+	if err := h.types.DeleteItem(t.Name, item.ID); err != nil && !errors.Is(err, storage.ErrItemNotFound) {
+		log.Printf("CRUD DeleteItem: Error deleting %s item %s: %v", t.Name, item.ID, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	log.Printf("CRUD DeleteItem: Deleted %s item %s", t.Name, item.ID)
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+// loadType fetches the type named by the {type} route parameter,
+// responding with 404 or 500 otherwise.
+func (h *CRUDHandler) loadType(ctx *fasthttp.RequestCtx, logPrefix string) (schema.Type, bool) {
+	name, _ := ctx.UserValue("type").(string)
+	t, err := h.types.Type(name)
+	if errors.Is(err, storage.ErrTypeNotFound) {
+		writeError(ctx, "Content type not found", fasthttp.StatusNotFound)
+		return t, false
+	}
+	if err != nil {
+		log.Printf("%s: Error loading type %s: %v", logPrefix, name, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return t, false
+	}
+	return t, true
+}
+
+// loadItem fetches the type and item named by the {type} and {id} route
+// parameters, responding with 404 or 500 otherwise.
+func (h *CRUDHandler) loadItem(ctx *fasthttp.RequestCtx, logPrefix string) (schema.Type, models.Item, bool) {
+	t, ok := h.loadType(ctx, logPrefix)
+	if !ok {
+		return t, models.Item{}, false
+	}
+	id, _ := ctx.UserValue("id").(string)
+	item, err := h.types.Item(t.Name, id)
+	if errors.Is(err, storage.ErrItemNotFound) {
+		writeError(ctx, "Item not found", fasthttp.StatusNotFound)
+		return t, item, false
+	}
+	if err != nil {
+		log.Printf("%s: Error loading %s item %s: %v", logPrefix, t.Name, id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return t, item, false
+	}
+	return t, item, true
+}
+
+// decodeItemValues reads the "values" object of an item request body.
+func decodeItemValues(ctx *fasthttp.RequestCtx) (map[string]any, bool) {
+	var body struct {
+		Values map[string]any `json:"values"`
+	}
+	if err := json.Unmarshal(ctx.PostBody(), &body); err != nil {
+		invalidJSON(ctx, err)
+		return nil, false
+	}
+	if body.Values == nil {
+		body.Values = map[string]any{}
+	}
+	return body.Values, true
+}
+
+// isYAMLRequest reports whether the request body is YAML.
+func isYAMLRequest(ctx *fasthttp.RequestCtx) bool {
+	contentType := string(ctx.Request.Header.ContentType())
+	return strings.Contains(contentType, "yaml")
+}
+
+// onlyNameError reports whether err complains about the type name alone.
+func onlyNameError(err error) bool {
+	var errs schema.Errors
+	if !errors.As(err, &errs) {
+		return false
+	}
+	_, ok := errs["name"]
+	return ok && len(errs) == 1
+}
+
+// writeValidationError responds with 400, listing per-field errors in the
+// "errors" member of the problem details.
+func writeValidationError(ctx *fasthttp.RequestCtx, err error) {
+	var errs schema.Errors
+	if !errors.As(err, &errs) {
+		writeError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	writeProblem(ctx, validationProblem(errs))
+}
+
+// writeJSON encodes v as the JSON response body.
+func writeJSON(ctx *fasthttp.RequestCtx, logPrefix string, v any) {
+	ctx.SetContentType("application/json; charset=utf-8")
+	if err := json.NewEncoder(ctx).Encode(v); err != nil {
+		log.Printf("%s: Error encoding response: %v", logPrefix, err)
+		if !ctx.Response.Header.IsHTTP11() {
+			writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		}
+	}
+}
+
+// TypesPage handles GET /types - lists the custom content types.
+func (h *PageHandler) TypesPage(ctx *fasthttp.RequestCtx) {
+	types, err := h.types.Types()
+	if err != nil {
+		log.Printf("Page Types: Error listing types: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	counts, err := h.types.Counts()
+	if err != nil {
+		log.Printf("Page Types: Error counting items: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	data := &models.TypesData{
+		BasePageData: h.newBasePageData(ctx, "Content Types", "Custom content types"),
+		Types:        types,
+		Counts:       counts,
+		CanManage:    can(ctx, auth.PermTypesManage) && h.sharedEditable(),
+	}
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WriteTypesPage(ctx, data)
+}
+
+// ItemsPage handles GET /types/{type} - lists the items of a type.
+func (h *PageHandler) ItemsPage(ctx *fasthttp.RequestCtx) {
+	t, ok := h.loadPageType(ctx, "Page Items")
+	if !ok {
+		return
+	}
+	items, err := h.types.Items(t.Name)
+	if err != nil {
+		log.Printf("Page Items: Error listing %s items: %v", t.Name, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	data := &models.ItemsData{
+		BasePageData: h.newBasePageData(ctx, t.DisplayLabel(), t.Description),
+		Type:         t,
+		Items:        items,
+		Columns:      t.Fields,
+		CanCreate:    can(ctx, auth.PermContentWrite) && h.sharedEditable(),
+		Editable:     make(map[string]bool, len(items)),
+		Message:      typeMessages[string(ctx.QueryArgs().Peek("message"))],
+	}
+	for _, item := range items {
+		data.Editable[item.ID] = h.sharedEditable() && canModifyAuthored(ctx, item.Author)
+	}
+	if len(data.Columns) > maxItemColumns {
+		data.Columns = data.Columns[:maxItemColumns]
+	}
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WriteItemsPage(ctx, data)
+}
+
+// NewItem handles GET /types/{type}/new - renders the generated form for a new item.
+func (h *PageHandler) NewItem(ctx *fasthttp.RequestCtx) {
+	t, ok := h.loadPageType(ctx, "Page NewItem")
+	if !ok {
+		return
+	}
+	h.renderItemForm(ctx, t, models.Item{Type: t.Name}, true, itemForm(t, defaultValues(t)), nil)
+}
+
+// CreateItem handles POST /types/{type}/new - creates an item from the
+// generated form, showing it again with per-field errors if invalid.
+func (h *PageHandler) CreateItem(ctx *fasthttp.RequestCtx) {
+	if !h.sharedWritable(ctx) {
+		return
+	}
+	t, ok := h.loadPageType(ctx, "Page CreateItem")
+	if !ok {
+		return
+	}
+	item := models.Item{Type: t.Name}
+	values, err := t.Validate(t.FromForm(formLookup(ctx)))
+	if err != nil {
+		h.renderItemForm(ctx, t, item, true, submittedForm(ctx, t), validationErrors(err))
+		return
+	}
+
+	id, err := generateID()
+	if err != nil {
+		log.Printf("Page CreateItem: Error generating ID: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	now := time.Now().UTC()
+	item.ID = id
+	item.Values = values
+	item.CreatedAt = now
+	item.UpdatedAt = now
+	if user, ok := currentUser(ctx); ok {
+		item.Author = user.Username
+	}
+	if err := h.types.CreateItem(item); err != nil {
+		log.Printf("Page CreateItem: Error creating %s item: %v", t.Name, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Page CreateItem: Created %s item %s", t.Name, id)
+	ctx.Redirect("/types/"+t.Name+"?message=created", fasthttp.StatusSeeOther)
+}
+
+// EditItem handles GET /types/{type}/{id}/edit - renders the generated
+// form for an existing item.
+func (h *PageHandler) EditItem(ctx *fasthttp.RequestCtx) {
+	t, item, ok := h.loadModifiableItem(ctx, "Page EditItem")
+	if !ok {
+		return
+	}
+	h.renderItemForm(ctx, t, item, false, itemForm(t, item.Values), nil)
+}
+
+// UpdateItem handles POST /types/{type}/{id}/edit - saves the generated form.
+func (h *PageHandler) UpdateItem(ctx *fasthttp.RequestCtx) {
+	if !h.sharedWritable(ctx) {
+		return
+	}
+	t, item, ok := h.loadModifiableItem(ctx, "Page UpdateItem")
+	if !ok {
+		return
+	}
+	values, err := t.Validate(t.FromForm(formLookup(ctx)))
+	if err != nil {
+		h.renderItemForm(ctx, t, item, false, submittedForm(ctx, t), validationErrors(err))
+		return
+	}
+
+	item.Values = values
+	item.UpdatedAt = time.Now().UTC()
+	if err := h.types.UpdateItem(item); err != nil {
+		log.Printf("Page UpdateItem: Error updating %s item %s: %v", t.Name, item.ID, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Page UpdateItem: Updated %s item %s", t.Name, item.ID)
+	ctx.Redirect("/types/"+t.Name+"?message=updated", fasthttp.StatusSeeOther)
+}
+
+// DeleteItem handles POST /types/{type}/{id}/delete - removes an item.
+func (h *PageHandler) DeleteItem(ctx *fasthttp.RequestCtx) {
+	if !h.sharedWritable(ctx) {
+		return
+	}
+	t, item, ok := h.loadModifiableItem(ctx, "Page DeleteItem")
+	if !ok {
+		return
+	}
+	if err := h.types.DeleteItem(t.Name, item.ID); err != nil && !errors.Is(err, storage.ErrItemNotFound) {
+		log.Printf("Page DeleteItem: Error deleting %s item %s: %v", t.Name, item.ID, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	log.Printf("Page DeleteItem: Deleted %s item %s", t.Name, item.ID)
+	ctx.Redirect("/types/"+t.Name+"?message=deleted", fasthttp.StatusSeeOther)
+}
+
+// loadPageType fetches the type named by the {type} route parameter,
+// rendering the 404 page when it does not exist.
+func (h *PageHandler) loadPageType(ctx *fasthttp.RequestCtx, logPrefix string) (schema.Type, bool) {
+	name, _ := ctx.UserValue("type").(string)
+	t, err := h.types.Type(name)
+	if errors.Is(err, storage.ErrTypeNotFound) {
+		h.NotFound(ctx)
+		return t, false
+	}
+	if err != nil {
+		log.Printf("%s: Error loading type %s: %v", logPrefix, name, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return t, false
+	}
+	return t, true
+}
+
+// loadModifiableItem fetches the type and item named by the route and
+// checks that the current user may modify the item.
+func (h *PageHandler) loadModifiableItem(ctx *fasthttp.RequestCtx, logPrefix string) (schema.Type, models.Item, bool) {
+	t, ok := h.loadPageType(ctx, logPrefix)
+	if !ok {
+		return t, models.Item{}, false
+	}
+	id, _ := ctx.UserValue("id").(string)
+	item, err := h.types.Item(t.Name, id)
+	if errors.Is(err, storage.ErrItemNotFound) {
+		h.NotFound(ctx)
+		return t, item, false
+	}
+	if err != nil {
+		log.Printf("%s: Error loading %s item %s: %v", logPrefix, t.Name, id, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return t, item, false
+	}
+	if !canModifyAuthored(ctx, item.Author) {
+		user, _ := currentUser(ctx)
+		log.Printf("%s: User '%s' may not modify %s item %s", logPrefix, user.Username, t.Name, id)
+		forbidden(ctx, user)
+		return t, item, false
+	}
+	return t, item, true
+}
+
+// renderItemForm renders the generated item form, with per-field errors
+// and a 400 status when errs is not empty.
+func (h *PageHandler) renderItemForm(ctx *fasthttp.RequestCtx, t schema.Type, item models.Item, isNew bool, form map[string]string, errs schema.Errors) {
+	title := "New " + t.DisplayLabel()
+	if !isNew {
+		title = "Edit " + t.DisplayLabel()
+	}
+	data := &models.ItemFormData{
+		BasePageData: h.newBasePageData(ctx, title, t.Description),
+		Type:         t,
+		Item:         item,
+		IsNew:        isNew,
+		Form:         form,
+		Errors:       errs,
+	}
+	if len(errs) > 0 {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+	}
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WriteItemFormPage(ctx, data)
+}
+
+// formLookup reads HTML form fields for schema.Type.FromForm.
+func formLookup(ctx *fasthttp.RequestCtx) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v := ctx.FormValue(name)
+		return string(v), v != nil
+	}
+}
+
+// itemForm formats stored values for the form inputs.
+func itemForm(t schema.Type, values map[string]any) map[string]string {
+	form := make(map[string]string, len(t.Fields))
+	for _, f := range t.Fields {
+		form[f.Name] = f.FormValue(values[f.Name])
+	}
+	return form
+}
+
+// submittedForm keeps what the user typed, to show it again with errors.
+func submittedForm(ctx *fasthttp.RequestCtx, t schema.Type) map[string]string {
+	form := make(map[string]string, len(t.Fields))
+	for _, f := range t.Fields {
+		v := ctx.FormValue(f.Name)
+		if f.Type == schema.TypeBoolean {
+			form[f.Name] = "false"
+			if v != nil && !bytes.Equal(v, []byte("false")) {
+				form[f.Name] = "true"
+			}
+			continue
+		}
+		form[f.Name] = string(v)
+	}
+	return form
+}
+
+// defaultValues returns the defaults of a type's fields.
+func defaultValues(t schema.Type) map[string]any {
+	values := make(map[string]any, len(t.Fields))
+	for _, f := range t.Fields {
+		if f.Default != nil {
+			values[f.Name] = f.Default
+		}
+	}
+	return values
+}
+
+// validationErrors extracts per-field errors, keeping other errors under "".
+func validationErrors(err error) schema.Errors {
+	var errs schema.Errors
+	if errors.As(err, &errs) {
+		return errs
+	}
+	return schema.Errors{"": err.Error()}
+}
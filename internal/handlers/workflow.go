@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"cms/internal/models"
+	"cms/internal/storage"
+	"cms/internal/templates/pages"
+	"cms/internal/workflow"
+
+	"github.com/valyala/fasthttp"
+)
+
+// reviewMessages maps ?message= keys to the flash text shown on /content/review.
+var reviewMessages = map[string]string{
+	"status": "Status updated.",
+}
+
+// Transition handles POST /api/content/{id}/status - moves an item to
+// another workflow state. The form carries the target `status` and an
+// optional `comment`, which some transitions (e.g. rejecting) require.
+func (h *CRUDHandler) Transition(ctx *fasthttp.RequestCtx) {
+	store, item, ok := h.loadModifiable(ctx, "CRUD Transition")
+	if !ok {
+		return
+	}
+	if item.Trashed() {
+		writeError(ctx, "Content is in the trash; restore it first", fasthttp.StatusConflict)
+		return
+	}
+
+	prev := item
+	comment := strings.TrimSpace(string(ctx.FormValue("comment")))
+	if err := h.prepareTransition(ctx, &item, strings.TrimSpace(string(ctx.FormValue("status"))), comment); err != nil {
+		respondError(ctx, "CRUD Transition", err)
+		return
+	}
+
+	if err := store.Update(item); err != nil {
+		if errors.Is(err, storage.ErrVersionConflict) {
+			writeError(ctx, "Content was changed while updating its status; try again", fasthttp.StatusConflict)
+			return
+		}
+		log.Printf("CRUD Transition: Error updating content for id %s: %v", item.ID, err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+	recordRevision(ctx, store, item, models.RevisionStatus, comment)
+	h.wakeScheduler(item)
+
+	log.Printf("CRUD Transition: Moved %s from %s to %s", item.ID, prev.Status, item.Status)
+	if string(ctx.FormValue("next")) == "review" {
+		formDone(ctx, "/content/review?message=status")
+		return
+	}
+	formDone(ctx, "/content/"+item.ID+"?message=status")
+}
+
+// prepareTransition moves item to the workflow state to, checking that
+// the current user may, and settles its schedule for the new state.
+func (h *CRUDHandler) prepareTransition(ctx *fasthttp.RequestCtx, item *models.Content, to, comment string) error {
+	if err := h.transitionError(ctx, item.Status, to, comment); err != nil {
+		return err
+	}
+
+	prev := *item
+	now := time.Now().UTC()
+	item.Status = to
+	item.UpdatedAt = now
+	// Publishing an item scheduled for later publishes it now
+	if to == models.StatusPublished && item.PublishedAt.After(now) {
+		item.PublishedAt = now
+	}
+	if err := applySchedule(item, prev, now); err != nil {
+		return invalidField(scheduleField(err), err)
+	}
+	// The schedule may have settled on another state, e.g. a past publish time
+	if item.Status != to {
+		return h.transitionError(ctx, prev.Status, item.Status, comment)
+	}
+	return nil
+}
+
+// checkTransition checks that the current user may move an item from one
+// workflow state to another, responding with 400 or 403 otherwise.
+func (h *CRUDHandler) checkTransition(ctx *fasthttp.RequestCtx, from, to, comment string) bool {
+	if err := h.transitionError(ctx, from, to, comment); err != nil {
+		respondError(ctx, "CRUD Transition", err)
+		return false
+	}
+	return true
+}
+
+// transitionError is checkTransition returning a requestError.
+func (h *CRUDHandler) transitionError(ctx *fasthttp.RequestCtx, from, to, comment string) error {
+	err := h.workflow.Check(currentRole(ctx), from, to, comment != "")
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, workflow.ErrNotAllowed):
+		return &requestError{status: fasthttp.StatusForbidden, msg: "Forbidden: " + err.Error()}
+	}
+	return &requestError{status: fasthttp.StatusBadRequest, msg: err.Error()}
+}
+
+// currentRole returns the role of the authenticated user, or "" if there is none.
+func currentRole(ctx *fasthttp.RequestCtx) string {
+	user, _ := currentUser(ctx)
+	return user.Role
+}
+
+// ReviewQueue handles GET /content/review - lists items waiting in the
+// workflow's review states, oldest first, with the actions the current
+// user may take on each.
+func (h *PageHandler) ReviewQueue(ctx *fasthttp.RequestCtx) {
+	store, err := h.contentStore(ctx)
+	if err != nil {
+		log.Printf("Page Review: Error resolving content store: %v", err)
+		writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	data := &models.ReviewData{
+		BasePageData: h.newBasePageData(ctx, "Review Queue", "Content waiting for review"),
+		Message:      reviewMessages[string(ctx.QueryArgs().Peek("message"))],
+	}
+	for _, state := range h.workflow.Review {
+		result, err := store.Query(storage.ListQuery{Status: state, Sort: storage.SortUpdatedAt})
+		if err != nil {
+			log.Printf("Page Review: Error listing %s content: %v", state, err)
+			writeError(ctx, "Internal Server Error", fasthttp.StatusInternalServerError)
+			return
+		}
+		for _, item := range result.Items {
+			data.Entries = append(data.Entries, models.ReviewEntry{Item: item, Transitions: h.transitions(ctx, item)})
+		}
+	}
+	sort.SliceStable(data.Entries, func(i, j int) bool {
+		return data.Entries[i].Item.UpdatedAt.Before(data.Entries[j].Item.UpdatedAt)
+	})
+
+	ctx.SetContentType("text/html; charset=utf-8")
+	pages.WriteReviewPage(ctx, data)
+}
+
+// transitions lists the status changes the current user may make to item
+// from its page. Scheduling needs a publish time, which is set in the edit
+// form, so it is only offered once the item has a future one.
+func (h *PageHandler) transitions(ctx *fasthttp.RequestCtx, item models.Content) []workflow.Transition {
+	if item.Trashed() || !canModify(ctx, item) {
+		return nil
+	}
+	now := time.Now()
+	var out []workflow.Transition
+	for _, t := range h.workflow.Available(currentRole(ctx), item.Status) {
+		if t.To == models.StatusScheduled && !item.PublishedAt.After(now) {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+package media
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrBlobNotFound is returned by BlobStore.Open for a missing key.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore holds file contents by key. Keys are slash-separated paths
+// chosen by the application, e.g. "originals/<id>".
+type BlobStore interface {
+	Put(key string, r io.Reader) error
+	Open(key string) (io.ReadCloser, error)
+	// Delete removes key and every key below it ("variants/<id>" removes
+	// all variants of an upload). Missing keys are not an error.
+	Delete(key string) error
+}
+
+// DiskStore is a BlobStore keeping each blob in a file below a directory.
+type DiskStore struct {
+	dir string
+}
+
+// NewDiskStore creates a disk store rooted at dir, creating it if needed.
+func NewDiskStore(dir string) (*DiskStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory %s: %w", dir, err)
+	}
+	return &DiskStore{dir: dir}, nil
+}
+
+// Put writes r to key. The file is written under a temporary name and
+// renamed, so readers never see a partial blob.
+func (s *DiskStore) Put(key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	return nil
+}
+
+// Open opens the blob at key for reading.
+func (s *DiskStore) Open(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	return f, err
+}
+
+// Delete removes the blob at key and any blobs below it.
+func (s *DiskStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(path)
+}
+
+// path maps key to a file below the store's directory, rejecting keys
+// that would escape it.
+func (s *DiskStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || clean != "/"+key || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,84 @@
+// Package media checks uploaded files, names their blobs and derives
+// resized image variants.
+package media
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+)
+
+// MaxPixels caps the size of images, so a small file that decodes to a
+// huge bitmap cannot exhaust memory when variants are made.
+const MaxPixels = 25_000_000
+
+// Errors returned for uploads that are not accepted.
+var (
+	ErrUnsupportedType = errors.New("unsupported file type")
+	ErrTooLarge        = errors.New("image dimensions too large")
+)
+
+// extensions maps the accepted MIME types to the extension used in file
+// URLs. SVG is left out on purpose: it can carry scripts.
+var extensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// Sniff detects the MIME type of a file from its first bytes (up to 512
+// are used). The file name and the Content-Type sent by the client are
+// never trusted. Returns ErrUnsupportedType for types not accepted.
+func Sniff(head []byte) (string, error) {
+	mime := http.DetectContentType(head)
+	if _, ok := extensions[mime]; !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedType, mime)
+	}
+	return mime, nil
+}
+
+// Extension returns the file extension for an accepted MIME type.
+func Extension(mime string) string {
+	return extensions[mime]
+}
+
+// IsImage reports whether mime is one of the accepted image types.
+func IsImage(mime string) bool {
+	return mime != "application/pdf" && extensions[mime] != ""
+}
+
+// Dimensions reads the width and height of an image. Both are 0 for files
+// the standard library cannot decode, such as PDF and WebP. Returns
+// ErrTooLarge for images over MaxPixels.
+func Dimensions(r io.Reader, mime string) (int, int, error) {
+	if !CanResize(mime) {
+		return 0, 0, nil
+	}
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid image: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return 0, 0, ErrTooLarge
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// OriginalKey names the blob holding an upload as it was received.
+func OriginalKey(id string) string {
+	return "originals/" + id
+}
+
+// VariantKey names the cached blob of one variant of an upload.
+func VariantKey(id, preset string) string {
+	return VariantsKey(id) + "/" + preset
+}
+
+// VariantsKey names the directory of all cached variants of an upload.
+func VariantsKey(id string) string {
+	return "variants/" + id
+}
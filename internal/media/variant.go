@@ -0,0 +1,164 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+)
+
+// Preset is a named image variant size.
+type Preset struct {
+	Name   string
+	Width  int
+	Height int
+	Crop   bool // Fill Width x Height exactly, cutting off the edges; otherwise fit within it
+}
+
+// Presets lists the variants served with ?size=. Sizes are fixed so the
+// cache holds a bounded number of files per upload.
+var Presets = []Preset{
+	{Name: "thumb", Width: 150, Height: 150, Crop: true},
+	{Name: "small", Width: 480, Height: 480},
+	{Name: "medium", Width: 960, Height: 960},
+	{Name: "large", Width: 1920, Height: 1920},
+}
+
+// LookupPreset finds a preset by name.
+func LookupPreset(name string) (Preset, bool) {
+	for _, p := range Presets {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Preset{}, false
+}
+
+// Needed reports whether an image of width x height must be resized for
+// the preset; if not, the original can be served as is.
+func (p Preset) Needed(width, height int) bool {
+	if p.Crop {
+		return width != p.Width || height != p.Height
+	}
+	return width > p.Width || height > p.Height
+}
+
+// CanResize reports whether variants can be made from files of type mime.
+func CanResize(mime string) bool {
+	return mime == "image/jpeg" || mime == "image/png" || mime == "image/gif"
+}
+
+// Resize decodes an image and encodes its variant for the preset. Images
+// are only ever scaled down. JPEG stays JPEG; other types become PNG (the
+// first frame of an animated GIF). Returns the encoded variant and its
+// MIME type.
+func Resize(r io.Reader, mime string, p Preset) ([]byte, string, error) {
+	var src image.Image
+	var err error
+	switch mime {
+	case "image/jpeg":
+		src, err = jpeg.Decode(r)
+	case "image/png":
+		src, err = png.Decode(r)
+	case "image/gif":
+		src, err = gif.Decode(r)
+	default:
+		return nil, "", fmt.Errorf("%w: %s", ErrUnsupportedType, mime)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	area := src.Bounds()
+	if p.Crop {
+		area = centerCrop(area, p.Width, p.Height)
+	}
+	width, height := fitWithin(area.Dx(), area.Dy(), p.Width, p.Height)
+	img := image.NewRGBA(image.Rect(0, 0, area.Dx(), area.Dy()))
+	draw.Draw(img, img.Bounds(), src, area.Min, draw.Src)
+	out := scaleDown(img, width, height)
+
+	var buf bytes.Buffer
+	if mime == "image/jpeg" {
+		err = jpeg.Encode(&buf, out, &jpeg.Options{Quality: 85})
+	} else {
+		mime = "image/png"
+		err = png.Encode(&buf, out)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode variant: %w", err)
+	}
+	return buf.Bytes(), mime, nil
+}
+
+// centerCrop returns the largest centred part of r with the aspect ratio
+// of width x height.
+func centerCrop(r image.Rectangle, width, height int) image.Rectangle {
+	w, h := r.Dx(), r.Dy()
+	if w*height > h*width {
+		cw := max(1, h*width/height)
+		x := r.Min.X + (w-cw)/2
+		return image.Rect(x, r.Min.Y, x+cw, r.Max.Y)
+	}
+	ch := max(1, w*height/width)
+	y := r.Min.Y + (h-ch)/2
+	return image.Rect(r.Min.X, y, r.Max.X, y+ch)
+}
+
+// fitWithin scales width x height down to fit maxWidth x maxHeight,
+// keeping the aspect ratio. Smaller sizes are returned unchanged.
+func fitWithin(width, height, maxWidth, maxHeight int) (int, int) {
+	scale := math.Min(1, math.Min(float64(maxWidth)/float64(width), float64(maxHeight)/float64(height)))
+	return max(1, int(math.Round(float64(width)*scale))), max(1, int(math.Round(float64(height)*scale)))
+}
+
+// scaleDown resizes src to width x height by averaging the source pixels
+// covered by each target pixel (a box filter), which is cheap and avoids
+// the aliasing of nearest-neighbour sampling when shrinking.
+func scaleDown(src *image.RGBA, width, height int) *image.RGBA {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	if sw == width && sh == height {
+		return src
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := span(y, height, sh)
+		for x := 0; x < width; x++ {
+			x0, x1 := span(x, width, sw)
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					px := row[sx*4 : sx*4+4]
+					r += uint32(px[0])
+					g += uint32(px[1])
+					b += uint32(px[2])
+					a += uint32(px[3])
+					n++
+				}
+			}
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// span returns the source range [lo, hi) covered by target index i when
+// scaling size source pixels to n target pixels.
+func span(i, n, size int) (int, int) {
+	lo := i * size / n
+	hi := (i + 1) * size / n
+	if hi <= lo {
+		hi = lo + 1
+	}
+	return lo, hi
+}
@@ -1,17 +1,153 @@
 package models
 
-import "time"
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"cms/internal/auth"
+	"cms/internal/slug"
+	"cms/internal/validate"
+	"cms/internal/workflow"
+)
+
+// Content body formats.
+const (
+	FormatHTML     = "html"     // Sanitized HTML
+	FormatMarkdown = "markdown" // CommonMark with tables, footnotes and heading anchors
+	FormatPlain    = "plain"    // Plain text, escaped on render
+)
+
+// Content statuses.
+const (
+	StatusDraft     = "draft"
+	StatusScheduled = "scheduled" // Goes live at PublishedAt
+	StatusPublished = "published"
+	StatusArchived  = "archived" // No longer public, e.g. after ExpiresAt
+)
+
+// ContentFormats lists the supported body formats.
+var ContentFormats = []string{FormatHTML, FormatMarkdown, FormatPlain}
+
+// ValidFormat reports whether format is a supported body format.
+// The empty format is treated as HTML.
+func ValidFormat(format string) bool {
+	if format == "" {
+		return true
+	}
+	for _, f := range ContentFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Limits on content fields, checked by Validate.
+const (
+	MaxTitleLength   = 200       // Characters
+	MaxContentLength = 1_000_000 // Characters of the body
+	MaxTerms         = 50        // Tags, and categories, per item
+	MaxTermName      = 100       // Characters of a tag or category name
+)
 
 // Content represents the main data structure for content items.
 type Content struct {
 	ID          string    `json:"id"`
 	Title       string    `json:"title"`
 	Slug        string    `json:"slug"`
-	Content     string    `json:"content"` // Consider using a more specific type if needed (e.g., HTML, Markdown)
+	Content     string    `json:"content"`
+	Format      string    `json:"format,omitempty"` // Body format, see Format* constants (empty means HTML)
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	PublishedAt time.Time `json:"published_at,omitempty"`
-	Status      string    `json:"status"` // e.g., "draft", "published", "archived"
+	ExpiresAt   time.Time `json:"expires_at,omitempty"` // When a published item is archived, zero if never
+	Status      string    `json:"status"`               // See Status* constants
+	Author      string    `json:"author,omitempty"`     // Username of the creator
+	DeletedAt   time.Time `json:"deleted_at,omitempty"` // When the item was moved to the trash, zero if not trashed
+	Tags        []string  `json:"tags,omitempty"`       // Tag slugs, see TaxonomyTags
+	Categories  []string  `json:"categories,omitempty"` // Category slugs, see TaxonomyCategories
+	Version     int       `json:"version"`              // Incremented by every update, see ETag
+}
+
+// Validate checks the fields a client sets and returns every violation as
+// validate.Errors keyed by JSON field name. states lists the workflow
+// states; an empty status or format means the default. published_at is
+// only checked against CreatedAt if that is set, so callers leave it zero
+// where the order does not apply, e.g. for backdated new posts.
+func (c Content) Validate(states []string) error {
+	return validate.Check(
+		validate.Field{Name: "title", Value: c.Title, Rules: []validate.Rule{validate.Required, validate.MaxLength(MaxTitleLength)}},
+		validate.Field{Name: "slug", Value: c.Slug, Rules: []validate.Rule{validate.MaxLength(slug.MaxLength), validate.Pattern(slug.Pattern, "lowercase letters, numbers and single hyphens")}},
+		validate.Field{Name: "content", Value: c.Content, Rules: []validate.Rule{validate.MaxLength(MaxContentLength)}},
+		validate.Field{Name: "format", Value: c.Format, Rules: []validate.Rule{validate.OneOf(ContentFormats...)}},
+		validate.Field{Name: "status", Value: c.Status, Rules: []validate.Rule{validate.OneOf(states...)}},
+		validate.Field{Name: "published_at", Value: c.PublishedAt, Rules: []validate.Rule{validate.NotBefore("created_at", c.CreatedAt)}},
+		validate.Field{Name: "expires_at", Value: c.ExpiresAt, Rules: []validate.Rule{validate.After("published_at", c.PublishedAt)}},
+		validate.Field{Name: "tags", Value: c.Tags, Rules: []validate.Rule{validate.MaxItems(MaxTerms), validate.Each(validate.MaxLength(MaxTermName), sluggable)}},
+		validate.Field{Name: "categories", Value: c.Categories, Rules: []validate.Rule{validate.MaxItems(MaxTerms), validate.Each(validate.MaxLength(MaxTermName))}},
+	)
+}
+
+// sluggable rejects term names that yield no slug, such as "!!!". Blank
+// names pass; they are skipped when the terms are resolved.
+func sluggable(v any) string {
+	if name, ok := v.(string); ok && strings.TrimSpace(name) != "" && slug.Make(name) == "" {
+		return "must contain a letter or digit"
+	}
+	return ""
+}
+
+// Trashed reports whether the item is in the trash.
+func (c Content) Trashed() bool {
+	return !c.DeletedAt.IsZero()
+}
+
+// ETag returns the strong entity tag of the item's current version, as
+// sent in ETag headers and compared with If-Match and If-None-Match.
+func (c Content) ETag() string {
+	return `"` + strconv.Itoa(c.Version) + `"`
+}
+
+// Terms returns the slugs of the item's terms in a taxonomy.
+func (c Content) Terms(taxonomy string) []string {
+	switch taxonomy {
+	case TaxonomyTags:
+		return c.Tags
+	case TaxonomyCategories:
+		return c.Categories
+	}
+	return nil
+}
+
+// SetTerms replaces the slugs of the item's terms in a taxonomy.
+func (c *Content) SetTerms(taxonomy string, slugs []string) {
+	switch taxonomy {
+	case TaxonomyTags:
+		c.Tags = slugs
+	case TaxonomyCategories:
+		c.Categories = slugs
+	}
+}
+
+// PublishTime returns when the item was (or will be) published. Items
+// published before publish times were recorded fall back to CreatedAt.
+func (c Content) PublishTime() time.Time {
+	if c.PublishedAt.IsZero() {
+		return c.CreatedAt
+	}
+	return c.PublishedAt
+}
+
+// Public reports whether readers may see the item at time t: it is
+// published or scheduled, outside the trash, its publish time has come and
+// it has not expired. Scheduled items go live on time even if the
+// scheduler has not switched them to published yet.
+func (c Content) Public(t time.Time) bool {
+	if c.Status != StatusPublished && c.Status != StatusScheduled {
+		return false
+	}
+	return !c.Trashed() && !c.PublishTime().After(t) && (c.ExpiresAt.IsZero() || c.ExpiresAt.After(t))
 }
 
 // --- Template Data Structures ---
@@ -22,6 +158,10 @@ type PageData interface {
 	Title() string
 	Description() string
 	IsAuthenticated() bool // Added method to check authentication status
+	IsSandbox() bool       // True when content is stored per session (demo mode)
+	CurrentUsername() string
+	CanManageUsers() bool
+	CSRFToken() string // Token for the hidden csrf_token field of POST forms
 	// Add other common fields if needed, e.g., CanonicalURL(), Username()
 }
 
@@ -30,6 +170,10 @@ type BasePageData struct {
 	PageTitle       string
 	PageDescription string
 	AuthStatus      bool // Field to store authentication status
+	SandboxMode     bool // Field to store whether sandbox storage is active
+	Username        string
+	UserRole        string
+	CSRF            string // CSRF token issued for this request
 }
 
 func (d *BasePageData) Title() string {
@@ -44,29 +188,127 @@ func (d *BasePageData) IsAuthenticated() bool {
 	return d.AuthStatus
 }
 
+func (d *BasePageData) IsSandbox() bool {
+	return d.SandboxMode
+}
+
+func (d *BasePageData) CurrentUsername() string {
+	return d.Username
+}
+
+func (d *BasePageData) CanManageUsers() bool {
+	return auth.Can(d.UserRole, auth.PermUsersManage)
+}
+
+// CanManageTerms reports whether the current user may manage tags and categories.
+func (d *BasePageData) CanManageTerms() bool {
+	return auth.Can(d.UserRole, auth.PermTermsManage)
+}
+
+func (d *BasePageData) CSRFToken() string {
+	return d.CSRF
+}
+
 // IndexData holds data specifically for the index page template.
 type IndexData struct {
-	BasePageData // Embed common page data
-	// Add index-specific fields here if necessary
+	BasePageData        // Embed common page data
+	PublicPrefix string // URL prefix of the public site, linked from the home page
 }
 
 // ListData holds data for the content list page template.
 type ListData struct {
-	BasePageData           // Embed common page data
-	Items        []Content // The list of content items to display
+	BasePageData                 // Embed common page data
+	Items        []Content       // The list of content items to display
+	Message      string          // Flash message, e.g. after moving an item to the trash
+	Filter       ListFilter      // Current filter values, echoed into the filter form
+	NextURL      string          // Link to the next page, empty on the last page
+	FirstURL     string          // Link back to the first page, set on later pages
+	ErrorMessage string          // Why the filter was rejected, if it was
+	Statuses     []string        // Workflow states offered by the status filter
+	Categories   []TermEntry     // Categories offered by the category filter
+	Selectable   map[string]bool // IDs the current user may change, offered for bulk actions
+}
+
+// ListFilter holds the raw filter and sort parameters of a content listing.
+type ListFilter struct {
+	Status        string
+	Search        string
+	CreatedAfter  string
+	CreatedBefore string
+	Sort          string
+	Tag           string
+	Category      string
+}
+
+// TrashEntry is a trashed item with what the current user may do with it.
+type TrashEntry struct {
+	Item      Content
+	CanModify bool      // Whether the user may restore or purge the item
+	PurgeAt   time.Time // When the purger removes the item, zero if never
+}
+
+// TrashData holds data for the trash page template.
+type TrashData struct {
+	BasePageData
+	Entries []TrashEntry
+	Message string // Flash message, e.g. after a restore
 }
 
 // ViewData holds data for the content view page template.
 type ViewData struct {
-	BasePageData         // Embed common page data
-	Item         Content // The content item being viewed
+	BasePageData                       // Embed common page data
+	Item         Content               // The content item being viewed
+	Body         string                // Item body rendered to sanitized HTML
+	Revisions    []Revision            // Saved versions, newest first
+	Diff         *RevisionDiff         // Comparison requested via ?from=&to=, if any
+	CanModify    bool                  // Whether the current user may edit, trash or restore the item
+	Message      string                // Flash message, e.g. after a restore
+	Transitions  []workflow.Transition // Status changes the current user may make
+	Tags         []Term                // The item's tags
+	Categories   []Term                // The item's categories
+}
+
+// PublicIndexData holds data for the public index of published posts.
+type PublicIndexData struct {
+	BasePageData
+	Prefix   string    // URL prefix of the public site, e.g. "/blog"
+	Items    []Content // One page of posts, most recently published first
+	NextURL  string    // Link to older posts, empty on the last page
+	FirstURL string    // Link back to the newest posts, set on later pages
+}
+
+// PublicPostData holds data for a public post page.
+type PublicPostData struct {
+	BasePageData
+	Prefix     string  // URL prefix of the public site
+	Item       Content // The published item
+	Body       string  // Item body rendered to sanitized HTML
+	Tags       []Term  // The item's tags, linked to their archives
+	Categories []Term  // The item's categories, linked to their archives
 }
 
 // EditData holds data for the content edit page template.
 type EditData struct {
-	BasePageData         // Embed common page data
-	Item         Content // The content item being edited
-	IsNew        bool    // Flag to indicate if this is for creating a new item
+	BasePageData             // Embed common page data
+	Item         Content     // The content item being edited
+	IsNew        bool        // Flag to indicate if this is for creating a new item
+	Statuses     []string    // Statuses the current user may save the item with
+	Feedback     *Revision   // Latest status change with a comment, e.g. a rejection, if not edited since
+	TagNames     []string    // Names of the item's tags, shown in the tag input
+	Categories   []TermEntry // Categories the item may be filed under
+}
+
+// ReviewEntry is an item awaiting review with what the current user may do with it.
+type ReviewEntry struct {
+	Item        Content
+	Transitions []workflow.Transition
+}
+
+// ReviewData holds data for the review queue page template.
+type ReviewData struct {
+	BasePageData
+	Entries []ReviewEntry // Oldest submission first
+	Message string        // Flash message, e.g. after an approval
 }
 
 // NewData holds data for the new content page template.
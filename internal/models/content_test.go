@@ -0,0 +1,74 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cms/internal/validate"
+)
+
+func TestContentValidate(t *testing.T) {
+	states := []string{StatusDraft, StatusPublished}
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	valid := Content{
+		Title:       "Hello",
+		Slug:        "hello",
+		Content:     "Body",
+		Format:      "markdown",
+		Status:      StatusPublished,
+		CreatedAt:   t0,
+		PublishedAt: t0.Add(time.Hour),
+		ExpiresAt:   t0.Add(2 * time.Hour),
+		Tags:        []string{"Go Lang"},
+		Categories:  []string{"news"},
+	}
+	tests := []struct {
+		name   string
+		modify func(c *Content)
+		want   validate.Errors // nil if the item is valid
+	}{
+		{"valid", func(c *Content) {}, nil},
+		{"defaults", func(c *Content) { c.Slug, c.Format, c.Status = "", "", "" }, nil},
+		{"missing title", func(c *Content) { c.Title = " " }, validate.Errors{"title": "is required"}},
+		{"long title", func(c *Content) { c.Title = strings.Repeat("x", MaxTitleLength+1) }, validate.Errors{"title": "must be at most 200 characters"}},
+		{"bad slug", func(c *Content) { c.Slug = "Hello World" }, validate.Errors{"slug": "must be lowercase letters, numbers and single hyphens"}},
+		{"unknown format", func(c *Content) { c.Format = "rtf" }, validate.Errors{"format": "must be one of " + strings.Join(ContentFormats, ", ")}},
+		{"unknown status", func(c *Content) { c.Status = "gone" }, validate.Errors{"status": "must be one of draft, published"}},
+		{"published before created", func(c *Content) { c.PublishedAt = t0.Add(-time.Hour) }, validate.Errors{"published_at": "must not be before created_at"}},
+		{"backdated without created_at", func(c *Content) { c.CreatedAt, c.PublishedAt = time.Time{}, t0.Add(-time.Hour) }, nil},
+		{"expires before published", func(c *Content) { c.ExpiresAt = c.PublishedAt }, validate.Errors{"expires_at": "must be after published_at"}},
+		{"too many tags", func(c *Content) { c.Tags = make([]string, MaxTerms+1) }, validate.Errors{"tags": "must have at most 50 entries"}},
+		{"long tag", func(c *Content) { c.Tags = []string{strings.Repeat("t", MaxTermName+1)} }, validate.Errors{"tags": "'" + strings.Repeat("t", MaxTermName+1) + "' must be at most 100 characters"}},
+		{"tag without a slug", func(c *Content) { c.Tags = []string{"ok", "!!!"} }, validate.Errors{"tags": "'!!!' must contain a letter or digit"}},
+		{"blank tag", func(c *Content) { c.Tags = []string{" "} }, nil},
+		{"long category", func(c *Content) { c.Categories = []string{strings.Repeat("c", MaxTermName+1)} }, validate.Errors{"categories": "'" + strings.Repeat("c", MaxTermName+1) + "' must be at most 100 characters"}},
+		{
+			"every violation at once",
+			func(c *Content) { c.Title, c.Format, c.ExpiresAt = "", "rtf", t0 },
+			validate.Errors{"title": "is required", "format": "must be one of " + strings.Join(ContentFormats, ", "), "expires_at": "must be after published_at"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid
+			tt.modify(&c)
+			err := c.Validate(states)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate = %v, want nil", err)
+				}
+				return
+			}
+			var errs validate.Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Validate = %v, want validate.Errors", err)
+			}
+			if !reflect.DeepEqual(errs, tt.want) {
+				t.Errorf("Validate = %v, want %v", errs, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"cms/internal/schema"
+)
+
+// Item is an entry of a custom content type. Its values are checked
+// against the type's fields (see schema.Type.Validate).
+type Item struct {
+	ID        string         `json:"id"`
+	Type      string         `json:"type"`             // Name of the content type
+	Values    map[string]any `json:"values"`           // Field name -> value
+	Author    string         `json:"author,omitempty"` // Username of the creator
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// TypesData holds data for the content types page template.
+type TypesData struct {
+	BasePageData
+	Types     []schema.Type
+	Counts    map[string]int // Items per type name
+	CanManage bool           // Whether the current user may define types
+	Message   string         // Flash message, e.g. after a delete
+}
+
+// ItemsData holds data for the item list page of a content type.
+type ItemsData struct {
+	BasePageData
+	Type      schema.Type
+	Items     []Item
+	Columns   []schema.Field  // Fields shown in the table
+	CanCreate bool            // Whether the current user may add items
+	Editable  map[string]bool // IDs of the items the current user may modify
+	Message   string          // Flash message, e.g. after a save
+}
+
+// ItemFormData holds data for the generated item create/edit form.
+type ItemFormData struct {
+	BasePageData
+	Type   schema.Type
+	Item   Item
+	IsNew  bool
+	Form   map[string]string // Field name -> input value, as submitted or stored
+	Errors schema.Errors     // Field name -> validation error
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Media is an uploaded file. Its contents live in a blob store; this is
+// the metadata kept in the database.
+type Media struct {
+	ID        string    `json:"id"`
+	Filename  string    `json:"filename"`         // Name the file was uploaded with
+	MIME      string    `json:"mime"`             // Sniffed from the contents
+	Size      int64     `json:"size"`             // In bytes
+	Width     int       `json:"width,omitempty"`  // Pixels, for images the server can decode
+	Height    int       `json:"height,omitempty"` // Pixels, for images the server can decode
+	Alt       string    `json:"alt,omitempty"`    // Alternative text for images
+	Uploader  string    `json:"uploader"`         // Username of the uploader
+	URL       string    `json:"url"`              // Public URL of the file
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MediaData holds data for the media library page.
+type MediaData struct {
+	BasePageData
+	Items     []Media         // Newest first
+	Deletable map[string]bool // IDs the current user may delete
+	Presets   []string        // Variant names for ?size=
+	MaxSize   int64           // Upload limit in bytes
+	Message   string          // Flash message, e.g. after a delete
+}
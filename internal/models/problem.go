@@ -0,0 +1,35 @@
+package models
+
+// Problem type URIs for errors clients may want to tell apart. Other
+// errors use "about:blank", meaning the status code says it all.
+const (
+	ProblemBlank        = "about:blank"
+	ProblemInvalidJSON  = "urn:cms:problem:invalid-json"  // The body is not the JSON the endpoint expects
+	ProblemValidation   = "urn:cms:problem:validation"    // Fields failed validation; see Errors
+	ProblemEditConflict = "urn:cms:problem:edit-conflict" // The item changed since the client loaded it
+)
+
+// Problem is an error response in the RFC 7807 problem details format. The
+// API sends it as application/problem+json; pages render it with the error
+// template.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`   // The request path
+	RequestID string       `json:"request_id,omitempty"` // Also in the X-Request-ID header and the server log
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is a validation failure of one field of the request.
+type FieldError struct {
+	Field  string `json:"field"`
+	Detail string `json:"detail"`
+}
+
+// ErrorData contains data for the error page template.
+type ErrorData struct {
+	BasePageData
+	Problem Problem
+}
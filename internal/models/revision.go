@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"cms/internal/diff"
+)
+
+// Revision actions.
+const (
+	RevisionCreate   = "create"
+	RevisionUpdate   = "update"
+	RevisionRestore  = "restore"
+	RevisionSchedule = "schedule" // Status changed by the publishing scheduler
+	RevisionStatus   = "status"   // Status changed through a workflow transition
+	RevisionTrash    = "trash"    // Moved to the trash
+	RevisionUntrash  = "untrash"  // Taken out of the trash
+)
+
+// Revision is a saved version of a content item.
+type Revision struct {
+	ContentID string    `json:"content_id"`
+	Number    int       `json:"number"` // Increases by one per save, starting at 1
+	Action    string    `json:"action"` // See Revision* constants
+	Editor    string    `json:"editor"` // Username of whoever saved this version
+	CreatedAt time.Time `json:"created_at"`
+	Comment   string    `json:"comment,omitempty"` // Note left with a status change, e.g. why it was rejected
+	Snapshot  Content   `json:"snapshot"`          // Full item as saved
+}
+
+// FieldChange is a metadata field that differs between two revisions.
+type FieldChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// RevisionDiff compares two revisions of an item.
+type RevisionDiff struct {
+	From   Revision
+	To     Revision
+	Fields []FieldChange // Changed metadata, body excluded
+	Body   []diff.Line   // Line diff of the body
+}
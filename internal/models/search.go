@@ -0,0 +1,17 @@
+package models
+
+// SearchResult is one full-text search hit.
+type SearchResult struct {
+	ID      string  `json:"id"`
+	Title   string  `json:"title"`
+	Slug    string  `json:"slug"`
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"` // HTML-escaped excerpt with matches wrapped in <mark>
+}
+
+// SearchData holds data for the search results page template.
+type SearchData struct {
+	BasePageData
+	Query   string
+	Results []SearchResult
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Taxonomies content items can be grouped by.
+const (
+	TaxonomyTags       = "tags"       // Flat, created on the fly when items are tagged
+	TaxonomyCategories = "categories" // Hierarchical, managed by editors
+)
+
+// Taxonomies lists the supported taxonomies.
+var Taxonomies = []string{TaxonomyTags, TaxonomyCategories}
+
+// ValidTaxonomy reports whether taxonomy is a supported taxonomy.
+func ValidTaxonomy(taxonomy string) bool {
+	return taxonomy == TaxonomyTags || taxonomy == TaxonomyCategories
+}
+
+// Term is a tag or category.
+type Term struct {
+	Slug        string    `json:"slug"` // URL key, referenced by Content.Tags and Content.Categories
+	Name        string    `json:"name"`
+	Parent      string    `json:"parent,omitempty"` // Slug of the parent category; tags have none
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TermEntry is a term as listed on the taxonomy pages.
+type TermEntry struct {
+	Term  Term
+	Depth int // Nesting level of a category, 0 for top-level categories and tags
+	Count int // Number of items with the term
+}
+
+// TaxonomiesData holds data for the tags and categories admin page.
+type TaxonomiesData struct {
+	BasePageData
+	Tags         []TermEntry // Ordered by name
+	Categories   []TermEntry // Parents before their children
+	Message      string      // Flash message, e.g. after a delete
+	ErrorMessage string      // Why an add was rejected, if it was
+	Form         Term        // Values of a rejected add, echoed back
+	FormTaxonomy string      // Taxonomy of the rejected add
+}
+
+// TermFormData holds data for the term edit page.
+type TermFormData struct {
+	BasePageData
+	Taxonomy     string
+	Term         Term
+	Parents      []TermEntry // Categories the term may be moved under
+	ErrorMessage string
+}
+
+// TermArchiveData holds data for a public tag or category archive.
+type TermArchiveData struct {
+	BasePageData
+	Prefix   string // URL prefix of the public site
+	Taxonomy string
+	Term     Term
+	Children []Term    // Subcategories, whose posts are included
+	Items    []Content // One page of posts, most recently published first
+	NextURL  string
+	FirstURL string
+}
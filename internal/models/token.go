@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"cms/internal/auth"
+)
+
+// APIToken is a personal access token for the JSON API.
+// The token itself is never stored, only its hash.
+type APIToken struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Username   string    `json:"username"` // Owner; requests act as this user
+	Scopes     []string  `json:"scopes"`
+	Hash       string    `json:"hash"`
+	Prefix     string    `json:"prefix"` // First characters of the token, for display
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// Allows reports whether the token's scopes include perm.
+func (t APIToken) Allows(perm auth.Permission) bool {
+	for _, s := range t.Scopes {
+		if s == string(perm) {
+			return true
+		}
+	}
+	return false
+}
+
+// SettingsData holds data for the settings page template.
+type SettingsData struct {
+	BasePageData            // Embed common page data
+	Tokens       []APIToken // The current user's API tokens
+	Scopes       []string   // Scopes offered when creating a token
+	NewToken     string     // Newly created token, shown once
+	Message      string     // Flash message (e.g. "Token revoked")
+	ErrorMessage string     // Validation error to display
+}
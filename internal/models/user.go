@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// User represents a CMS account.
+type User struct {
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"password_hash"`
+	Role         string    `json:"role"` // e.g., "admin", "editor", "author", "viewer"
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// UsersData holds data for the admin user list page template.
+type UsersData struct {
+	BasePageData        // Embed common page data
+	Users        []User // All user accounts
+	Message      string // Flash message (e.g. "User created")
+}
+
+// UserFormData holds data for the admin user create/edit page template.
+type UserFormData struct {
+	BasePageData          // Embed common page data
+	User         User     // The user being edited (empty for new users)
+	IsNew        bool     // Flag to indicate if this is for creating a new user
+	Roles        []string // Roles offered in the select
+	ErrorMessage string   // Validation error to display
+}
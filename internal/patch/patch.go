@@ -0,0 +1,384 @@
+// Package patch applies JSON Merge Patch (RFC 7396) and JSON Patch
+// (RFC 6902) documents to JSON values.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Media types of the two patch formats, as sent in Content-Type.
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+// Errors returned by Merge and Apply. ErrInvalid means the patch itself is
+// malformed; ErrNotApplicable means it is well-formed but names a location
+// the document does not have; ErrTestFailed means a "test" operation did
+// not match.
+var (
+	ErrInvalid       = errors.New("invalid patch")
+	ErrNotApplicable = errors.New("patch does not apply")
+	ErrTestFailed    = errors.New("test operation failed")
+)
+
+// Merge applies a JSON Merge Patch to doc: members of a patch object
+// replace the target's, null members remove them, and any other patch
+// value replaces the target as a whole.
+func Merge(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	p, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return json.Marshal(merge(target, p))
+}
+
+func merge(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = merge(t[k], v)
+	}
+	return t
+}
+
+// Apply applies a JSON Patch, a list of add, remove, replace, move, copy
+// and test operations, to doc. The operations are applied in order and
+// the patch fails as a whole if any of them does.
+func Apply(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	var ops []map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: a JSON Patch must be an array of operation objects", ErrInvalid)
+	}
+	for i, op := range ops {
+		if target, err = applyOp(target, op); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	return json.Marshal(target)
+}
+
+// applyOp applies one JSON Patch operation and returns the new document.
+func applyOp(doc any, op map[string]json.RawMessage) (any, error) {
+	name, err := stringMember(op, "op")
+	if err != nil {
+		return nil, err
+	}
+	pathStr, err := stringMember(op, "path")
+	if err != nil {
+		return nil, err
+	}
+	path, err := parsePointer(pathStr)
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "add", "replace", "test":
+		raw, ok := op["value"]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s requires a value", ErrInvalid, name)
+		}
+		value, err := decode(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		switch name {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			return replace(doc, path, value)
+		}
+		current, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(current, value) {
+			return nil, fmt.Errorf("%w: %s", ErrTestFailed, pathStr)
+		}
+		return doc, nil
+	case "remove":
+		return remove(doc, path)
+	case "move", "copy":
+		fromStr, err := stringMember(op, "from")
+		if err != nil {
+			return nil, err
+		}
+		from, err := parsePointer(fromStr)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if name == "copy" {
+			return add(doc, path, clone(value))
+		}
+		if fromStr == pathStr {
+			return doc, nil
+		}
+		if strings.HasPrefix(pathStr, fromStr+"/") {
+			return nil, fmt.Errorf("%w: cannot move %s into itself", ErrInvalid, fromStr)
+		}
+		if doc, err = remove(doc, from); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	}
+	return nil, fmt.Errorf("%w: unknown op %q", ErrInvalid, name)
+}
+
+// add sets the member at path or inserts into an array at path, where "-"
+// appends. The parent must exist.
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(container any, key string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			c[key] = value
+			return c, nil
+		case []any:
+			i := len(c)
+			if key != "-" {
+				var err error
+				if i, err = index(key, len(c)+1); err != nil {
+					return nil, err
+				}
+			}
+			return append(c[:i], append([]any{value}, c[i:]...)...), nil
+		}
+		return nil, fmt.Errorf("%w: cannot add to a scalar at %s", ErrNotApplicable, key)
+	})
+}
+
+// remove deletes the member or array element at path, which must exist.
+func remove(doc any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: cannot remove the whole document", ErrInvalid)
+	}
+	return update(doc, path, func(container any, key string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			if _, ok := c[key]; !ok {
+				return nil, fmt.Errorf("%w: no member %q", ErrNotApplicable, key)
+			}
+			delete(c, key)
+			return c, nil
+		case []any:
+			i, err := index(key, len(c))
+			if err != nil {
+				return nil, err
+			}
+			return append(c[:i], c[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("%w: cannot remove from a scalar at %s", ErrNotApplicable, key)
+	})
+}
+
+// replace sets the value at path, which must exist.
+func replace(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	if _, err := get(doc, path); err != nil {
+		return nil, err
+	}
+	return update(doc, path, func(container any, key string) (any, error) {
+		if c, ok := container.([]any); ok {
+			i, _ := index(key, len(c))
+			c[i] = value
+			return c, nil
+		}
+		container.(map[string]any)[key] = value
+		return container, nil
+	})
+}
+
+// update walks to the container of the last token of path and replaces
+// it with the result of fn, returning the new document. Arrays are values
+// that change length, so every container on the way is written back.
+func update(doc any, path []string, fn func(container any, key string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	child, err := get(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	child, err = update(child, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	switch c := doc.(type) {
+	case map[string]any:
+		c[path[0]] = child
+	case []any:
+		i, _ := index(path[0], len(c))
+		c[i] = child
+	}
+	return doc, nil
+}
+
+// get returns the value at path.
+func get(doc any, path []string) (any, error) {
+	for _, key := range path {
+		switch c := doc.(type) {
+		case map[string]any:
+			v, ok := c[key]
+			if !ok {
+				return nil, fmt.Errorf("%w: no member %q", ErrNotApplicable, key)
+			}
+			doc = v
+		case []any:
+			i, err := index(key, len(c))
+			if err != nil {
+				return nil, err
+			}
+			doc = c[i]
+		default:
+			return nil, fmt.Errorf("%w: cannot descend into a scalar at %q", ErrNotApplicable, key)
+		}
+	}
+	return doc, nil
+}
+
+// index parses an array index token, which must be below n.
+func index(key string, n int) (int, error) {
+	if key == "" || (len(key) > 1 && key[0] == '0') || strings.Trim(key, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: bad array index %q", ErrInvalid, key)
+	}
+	i, err := strconv.Atoi(key)
+	if err != nil || i >= n {
+		return 0, fmt.Errorf("%w: array index %s out of range", ErrNotApplicable, key)
+	}
+	return i, nil
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into unescaped tokens.
+// The empty pointer refers to the whole document.
+func parsePointer(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if s[0] != '/' {
+		return nil, fmt.Errorf("%w: path %q must start with /", ErrInvalid, s)
+	}
+	tokens := strings.Split(s[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// stringMember reads a required string member of an operation.
+func stringMember(op map[string]json.RawMessage, name string) (string, error) {
+	raw, ok := op[name]
+	if !ok {
+		return "", fmt.Errorf("%w: missing %q", ErrInvalid, name)
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return "", fmt.Errorf("%w: %q must be a string", ErrInvalid, name)
+	}
+	return s, nil
+}
+
+// decode parses a single JSON value, keeping numbers as written.
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+	return v, nil
+}
+
+// equal compares decoded JSON values; numbers compare by value.
+func equal(a, b any) bool {
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if x == y {
+			return true
+		}
+		fx, err1 := x.Float64()
+		fy, err2 := y.Float64()
+		return err1 == nil && err2 == nil && fx == fy
+	}
+	return a == b
+}
+
+// clone deep-copies a decoded JSON value.
+func clone(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(x))
+		for k, w := range x {
+			m[k] = clone(w)
+		}
+		return m
+	case []any:
+		s := make([]any, len(x))
+		for i, w := range x {
+			s[i] = clone(w)
+		}
+		return s
+	}
+	return v
+}
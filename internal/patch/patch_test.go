@@ -0,0 +1,391 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// assertJSON fails t unless got and want encode the same JSON value.
+func assertJSON(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("result %s is not JSON: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("bad expectation %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+// The examples of RFC 7396, Appendix A.
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		// The example of section 3
+		{
+			`{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`,
+			`{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`,
+			`{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.doc+" "+tt.patch, func(t *testing.T) {
+			got, err := Merge([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Merge: %v", err)
+			}
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+func TestMergeInvalid(t *testing.T) {
+	for _, patch := range []string{``, `{"a":`, `{} {}`} {
+		if _, err := Merge([]byte(`{}`), []byte(patch)); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Merge(%q) = %v, want ErrInvalid", patch, err)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name       string
+		doc, patch string
+		want       string // Ignored if err is set
+		err        error
+	}{
+		// RFC 6902, Appendix A
+		{
+			name:  "A.1 add an object member",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz","value":"qux"}]`,
+			want:  `{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			name:  "A.2 add an array element",
+			doc:   `{"foo":["bar","baz"]}`,
+			patch: `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			want:  `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			name:  "A.3 remove an object member",
+			doc:   `{"baz":"qux","foo":"bar"}`,
+			patch: `[{"op":"remove","path":"/baz"}]`,
+			want:  `{"foo":"bar"}`,
+		},
+		{
+			name:  "A.4 remove an array element",
+			doc:   `{"foo":["bar","qux","baz"]}`,
+			patch: `[{"op":"remove","path":"/foo/1"}]`,
+			want:  `{"foo":["bar","baz"]}`,
+		},
+		{
+			name:  "A.5 replace a value",
+			doc:   `{"baz":"qux","foo":"bar"}`,
+			patch: `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			want:  `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			name:  "A.6 move a value",
+			doc:   `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			want:  `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			name:  "A.7 move an array element",
+			doc:   `{"foo":["all","grass","cows","eat"]}`,
+			patch: `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			want:  `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			name:  "A.8 test a value: success",
+			doc:   `{"baz":"qux","foo":["a",2,"c"]}`,
+			patch: `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			want:  `{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			name:  "A.9 test a value: error",
+			doc:   `{"baz":"qux"}`,
+			patch: `[{"op":"test","path":"/baz","value":"bar"}]`,
+			err:   ErrTestFailed,
+		},
+		{
+			name:  "A.10 add a nested member object",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`,
+			want:  `{"foo":"bar","child":{"grandchild":{}}}`,
+		},
+		{
+			name:  "A.11 ignore unrecognized elements",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz","value":"qux","xyz":123}]`,
+			want:  `{"foo":"bar","baz":"qux"}`,
+		},
+		{
+			name:  "A.12 add to a nonexistent target",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			err:   ErrNotApplicable,
+		},
+		{
+			name:  "A.14 ~ escape ordering",
+			doc:   `{"/":9,"~1":10}`,
+			patch: `[{"op":"test","path":"/~01","value":10}]`,
+			want:  `{"/":9,"~1":10}`,
+		},
+		{
+			name:  "A.15 comparing strings and numbers",
+			doc:   `{"/":9,"~1":10}`,
+			patch: `[{"op":"test","path":"/~01","value":"10"}]`,
+			err:   ErrTestFailed,
+		},
+		{
+			name:  "A.16 add an array value",
+			doc:   `{"foo":["bar"]}`,
+			patch: `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			want:  `{"foo":["bar",["abc","def"]]}`,
+		},
+
+		// Pointer escaping
+		{
+			name:  "~1 addresses a member with a slash",
+			doc:   `{"a/b":1}`,
+			patch: `[{"op":"replace","path":"/a~1b","value":2}]`,
+			want:  `{"a/b":2}`,
+		},
+		{
+			name:  "~0 addresses a member with a tilde",
+			doc:   `{"m~n":1}`,
+			patch: `[{"op":"remove","path":"/m~0n"}]`,
+			want:  `{}`,
+		},
+		{
+			name:  "empty member name",
+			doc:   `{"":0}`,
+			patch: `[{"op":"replace","path":"/","value":1}]`,
+			want:  `{"":1}`,
+		},
+		{
+			name:  "path without leading slash",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"remove","path":"a"}]`,
+			err:   ErrInvalid,
+		},
+
+		// Array indexes
+		{
+			name:  "add at the end by index",
+			doc:   `[1,2]`,
+			patch: `[{"op":"add","path":"/2","value":3}]`,
+			want:  `[1,2,3]`,
+		},
+		{
+			name:  "add past the end",
+			doc:   `[1,2]`,
+			patch: `[{"op":"add","path":"/3","value":3}]`,
+			err:   ErrNotApplicable,
+		},
+		{
+			name:  "- appends to a nested array",
+			doc:   `{"a":{"b":[]}}`,
+			patch: `[{"op":"add","path":"/a/b/-","value":1},{"op":"add","path":"/a/b/-","value":2}]`,
+			want:  `{"a":{"b":[1,2]}}`,
+		},
+		{
+			name:  "remove - is out of range",
+			doc:   `[1]`,
+			patch: `[{"op":"remove","path":"/-"}]`,
+			err:   ErrInvalid,
+		},
+		{
+			name:  "replace - is out of range",
+			doc:   `[1]`,
+			patch: `[{"op":"replace","path":"/-","value":2}]`,
+			err:   ErrInvalid,
+		},
+		{
+			name:  "leading zero index",
+			doc:   `[1,2]`,
+			patch: `[{"op":"remove","path":"/01"}]`,
+			err:   ErrInvalid,
+		},
+		{
+			name:  "remove past the end",
+			doc:   `[1,2]`,
+			patch: `[{"op":"remove","path":"/2"}]`,
+			err:   ErrNotApplicable,
+		},
+
+		// test
+		{
+			name:  "test compares numbers by value",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"test","path":"/a","value":1.0}]`,
+			want:  `{"a":1}`,
+		},
+		{
+			name:  "test compares objects regardless of member order",
+			doc:   `{"a":{"x":1,"y":[true,null]}}`,
+			patch: `[{"op":"test","path":"/a","value":{"y":[true,null],"x":1}}]`,
+			want:  `{"a":{"x":1,"y":[true,null]}}`,
+		},
+		{
+			name:  "test array order matters",
+			doc:   `{"a":[1,2]}`,
+			patch: `[{"op":"test","path":"/a","value":[2,1]}]`,
+			err:   ErrTestFailed,
+		},
+		{
+			name:  "test a missing member",
+			doc:   `{}`,
+			patch: `[{"op":"test","path":"/a","value":null}]`,
+			err:   ErrNotApplicable,
+		},
+		{
+			name:  "failed test discards earlier operations",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"replace","path":"/a","value":2},{"op":"test","path":"/a","value":1}]`,
+			err:   ErrTestFailed,
+		},
+
+		// move and copy
+		{
+			name:  "move into a child of itself",
+			doc:   `{"a":{"b":{}}}`,
+			patch: `[{"op":"move","from":"/a","path":"/a/b/c"}]`,
+			err:   ErrInvalid,
+		},
+		{
+			name:  "move to a sibling sharing a prefix",
+			doc:   `{"a":1,"ab":{}}`,
+			patch: `[{"op":"move","from":"/a","path":"/ab/x"}]`,
+			want:  `{"ab":{"x":1}}`,
+		},
+		{
+			name:  "move onto itself",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"move","from":"/a","path":"/a"}]`,
+			want:  `{"a":1}`,
+		},
+		{
+			name:  "move a missing value",
+			doc:   `{}`,
+			patch: `[{"op":"move","from":"/a","path":"/b"}]`,
+			err:   ErrNotApplicable,
+		},
+		{
+			name:  "copy into a child of itself",
+			doc:   `{"a":{"b":1}}`,
+			patch: `[{"op":"copy","from":"/a","path":"/a/c"}]`,
+			want:  `{"a":{"b":1,"c":{"b":1}}}`,
+		},
+		{
+			name:  "copy is independent of its source",
+			doc:   `{"a":{"b":[1]}}`,
+			patch: `[{"op":"copy","from":"/a","path":"/c"},{"op":"add","path":"/c/b/-","value":2}]`,
+			want:  `{"a":{"b":[1]},"c":{"b":[1,2]}}`,
+		},
+		{
+			name:  "copy an array element to the end",
+			doc:   `[1,2]`,
+			patch: `[{"op":"copy","from":"/0","path":"/-"}]`,
+			want:  `[1,2,1]`,
+		},
+
+		// Whole document and malformed operations
+		{
+			name:  "replace the whole document",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"replace","path":"","value":[1]}]`,
+			want:  `[1]`,
+		},
+		{
+			name:  "remove the whole document",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"remove","path":""}]`,
+			err:   ErrInvalid,
+		},
+		{
+			name:  "add into a scalar",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"add","path":"/a/b","value":2}]`,
+			err:   ErrNotApplicable,
+		},
+		{
+			name:  "missing value",
+			doc:   `{}`,
+			patch: `[{"op":"add","path":"/a"}]`,
+			err:   ErrInvalid,
+		},
+		{
+			name:  "missing from",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"copy","path":"/b"}]`,
+			err:   ErrInvalid,
+		},
+		{
+			name:  "unknown op",
+			doc:   `{}`,
+			patch: `[{"op":"merge","path":"/a","value":1}]`,
+			err:   ErrInvalid,
+		},
+		{
+			name:  "not an array",
+			doc:   `{}`,
+			patch: `{"op":"add","path":"/a","value":1}`,
+			err:   ErrInvalid,
+		},
+		{
+			name:  "empty patch",
+			doc:   `{"a":1}`,
+			patch: `[]`,
+			want:  `{"a":1}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Apply = %s, %v; want error %v", got, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+// RFC 6902, Appendix A.13: an operation with duplicate members is
+// invalid. The decoder keeps the last "op", a remove of a missing member,
+// so the patch must still fail.
+func TestApplyDuplicateMembers(t *testing.T) {
+	_, err := Apply([]byte(`{"foo":"bar"}`), []byte(`[{"op":"add","path":"/baz","value":"qux","op":"remove"}]`))
+	if err == nil {
+		t.Fatal("Apply succeeded, want an error")
+	}
+}
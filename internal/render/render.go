@@ -0,0 +1,93 @@
+// Package render turns content bodies into safe HTML for display.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+	"time"
+
+	"cms/internal/models"
+	"cms/internal/sanitize"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+)
+
+// maxCacheEntries bounds the rendered-body cache.
+const maxCacheEntries = 1000
+
+type cacheEntry struct {
+	updatedAt time.Time
+	format    string
+	html      string
+}
+
+// Renderer renders content bodies by format and caches the result per item
+// until the item's UpdatedAt changes. It is safe for concurrent use.
+type Renderer struct {
+	sanitizer *sanitize.Sanitizer
+	markdown  goldmark.Markdown
+
+	mu    sync.RWMutex
+	cache map[string]cacheEntry
+}
+
+// New creates a renderer. All HTML output passes through sanitizer.
+func New(sanitizer *sanitize.Sanitizer) *Renderer {
+	return &Renderer{
+		sanitizer: sanitizer,
+		markdown: goldmark.New(
+			goldmark.WithExtensions(extension.Table, extension.Footnote),
+			goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		),
+		cache: make(map[string]cacheEntry),
+	}
+}
+
+// Item returns item's body as safe HTML, using the cache when possible.
+func (r *Renderer) Item(item models.Content) string {
+	r.mu.RLock()
+	e, ok := r.cache[item.ID]
+	r.mu.RUnlock()
+	if ok && e.updatedAt.Equal(item.UpdatedAt) && e.format == item.Format {
+		return e.html
+	}
+
+	out, err := r.Body(item.Format, item.Content)
+	if err != nil {
+		// Fall back to escaped source rather than failing the page
+		return "<pre>" + html.EscapeString(item.Content) + "</pre>"
+	}
+
+	r.mu.Lock()
+	if len(r.cache) >= maxCacheEntries {
+		for k := range r.cache { // Evict an arbitrary entry
+			delete(r.cache, k)
+			break
+		}
+	}
+	r.cache[item.ID] = cacheEntry{updatedAt: item.UpdatedAt, format: item.Format, html: out}
+	r.mu.Unlock()
+	return out
+}
+
+// Body renders body in format to safe HTML without caching, e.g. for previews.
+func (r *Renderer) Body(format, body string) (string, error) {
+	switch format {
+	case models.FormatMarkdown:
+		var buf bytes.Buffer
+		if err := r.markdown.Convert([]byte(body), &buf); err != nil {
+			return "", fmt.Errorf("failed to render markdown: %w", err)
+		}
+		return r.sanitizer.HTML(buf.String()), nil
+	case models.FormatPlain:
+		return strings.ReplaceAll(html.EscapeString(body), "\n", "<br>\n"), nil
+	default:
+		// HTML is sanitized again so items stored before a policy change are safe
+		return r.sanitizer.HTML(body), nil
+	}
+}
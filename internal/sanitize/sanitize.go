@@ -0,0 +1,56 @@
+// Package sanitize cleans user-supplied HTML against an allowlist.
+package sanitize
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"cms/internal/models"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// allowRule matches an allowlist entry: "tag" or "tag[attr|attr]".
+var allowRule = regexp.MustCompile(`^([a-z][a-z0-9]*)(?:\[([a-z0-9_:|-]+)\])?$`)
+
+// Sanitizer applies an HTML allowlist policy. It is safe for concurrent use.
+type Sanitizer struct {
+	policy *bluemonday.Policy
+}
+
+// New creates a sanitizer starting from the user generated content policy
+// (formatting, links, images, tables, lists) and additionally allowing the
+// given elements, each written as "tag" or "tag[attr|attr]".
+func New(allow []string) (*Sanitizer, error) {
+	p := bluemonday.UGCPolicy()
+	for _, rule := range allow {
+		rule = strings.ToLower(strings.TrimSpace(rule))
+		if rule == "" {
+			continue
+		}
+		m := allowRule.FindStringSubmatch(rule)
+		if m == nil {
+			return nil, fmt.Errorf("invalid allowed tag %q, want tag or tag[attr|attr]", rule)
+		}
+		p.AllowElements(m[1])
+		if m[2] != "" {
+			p.AllowAttrs(strings.Split(m[2], "|")...).OnElements(m[1])
+		}
+	}
+	return &Sanitizer{policy: p}, nil
+}
+
+// HTML returns body with everything outside the allowlist removed.
+func (s *Sanitizer) HTML(body string) string {
+	return s.policy.Sanitize(body)
+}
+
+// Clean sanitizes item's body in place according to its format, for use
+// before saving. Markdown and plain text are stored as-is; their rendered
+// output is sanitized or escaped instead.
+func (s *Sanitizer) Clean(item *models.Content) {
+	if item.Format == "" || item.Format == models.FormatHTML {
+		item.Content = s.HTML(item.Content)
+	}
+}
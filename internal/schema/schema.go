@@ -0,0 +1,423 @@
+// Package schema describes custom content types declaratively and
+// validates item values against them.
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"cms/internal/validate"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Field types.
+const (
+	TypeString   = "string"   // Single line of text
+	TypeText     = "text"     // Multi-line text
+	TypeNumber   = "number"   // Any number
+	TypeInteger  = "integer"  // Whole number
+	TypeBoolean  = "boolean"  // true or false
+	TypeDate     = "date"     // YYYY-MM-DD
+	TypeDateTime = "datetime" // RFC 3339 timestamp
+	TypeSelect   = "select"   // One of Options
+	TypeEmail    = "email"    // Email address
+	TypeURL      = "url"      // http(s) URL
+)
+
+// FieldTypes lists the supported field types.
+var FieldTypes = []string{TypeString, TypeText, TypeNumber, TypeInteger, TypeBoolean, TypeDate, TypeDateTime, TypeSelect, TypeEmail, TypeURL}
+
+// Limits on type definitions.
+const (
+	MaxFields     = 100
+	MaxNameLength = 64
+)
+
+var (
+	typeNamePattern  = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+	fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// Field is one field of a content type.
+type Field struct {
+	Name      string   `json:"name"`                 // Key in item values, e.g. "price"
+	Label     string   `json:"label,omitempty"`      // Form label, defaults to Name
+	Type      string   `json:"type"`                 // One of FieldTypes
+	Required  bool     `json:"required,omitempty"`   // Whether a value must be present
+	Default   any      `json:"default,omitempty"`    // Value used when none is given
+	Help      string   `json:"help,omitempty"`       // Hint shown under the form input
+	MinLength int      `json:"min_length,omitempty"` // Text types: minimum length in characters
+	MaxLength int      `json:"max_length,omitempty"` // Text types: maximum length in characters, 0 for none
+	Pattern   string   `json:"pattern,omitempty"`    // Text types: regular expression the whole value must match
+	Min       *float64 `json:"min,omitempty"`        // Number types: smallest allowed value
+	Max       *float64 `json:"max,omitempty"`        // Number types: largest allowed value
+	Options   []string `json:"options,omitempty"`    // Select: allowed values
+}
+
+// Type is a content type: a named set of fields.
+type Type struct {
+	Name        string    `json:"name"` // URL key, e.g. "product"
+	Label       string    `json:"label,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Fields      []Field   `json:"fields"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Errors maps field names to what is wrong with their values. The empty
+// key holds errors about the item or definition as a whole. Content items
+// report theirs the same way.
+type Errors = validate.Errors
+
+// ErrInvalid is wrapped by the errors of Parse for malformed documents.
+var ErrInvalid = errors.New("invalid type definition")
+
+// Parse decodes a type definition from JSON or, with isYAML, YAML, and
+// checks it. YAML is converted to JSON first, so both formats use the
+// same field names.
+func Parse(data []byte, isYAML bool) (Type, error) {
+	var t Type
+	if isYAML {
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return t, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return t, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		data = converted
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return t, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return t, t.Check()
+}
+
+// Check validates the definition itself: names, field types and
+// constraints, and that defaults satisfy their fields. It returns Errors
+// keyed by field name.
+func (t *Type) Check() error {
+	errs := Errors{}
+	if !typeNamePattern.MatchString(t.Name) || len(t.Name) > MaxNameLength {
+		errs["name"] = fmt.Sprintf("type name must be lowercase letters, numbers and hyphens, starting with a letter, at most %d characters", MaxNameLength)
+	}
+	if len(t.Fields) == 0 {
+		errs["fields"] = "at least one field is required"
+	}
+	if len(t.Fields) > MaxFields {
+		errs["fields"] = fmt.Sprintf("at most %d fields are allowed", MaxFields)
+	}
+	seen := make(map[string]bool, len(t.Fields))
+	for i := range t.Fields {
+		f := &t.Fields[i]
+		key := f.Name
+		if !fieldNamePattern.MatchString(f.Name) || len(f.Name) > MaxNameLength {
+			errs[fmt.Sprintf("fields[%d]", i)] = fmt.Sprintf("field name must be lowercase letters, numbers and underscores, starting with a letter, at most %d characters", MaxNameLength)
+			continue
+		}
+		if seen[key] {
+			errs[key] = "duplicate field name"
+			continue
+		}
+		seen[key] = true
+		if msg := f.check(); msg != "" {
+			errs[key] = msg
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// check validates one field definition, returning a message or "".
+func (f *Field) check() string {
+	if !validFieldType(f.Type) {
+		return "unknown type '" + f.Type + "', expected one of " + strings.Join(FieldTypes, ", ")
+	}
+	if f.MinLength < 0 || f.MaxLength < 0 || (f.MaxLength > 0 && f.MinLength > f.MaxLength) {
+		return "min_length and max_length must be non-negative, with min_length <= max_length"
+	}
+	if f.Min != nil && f.Max != nil && *f.Min > *f.Max {
+		return "min must not be greater than max"
+	}
+	if f.Pattern != "" {
+		if _, err := compilePattern(f.Pattern); err != nil {
+			return "invalid pattern: " + err.Error()
+		}
+	}
+	if f.Type == TypeSelect {
+		if len(f.Options) == 0 {
+			return "select fields need options"
+		}
+		seen := make(map[string]bool, len(f.Options))
+		for _, o := range f.Options {
+			if o == "" || seen[o] {
+				return "options must be non-empty and unique"
+			}
+			seen[o] = true
+		}
+	}
+	if f.Default != nil {
+		v, msg := f.coerce(f.Default)
+		if msg == "" {
+			msg = f.validate(v)
+		}
+		if msg != "" {
+			return "default " + msg
+		}
+		f.Default = v
+	}
+	return ""
+}
+
+// compilePattern anchors pattern so it must match the whole value.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile(`^(?:` + pattern + `)$`)
+}
+
+func validFieldType(t string) bool {
+	for _, ft := range FieldTypes {
+		if ft == t {
+			return true
+		}
+	}
+	return false
+}
+
+// DisplayLabel returns the label of the type, or its name.
+func (t Type) DisplayLabel() string {
+	if t.Label != "" {
+		return t.Label
+	}
+	return t.Name
+}
+
+// DisplayLabel returns the label of the field, or its name.
+func (f Field) DisplayLabel() string {
+	if f.Label != "" {
+		return f.Label
+	}
+	return f.Name
+}
+
+// Validate checks values against the type and returns them normalized:
+// defaults filled in, empty values dropped, numbers and times in
+// canonical form. Unknown fields are errors. The type must have passed
+// Check.
+func (t Type) Validate(values map[string]any) (map[string]any, error) {
+	errs := Errors{}
+	out := make(map[string]any, len(t.Fields))
+	known := make(map[string]bool, len(t.Fields))
+	for _, f := range t.Fields {
+		known[f.Name] = true
+		raw, ok := values[f.Name]
+		if !ok || isEmpty(raw) {
+			if f.Default != nil {
+				out[f.Name] = f.Default
+			} else if f.Required {
+				errs[f.Name] = "is required"
+			}
+			continue
+		}
+		v, msg := f.coerce(raw)
+		if msg == "" {
+			msg = f.validate(v)
+		}
+		if msg != "" {
+			errs[f.Name] = msg
+			continue
+		}
+		out[f.Name] = v
+	}
+	for name := range values {
+		if !known[name] {
+			errs[name] = "unknown field"
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return out, nil
+}
+
+// FromForm reads field values from HTML form inputs, where every value is
+// a string. Unchecked checkboxes are absent, so booleans default to false.
+func (t Type) FromForm(get func(name string) (string, bool)) map[string]any {
+	values := make(map[string]any, len(t.Fields))
+	for _, f := range t.Fields {
+		s, ok := get(f.Name)
+		if f.Type == TypeBoolean {
+			values[f.Name] = ok && s != "" && s != "false"
+			continue
+		}
+		if ok {
+			values[f.Name] = s
+		}
+	}
+	return values
+}
+
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	}
+	return false
+}
+
+// coerce converts a JSON or form value to the field's Go type: string,
+// float64, int64 or bool. Numbers and booleans may be given as strings.
+func (f Field) coerce(v any) (any, string) {
+	switch f.Type {
+	case TypeNumber, TypeInteger:
+		var n float64
+		switch v := v.(type) {
+		case float64:
+			n = v
+		case int:
+			n = float64(v)
+		case int64:
+			n = float64(v)
+		case string:
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, "must be a number"
+			}
+			n = parsed
+		default:
+			return nil, "must be a number"
+		}
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, "must be a number"
+		}
+		if f.Type == TypeInteger {
+			if n != math.Trunc(n) || math.Abs(n) > 1<<53 {
+				return nil, "must be a whole number"
+			}
+			return int64(n), ""
+		}
+		return n, ""
+	case TypeBoolean:
+		switch v := v.(type) {
+		case bool:
+			return v, ""
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, "must be true or false"
+			}
+			return b, ""
+		}
+		return nil, "must be true or false"
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return nil, "must be a string"
+	}
+	switch f.Type {
+	case TypeDate:
+		d, err := time.Parse(time.DateOnly, strings.TrimSpace(s))
+		if err != nil {
+			return nil, "must be a date like 2006-01-02"
+		}
+		return d.Format(time.DateOnly), ""
+	case TypeDateTime:
+		s = strings.TrimSpace(s)
+		ts, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			// datetime-local inputs send no seconds and no zone; read them as UTC
+			if ts, err = time.Parse("2006-01-02T15:04", s); err != nil {
+				return nil, "must be a time like 2006-01-02T15:04:05Z"
+			}
+		}
+		return ts.UTC().Format(time.RFC3339), ""
+	case TypeText:
+		return s, ""
+	}
+	return strings.TrimSpace(s), ""
+}
+
+// validate checks a coerced value against the field's constraints.
+func (f Field) validate(v any) string {
+	switch v := v.(type) {
+	case float64:
+		return f.checkRange(v)
+	case int64:
+		return f.checkRange(float64(v))
+	case string:
+		n := len([]rune(v))
+		if n < f.MinLength {
+			return fmt.Sprintf("must be at least %d characters", f.MinLength)
+		}
+		if f.MaxLength > 0 && n > f.MaxLength {
+			return fmt.Sprintf("must be at most %d characters", f.MaxLength)
+		}
+		if f.Pattern != "" {
+			if re, err := compilePattern(f.Pattern); err != nil || !re.MatchString(v) {
+				return "does not match the pattern " + f.Pattern
+			}
+		}
+		switch f.Type {
+		case TypeSelect:
+			for _, o := range f.Options {
+				if o == v {
+					return ""
+				}
+			}
+			return "must be one of " + strings.Join(f.Options, ", ")
+		case TypeEmail:
+			if addr, err := mail.ParseAddress(v); err != nil || addr.Address != v {
+				return "must be an email address"
+			}
+		case TypeURL:
+			if u, err := url.Parse(v); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return "must be an http or https URL"
+			}
+		}
+	}
+	return ""
+}
+
+func (f Field) checkRange(n float64) string {
+	if f.Min != nil && n < *f.Min {
+		return "must be at least " + strconv.FormatFloat(*f.Min, 'f', -1, 64)
+	}
+	if f.Max != nil && n > *f.Max {
+		return "must be at most " + strconv.FormatFloat(*f.Max, 'f', -1, 64)
+	}
+	return ""
+}
+
+// FormValue formats a stored value for an HTML input of the field.
+func (f Field) FormValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		if f.Type == TypeDateTime {
+			if ts, err := time.Parse(time.RFC3339, v); err == nil {
+				return ts.UTC().Format("2006-01-02T15:04")
+			}
+		}
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,85 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kljensen/snowball/english"
+	"github.com/kljensen/snowball/russian"
+)
+
+// token is a word of a text with its byte offsets.
+type token struct {
+	term       string // Stemmed, lower-cased form
+	start, end int
+}
+
+// tokenize splits text into words of letters and digits and stems each
+// one, dropping English and Russian stop words.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = appendToken(tokens, text, start, i)
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = appendToken(tokens, text, start, len(text))
+	}
+	return tokens
+}
+
+func appendToken(tokens []token, text string, start, end int) []token {
+	if term := stem(text[start:end]); term != "" {
+		tokens = append(tokens, token{term: term, start: start, end: end})
+	}
+	return tokens
+}
+
+// terms returns the stemmed terms of text.
+func terms(text string) []string {
+	tokens := tokenize(text)
+	out := make([]string, len(tokens))
+	for i, t := range tokens {
+		out[i] = t.term
+	}
+	return out
+}
+
+// stem lower-cases a word and reduces it to its stem with the Russian
+// stemmer for Cyrillic words and the English one otherwise. Stop words
+// yield "".
+func stem(word string) string {
+	word = strings.ToLower(word)
+	if isCyrillic(word) {
+		if russian.IsStopWord(word) {
+			return ""
+		}
+		return russian.Stem(word, false)
+	}
+	if english.IsStopWord(word) {
+		return ""
+	}
+	if utf8.RuneCountInString(word) < 3 {
+		return word // The English stemmer leaves short words alone anyway
+	}
+	return english.Stem(word, false)
+}
+
+func isCyrillic(word string) bool {
+	for _, r := range word {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return true
+		}
+	}
+	return false
+}
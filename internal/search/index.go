@@ -0,0 +1,199 @@
+// Package search maintains an in-memory full-text index of content items
+// with BM25 ranking and highlighted snippets.
+package search
+
+import (
+	"html"
+	"math"
+	"sort"
+	"sync"
+
+	"cms/internal/models"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// BM25 parameters.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// titleBoost counts every title term this many times, so matches in the
+// title outrank matches in the body.
+const titleBoost = 3
+
+type document struct {
+	title  string
+	slug   string
+	text   string // Body as plain text, for snippets
+	length int    // Number of (boosted) terms
+	terms  []string
+}
+
+// Index is an inverted index of content items. It is safe for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[string]*document
+	postings map[string]map[string]int // term -> document ID -> term frequency
+	totalLen int
+	strip    *bluemonday.Policy
+}
+
+// NewIndex creates an index holding items.
+func NewIndex(items []models.Content) *Index {
+	idx := &Index{strip: bluemonday.StrictPolicy()}
+	idx.Rebuild(items)
+	return idx
+}
+
+// Rebuild replaces the index contents with items.
+func (idx *Index) Rebuild(items []models.Content) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.docs = make(map[string]*document, len(items))
+	idx.postings = make(map[string]map[string]int)
+	idx.totalLen = 0
+	for _, item := range items {
+		idx.put(item)
+	}
+}
+
+// Put indexes item, replacing its previous version. Trashed items are
+// removed from the index instead.
+func (idx *Index) Put(item models.Content) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(item.ID)
+	idx.put(item)
+}
+
+// Remove drops an item from the index.
+func (idx *Index) Remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(id)
+}
+
+// Search returns up to limit items matching any term of query, best first.
+func (idx *Index) Search(query string, limit int) []models.SearchResult {
+	queryTerms := unique(terms(query))
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if len(queryTerms) == 0 || len(idx.docs) == 0 {
+		return []models.SearchResult{}
+	}
+
+	n := float64(len(idx.docs))
+	avgLen := float64(idx.totalLen) / n
+	scores := make(map[string]float64)
+	for _, term := range queryTerms {
+		posting := idx.postings[term]
+		if len(posting) == 0 {
+			continue
+		}
+		df := float64(len(posting))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id, tf := range posting {
+			f := float64(tf)
+			dl := float64(idx.docs[id].length)
+			scores[id] += idf * f * (k1 + 1) / (f + k1*(1-b+b*dl/avgLen))
+		}
+	}
+
+	results := make([]models.SearchResult, 0, len(scores))
+	for id, score := range scores {
+		doc := idx.docs[id]
+		results = append(results, models.SearchResult{ID: id, Title: doc.title, Slug: doc.slug, Score: score})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	want := make(map[string]bool, len(queryTerms))
+	for _, term := range queryTerms {
+		want[term] = true
+	}
+	for i := range results {
+		results[i].Snippet = snippet(idx.docs[results[i].ID].text, want)
+	}
+	return results
+}
+
+// put indexes item; the caller holds the write lock and has removed any
+// previous version.
+func (idx *Index) put(item models.Content) {
+	if item.Trashed() {
+		return
+	}
+
+	text := item.Content
+	if item.Format != models.FormatPlain {
+		text = html.UnescapeString(idx.strip.Sanitize(text))
+	}
+	doc := &document{title: item.Title, slug: item.Slug, text: text}
+
+	freq := make(map[string]int)
+	for _, term := range terms(item.Title) {
+		freq[term] += titleBoost
+	}
+	for _, term := range terms(item.Slug) {
+		freq[term]++
+	}
+	for _, term := range terms(text) {
+		freq[term]++
+	}
+	for term, tf := range freq {
+		posting := idx.postings[term]
+		if posting == nil {
+			posting = make(map[string]int)
+			idx.postings[term] = posting
+		}
+		posting[item.ID] = tf
+		doc.length += tf
+		doc.terms = append(doc.terms, term)
+	}
+
+	idx.docs[item.ID] = doc
+	idx.totalLen += doc.length
+}
+
+// remove drops an item; the caller holds the write lock.
+func (idx *Index) remove(id string) {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	for _, term := range doc.terms {
+		posting := idx.postings[term]
+		delete(posting, id)
+		if len(posting) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	idx.totalLen -= doc.length
+	delete(idx.docs, id)
+}
+
+func unique(list []string) []string {
+	seen := make(map[string]bool, len(list))
+	out := list[:0]
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
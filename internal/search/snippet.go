@@ -0,0 +1,60 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// snippetRadius is how many bytes of context a snippet shows around the
+// first match, on each side.
+const snippetRadius = 80
+
+// snippet returns an HTML-escaped excerpt of text around the first word
+// whose stem is in want, with every matching word wrapped in <mark>.
+func snippet(text string, want map[string]bool) string {
+	tokens := tokenize(text)
+	first := -1
+	for i, t := range tokens {
+		if want[t.term] {
+			first = i
+			break
+		}
+	}
+
+	start, end := 0, min(len(text), 2*snippetRadius)
+	if first >= 0 {
+		start = max(0, tokens[first].start-snippetRadius)
+		end = min(len(text), tokens[first].end+snippetRadius)
+	}
+	start, end = runeStart(text, start), runeStart(text, end)
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("&hellip;")
+	}
+	pos := start
+	for _, t := range tokens {
+		if t.start < start || t.end > end || !want[t.term] {
+			continue
+		}
+		sb.WriteString(html.EscapeString(text[pos:t.start]))
+		sb.WriteString("<mark>")
+		sb.WriteString(html.EscapeString(text[t.start:t.end]))
+		sb.WriteString("</mark>")
+		pos = t.end
+	}
+	sb.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		sb.WriteString("&hellip;")
+	}
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// runeStart moves i back to the start of the UTF-8 sequence it falls in.
+func runeStart(s string, i int) int {
+	for i > 0 && i < len(s) && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
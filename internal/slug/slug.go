@@ -0,0 +1,90 @@
+// Package slug builds URL slugs from titles.
+package slug
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxLength caps slugs, in bytes.
+	MaxLength = 80
+	// Fallback is used for titles that yield no slug characters.
+	Fallback = "untitled"
+)
+
+// Pattern matches well-formed slugs; see Valid.
+var Pattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// cyrillic transliterates Russian letters (lower case) to Latin.
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	// Ukrainian and Belarusian letters
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u",
+}
+
+// Valid reports whether s is a well-formed slug: lower-case letters and
+// digits separated by single hyphens.
+func Valid(s string) bool {
+	return len(s) <= MaxLength && Pattern.MatchString(s)
+}
+
+// Make builds a slug from title, transliterating Cyrillic and replacing
+// every other run of non-alphanumeric characters with a hyphen. It returns
+// "" if nothing usable remains.
+func Make(title string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(title) {
+		var part string
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			part = string(r)
+		case cyrillic[r] != "":
+			part = cyrillic[r]
+		case unicode.Is(unicode.Cyrillic, r):
+			continue // Hard and soft signs are dropped without a separator
+		default:
+			hyphen = sb.Len() > 0
+			continue
+		}
+		if sb.Len()+len(part)+1 > MaxLength {
+			break
+		}
+		if hyphen {
+			sb.WriteByte('-')
+			hyphen = false
+		}
+		sb.WriteString(part)
+	}
+	return sb.String()
+}
+
+// Unique returns base, or base with the lowest numeric suffix from 2 up,
+// whichever taken reports as free. An empty base becomes Fallback.
+func Unique(base string, taken func(string) bool) string {
+	if base == "" {
+		base = Fallback
+	}
+	s := base
+	for n := 2; taken(s); n++ {
+		s = WithSuffix(base, n)
+	}
+	return s
+}
+
+// WithSuffix returns base with "-n" appended, trimming base so the result
+// stays within MaxLength.
+func WithSuffix(base string, n int) string {
+	suffix := "-" + strconv.Itoa(n)
+	if len(base)+len(suffix) > MaxLength {
+		base = strings.TrimRight(base[:MaxLength-len(suffix)], "-")
+	}
+	return base + suffix
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cms/internal/auth"
+
+	"go.etcd.io/bbolt"
+)
+
+const attemptsBucket = "login_attempts"
+
+// AttemptStore persists login limiter state in the login_attempts bucket.
+// It implements auth.AttemptStore.
+type AttemptStore struct {
+	db *bbolt.DB
+}
+
+// NewAttemptStore creates an attempt store on an open bbolt database.
+func NewAttemptStore(db *bbolt.DB) (*AttemptStore, error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(attemptsBucket))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bucket %s: %w", attemptsBucket, err)
+	}
+	return &AttemptStore{db: db}, nil
+}
+
+// LoadAttempts returns all saved attempts keyed by limiter key.
+func (s *AttemptStore) LoadAttempts() (map[string]auth.Attempt, error) {
+	attempts := make(map[string]auth.Attempt)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(attemptsBucket)).ForEach(func(k, v []byte) error {
+			var a auth.Attempt
+			if err := json.Unmarshal(v, &a); err != nil {
+				return fmt.Errorf("failed to unmarshal attempts for %s: %w", string(k), err)
+			}
+			attempts[string(k)] = a
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error loading login attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// SaveAttempt stores the attempts for key.
+func (s *AttemptStore) SaveAttempt(key string, a auth.Attempt) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("failed to marshal attempts for %s: %w", key, err)
+	}
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(attemptsBucket)).Put([]byte(key), data)
+	})
+}
+
+// DeleteAttempt removes the attempts for key.
+func (s *AttemptStore) DeleteAttempt(key string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(attemptsBucket)).Delete([]byte(key))
+	})
+}
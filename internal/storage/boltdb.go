@@ -111,7 +111,7 @@ func (r *InitialDataReader) Close() error {
 }
 
 // LoadInitialContent reads all content items from the initial database.
-// Returns a map[string]models.Content for easy use in session storage.
+// Returns a map[string]models.Content used to seed the content store on first boot.
 func (r *InitialDataReader) LoadInitialContent() (map[string]models.Content, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -154,33 +154,3 @@ func (r *InitialDataReader) LoadInitialContent() (map[string]models.Content, err
 	log.Printf("Loaded %d items from initial database.", len(contentMap))
 	return contentMap, nil
 }
-
-/* --- Deprecated Write Operations ---
-   These functions are no longer needed as user data is stored in the session.
-
-// GetContent retrieves a content item by ID.
-// Returns the raw JSON data as []byte.
-func (e *EphemeralBoltDB) GetContent(id string) ([]byte, error) { ... }
-
-// ListContent retrieves all content items.
-// Returns a slice of models.Content.
-func (e *EphemeralBoltDB) ListContent() ([]models.Content, error) { ... }
-
-// CreateContent adds a new content item.
-// Takes ID and raw JSON data as []byte.
-func (e *EphemeralBoltDB) CreateContent(id string, data []byte) error { ... }
-
-// UpdateContent updates an existing content item.
-// Takes ID and raw JSON data as []byte.
-func (e *EphemeralBoltDB) UpdateContent(id string, data []byte) error { ... }
-
-// DeleteContent removes a content item by ID.
-func (e *EphemeralBoltDB) DeleteContent(id string) error { ... }
-
-// ExportDatabase exports the entire database content.
-func (e *EphemeralBoltDB) ExportDatabase() (map[string]map[string]json.RawMessage, error) { ... }
-
-// ImportDatabase imports data into the database, replacing existing data.
-func (e *EphemeralBoltDB) ImportDatabase(importData map[string]map[string]json.RawMessage) error { ... }
-
-*/
@@ -0,0 +1,759 @@
+package storage
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"cms/internal/models"
+	"cms/internal/search"
+
+	"go.etcd.io/bbolt"
+)
+
+const (
+	metaBucket      = "meta"
+	revisionsBucket = "revisions" // Holds one nested bucket of revisions per content ID
+	indexPrefix     = "idx_"      // Sorted index buckets, e.g. "idx_updated_at"
+	slugsBucket     = "slugs"     // Current slug -> content ID
+	oldSlugsBucket  = "old_slugs" // Previous slug -> content ID, for redirects
+	seededKey       = "seeded"
+)
+
+// OpenDB opens (or creates) the bbolt database file at path,
+// creating the parent directory if necessary.
+func OpenDB(path string) (*bbolt.DB, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("failed to create db directory %s: %w", dir, err)
+		}
+	}
+
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{
+		Timeout: 1 * time.Second, // Fail fast if another process holds the lock
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open boltdb file %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// BoltStore is a durable ContentStore backed by a bbolt database.
+// The full-text index is kept in memory and built when the store opens;
+// writes update it once their transaction has committed, so a failed
+// commit leaves it untouched. writes holds them one at a time across the
+// commit and the index update, so the index sees changes in commit order.
+type BoltStore struct {
+	db           *bbolt.DB
+	maxRevisions int // 0 means unlimited
+	fulltext     *search.Index
+	writes       sync.Mutex
+}
+
+// NewBoltStore creates a ContentStore on top of an open bbolt database
+// and ensures the required buckets exist. maxRevisions caps the revisions
+// kept per item (0 for no limit).
+func NewBoltStore(db *bbolt.DB, maxRevisions int) (*BoltStore, error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range []string{contentBucket, metaBucket, revisionsBucket} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return fmt.Errorf("failed to create bucket %s: %w", name, err)
+			}
+		}
+		// Build indexes missing from databases created by older versions
+		for _, field := range indexedFields {
+			if tx.Bucket(indexBucket(field)) != nil {
+				continue
+			}
+			if err := buildIndex(tx, field); err != nil {
+				return err
+			}
+		}
+		for _, taxonomy := range models.Taxonomies {
+			if tx.Bucket(relationBucket(taxonomy)) != nil {
+				continue
+			}
+			if err := buildRelations(tx, taxonomy); err != nil {
+				return err
+			}
+		}
+		if tx.Bucket([]byte(slugsBucket)) == nil {
+			return buildSlugIndex(tx)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s := &BoltStore{db: db, maxRevisions: maxRevisions}
+	items, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	s.fulltext = search.NewIndex(items)
+	return s, nil
+}
+
+// Seed writes the given items into the store on first boot only.
+// A marker in the meta bucket prevents re-seeding once the store has been
+// initialized, even if the user later deletes every item.
+// Returns true if the items were written.
+func (s *BoltStore) Seed(items map[string]models.Content) (bool, error) {
+	s.writes.Lock()
+	defer s.writes.Unlock()
+	seeded := false
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		meta := tx.Bucket([]byte(metaBucket))
+		if meta.Get([]byte(seededKey)) != nil {
+			return nil // Already initialized
+		}
+
+		for id, item := range items {
+			item.ID = id
+			if err := putContent(tx, item); err != nil {
+				return err
+			}
+		}
+		seeded = true
+		return meta.Put([]byte(seededKey), []byte(time.Now().UTC().Format(time.RFC3339)))
+	})
+	if err != nil {
+		return false, fmt.Errorf("error seeding content store: %w", err)
+	}
+	if seeded {
+		for id, item := range items {
+			item.ID = id
+			s.fulltext.Put(item)
+		}
+		log.Printf("Seeded content store with %d initial items.", len(items))
+	}
+	return seeded, nil
+}
+
+// Store implements Backend; a BoltStore serves every scope.
+func (s *BoltStore) Store(scope string) (ContentStore, error) {
+	return s, nil
+}
+
+// Stores implements Enumerator.
+func (s *BoltStore) Stores() []ContentStore {
+	return []ContentStore{s}
+}
+
+// Get retrieves a content item by ID.
+func (s *BoltStore) Get(id string) (models.Content, error) {
+	var item models.Content
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		v := tx.Bucket([]byte(contentBucket)).Get([]byte(id))
+		if v == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(v, &item)
+	})
+	return item, err
+}
+
+// GetBySlug retrieves the item using slug, or that used it before.
+func (s *BoltStore) GetBySlug(slug string) (models.Content, error) {
+	var item models.Content
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		id := tx.Bucket([]byte(slugsBucket)).Get([]byte(slug))
+		if id == nil {
+			id = tx.Bucket([]byte(oldSlugsBucket)).Get([]byte(slug))
+		}
+		if id == nil {
+			return ErrNotFound
+		}
+		v := tx.Bucket([]byte(contentBucket)).Get(id)
+		if v == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(v, &item)
+	})
+	return item, err
+}
+
+// List retrieves all content items in key order.
+func (s *BoltStore) List() ([]models.Content, error) {
+	var items []models.Content
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(contentBucket))
+		items = make([]models.Content, 0, b.Stats().KeyN)
+		return b.ForEach(func(k, v []byte) error {
+			var item models.Content
+			if err := json.Unmarshal(v, &item); err != nil {
+				log.Printf("BoltStore: Error unmarshaling content %s: %v", string(k), err)
+				return nil // Skip corrupt items rather than failing the whole list
+			}
+			items = append(items, item)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing content: %w", err)
+	}
+	return items, nil
+}
+
+// Query returns one page of the items matching q, walking the sorted index
+// bucket of the requested field.
+func (s *BoltStore) Query(q ListQuery) (ListResult, error) {
+	field, desc, err := ParseSort(q.Sort)
+	if err != nil {
+		return ListResult{}, err
+	}
+
+	var res ListResult
+	err = s.db.View(func(tx *bbolt.Tx) error {
+		content := tx.Bucket([]byte(contentBucket))
+		// Taxonomy filters are answered from the relation index, so other
+		// items are skipped without being decoded
+		ids := relatedIDs(q, func(taxonomy, term string) []string {
+			return relatedKeys(boltCursor{tx.Bucket(relationBucket(taxonomy)).Cursor()}, term)
+		})
+		cursor := boltCursor{tx.Bucket(indexBucket(field)).Cursor()}
+		res, err = scanIndex(cursor, field, desc, q, func(id string) (models.Content, bool) {
+			var item models.Content
+			if ids != nil && !ids[id] {
+				return item, false
+			}
+			v := content.Get([]byte(id))
+			if v == nil {
+				return item, false
+			}
+			if err := json.Unmarshal(v, &item); err != nil {
+				log.Printf("BoltStore: Error unmarshaling content %s: %v", id, err)
+				return item, false
+			}
+			return item, true
+		})
+		return err
+	})
+	return res, err
+}
+
+// Create adds a new content item.
+func (s *BoltStore) Create(item models.Content) error {
+	s.writes.Lock()
+	defer s.writes.Unlock()
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return createContent(tx, item)
+	})
+	if err != nil {
+		return err
+	}
+	s.fulltext.Put(item)
+	return nil
+}
+
+// Update replaces an existing content item if it is still at item.Version.
+func (s *BoltStore) Update(item models.Content) error {
+	s.writes.Lock()
+	defer s.writes.Unlock()
+	var saved models.Content
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		saved, err = updateContent(tx, item)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.fulltext.Put(saved)
+	return nil
+}
+
+// Batch saves the writes in one bbolt transaction, which is rolled back
+// if any of them fails.
+func (s *BoltStore) Batch(writes []BatchWrite) error {
+	s.writes.Lock()
+	defer s.writes.Unlock()
+	saved := make([]models.Content, 0, len(writes))
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		for i, w := range writes {
+			item := w.Item
+			var err error
+			if w.Create {
+				err = createContent(tx, item)
+			} else {
+				item, err = updateContent(tx, item)
+			}
+			if err != nil {
+				return &BatchError{Index: i, Err: err}
+			}
+			saved = append(saved, item)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, item := range saved {
+		s.fulltext.Put(item)
+	}
+	return nil
+}
+
+// Delete removes a content item and its revisions by ID if it is still at version.
+func (s *BoltStore) Delete(id string, version int) error {
+	s.writes.Lock()
+	defer s.writes.Unlock()
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(contentBucket))
+		v := b.Get([]byte(id))
+		if v == nil {
+			return ErrNotFound
+		}
+		var item models.Content
+		if err := json.Unmarshal(v, &item); err != nil {
+			return fmt.Errorf("failed to unmarshal content %s: %w", id, err)
+		}
+		if item.Version != version {
+			return ErrVersionConflict
+		}
+		if err := removeIndexKeys(tx, v); err != nil {
+			return err
+		}
+		if err := removeSlugs(tx, id, v); err != nil {
+			return err
+		}
+		if err := b.Delete([]byte(id)); err != nil {
+			return err
+		}
+		revs := tx.Bucket([]byte(revisionsBucket))
+		if err := revs.DeleteBucket([]byte(id)); err != nil && err != bbolt.ErrBucketNotFound {
+			return fmt.Errorf("failed to delete revisions of %s: %w", id, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.fulltext.Remove(id)
+	return nil
+}
+
+// Export returns all content in the bucket -> id -> JSON export format.
+func (s *BoltStore) Export() (map[string]map[string]json.RawMessage, error) {
+	exportData := map[string]map[string]json.RawMessage{
+		contentBucket: make(map[string]json.RawMessage),
+	}
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(contentBucket)).ForEach(func(k, v []byte) error {
+			// Copy v because it's only valid during the transaction
+			dataCopy := make([]byte, len(v))
+			copy(dataCopy, v)
+			exportData[contentBucket][string(k)] = dataCopy
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error exporting content: %w", err)
+	}
+	return exportData, nil
+}
+
+// Import replaces all content with the items of the "content" bucket in data.
+func (s *BoltStore) Import(data map[string]map[string]json.RawMessage) error {
+	items, err := decodeImport(data)
+	if err != nil {
+		return err
+	}
+	assignSlugs(items)
+
+	s.writes.Lock()
+	defer s.writes.Unlock()
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.DeleteBucket([]byte(contentBucket)); err != nil && err != bbolt.ErrBucketNotFound {
+			return fmt.Errorf("failed to clear content bucket: %w", err)
+		}
+		if _, err := tx.CreateBucket([]byte(contentBucket)); err != nil {
+			return fmt.Errorf("failed to recreate content bucket: %w", err)
+		}
+		for _, name := range append(append(indexBuckets(), relationBuckets()...), slugsBucket, oldSlugsBucket) {
+			if err := tx.DeleteBucket([]byte(name)); err != nil && err != bbolt.ErrBucketNotFound {
+				return fmt.Errorf("failed to clear bucket %s: %w", name, err)
+			}
+			if _, err := tx.CreateBucket([]byte(name)); err != nil {
+				return fmt.Errorf("failed to recreate bucket %s: %w", name, err)
+			}
+		}
+		for _, item := range items {
+			if err := putContent(tx, item); err != nil {
+				return err
+			}
+		}
+
+		// Imported items start with a fresh history
+		if err := tx.DeleteBucket([]byte(revisionsBucket)); err != nil && err != bbolt.ErrBucketNotFound {
+			return fmt.Errorf("failed to clear revisions bucket: %w", err)
+		}
+		if _, err := tx.CreateBucket([]byte(revisionsBucket)); err != nil {
+			return fmt.Errorf("failed to recreate revisions bucket: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	list := make([]models.Content, 0, len(items))
+	for _, item := range items {
+		list = append(list, item)
+	}
+	s.fulltext.Rebuild(list)
+	return nil
+}
+
+// Search returns the best full-text matches for query.
+func (s *BoltStore) Search(query string, limit int) ([]models.SearchResult, error) {
+	return s.fulltext.Search(query, limit), nil
+}
+
+// AddRevision records a version of an item, numbering it from the item's
+// revision sequence and pruning the oldest revisions beyond the limit.
+func (s *BoltStore) AddRevision(rev models.Revision) (models.Revision, error) {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.Bucket([]byte(revisionsBucket)).CreateBucketIfNotExists([]byte(rev.ContentID))
+		if err != nil {
+			return fmt.Errorf("failed to create revisions bucket for %s: %w", rev.ContentID, err)
+		}
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		rev.Number = int(seq)
+		data, err := json.Marshal(rev)
+		if err != nil {
+			return fmt.Errorf("failed to marshal revision %d of %s: %w", rev.Number, rev.ContentID, err)
+		}
+		if err := b.Put(revisionKey(rev.Number), data); err != nil {
+			return err
+		}
+
+		if s.maxRevisions <= 0 {
+			return nil
+		}
+		// Collect keys first; deleting while iterating makes the cursor skip entries
+		var keys [][]byte
+		c := b.Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			keys = append(keys, append([]byte(nil), k...))
+		}
+		for len(keys) > s.maxRevisions {
+			k := keys[0]
+			keys = keys[1:]
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return rev, err
+}
+
+// Revisions lists the revisions of an item, newest first.
+func (s *BoltStore) Revisions(id string) ([]models.Revision, error) {
+	var revs []models.Revision
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(revisionsBucket)).Bucket([]byte(id))
+		if b == nil {
+			return nil
+		}
+		c := b.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var rev models.Revision
+			if err := json.Unmarshal(v, &rev); err != nil {
+				log.Printf("BoltStore: Error unmarshaling revision %d of %s: %v", binary.BigEndian.Uint64(k), id, err)
+				continue
+			}
+			revs = append(revs, rev)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing revisions: %w", err)
+	}
+	return revs, nil
+}
+
+// Revision retrieves one revision of an item.
+func (s *BoltStore) Revision(id string, number int) (models.Revision, error) {
+	var rev models.Revision
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(revisionsBucket)).Bucket([]byte(id))
+		if b == nil || number < 1 {
+			return ErrRevisionNotFound
+		}
+		v := b.Get(revisionKey(number))
+		if v == nil {
+			return ErrRevisionNotFound
+		}
+		return json.Unmarshal(v, &rev)
+	})
+	return rev, err
+}
+
+// revisionKey encodes a revision number so keys sort numerically.
+func revisionKey(number int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(number))
+	return key
+}
+
+// putContent serializes an item into the content bucket and moves its
+// index keys and slug from the previous version, if any, to the new one.
+// Returns ErrSlugTaken if another item uses the slug.
+func putContent(tx *bbolt.Tx, item models.Content) error {
+	b := tx.Bucket([]byte(contentBucket))
+	var oldSlug string
+	if old := b.Get([]byte(item.ID)); old != nil {
+		if err := removeIndexKeys(tx, old); err != nil {
+			return err
+		}
+		var prev models.Content
+		if json.Unmarshal(old, &prev) == nil {
+			oldSlug = prev.Slug
+		}
+	}
+	if err := moveSlug(tx, oldSlug, item); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("failed to marshal content %s: %w", item.ID, err)
+	}
+	if err := b.Put([]byte(item.ID), data); err != nil {
+		return err
+	}
+	for _, field := range indexedFields {
+		if err := tx.Bucket(indexBucket(field)).Put([]byte(indexKey(field, item)), nil); err != nil {
+			return fmt.Errorf("failed to index content %s: %w", item.ID, err)
+		}
+	}
+	for _, taxonomy := range models.Taxonomies {
+		for _, key := range relationKeys(taxonomy, item) {
+			if err := tx.Bucket(relationBucket(taxonomy)).Put([]byte(key), nil); err != nil {
+				return fmt.Errorf("failed to index %s of content %s: %w", taxonomy, item.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// createContent stores a new item. Returns ErrExists if the ID is taken.
+func createContent(tx *bbolt.Tx, item models.Content) error {
+	if tx.Bucket([]byte(contentBucket)).Get([]byte(item.ID)) != nil {
+		return ErrExists
+	}
+	return putContent(tx, item)
+}
+
+// updateContent replaces a stored item if it is still at item.Version and
+// returns it as saved, with the next version.
+func updateContent(tx *bbolt.Tx, item models.Content) (models.Content, error) {
+	old := tx.Bucket([]byte(contentBucket)).Get([]byte(item.ID))
+	if old == nil {
+		return item, ErrNotFound
+	}
+	var prev models.Content
+	if err := json.Unmarshal(old, &prev); err != nil {
+		return item, fmt.Errorf("failed to unmarshal content %s: %w", item.ID, err)
+	}
+	if prev.Version != item.Version {
+		return item, ErrVersionConflict
+	}
+	item.Version++
+	return item, putContent(tx, item)
+}
+
+// removeIndexKeys deletes the index and relation keys of a stored item.
+func removeIndexKeys(tx *bbolt.Tx, data []byte) error {
+	var item models.Content
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil // Corrupt items were never indexed with a usable key
+	}
+	for _, field := range indexedFields {
+		if err := tx.Bucket(indexBucket(field)).Delete([]byte(indexKey(field, item))); err != nil {
+			return err
+		}
+	}
+	for _, taxonomy := range models.Taxonomies {
+		for _, key := range relationKeys(taxonomy, item) {
+			if err := tx.Bucket(relationBucket(taxonomy)).Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// moveSlug points item's slug at it and keeps oldSlug, if it differs, as a
+// redirect. Returns ErrSlugTaken if another item uses the new slug.
+func moveSlug(tx *bbolt.Tx, oldSlug string, item models.Content) error {
+	if oldSlug == item.Slug {
+		return nil
+	}
+	slugs, oldSlugs := tx.Bucket([]byte(slugsBucket)), tx.Bucket([]byte(oldSlugsBucket))
+	if owner := slugs.Get([]byte(item.Slug)); item.Slug != "" && owner != nil && string(owner) != item.ID {
+		return ErrSlugTaken
+	}
+	if oldSlug != "" {
+		if err := slugs.Delete([]byte(oldSlug)); err != nil {
+			return err
+		}
+		if err := oldSlugs.Put([]byte(oldSlug), []byte(item.ID)); err != nil {
+			return err
+		}
+	}
+	if item.Slug == "" {
+		return nil
+	}
+	if err := oldSlugs.Delete([]byte(item.Slug)); err != nil {
+		return err
+	}
+	return slugs.Put([]byte(item.Slug), []byte(item.ID))
+}
+
+// removeSlugs releases the current and previous slugs of the stored item id.
+func removeSlugs(tx *bbolt.Tx, id string, data []byte) error {
+	var item models.Content
+	if json.Unmarshal(data, &item) == nil && item.Slug != "" {
+		slugs := tx.Bucket([]byte(slugsBucket))
+		if string(slugs.Get([]byte(item.Slug))) == id {
+			if err := slugs.Delete([]byte(item.Slug)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Collect keys first; deleting while iterating makes the cursor skip entries
+	oldSlugs := tx.Bucket([]byte(oldSlugsBucket))
+	var keys [][]byte
+	err := oldSlugs.ForEach(func(k, v []byte) error {
+		if string(v) == id {
+			keys = append(keys, append([]byte(nil), k...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := oldSlugs.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildSlugIndex creates the slug buckets for databases created by older
+// versions, first regenerating missing, invalid and duplicate slugs.
+func buildSlugIndex(tx *bbolt.Tx) error {
+	b := tx.Bucket([]byte(contentBucket))
+	items := make(map[string]models.Content)
+	err := b.ForEach(func(k, v []byte) error {
+		var item models.Content
+		if err := json.Unmarshal(v, &item); err != nil {
+			log.Printf("BoltStore: Error unmarshaling content %s while indexing slugs: %v", string(k), err)
+			return nil
+		}
+		items[string(k)] = item
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if n := assignSlugs(items); n > 0 {
+		log.Printf("BoltStore: Generated slugs for %d items", n)
+	}
+
+	if _, err := tx.CreateBucketIfNotExists([]byte(oldSlugsBucket)); err != nil {
+		return fmt.Errorf("failed to create bucket %s: %w", oldSlugsBucket, err)
+	}
+	slugs, err := tx.CreateBucket([]byte(slugsBucket))
+	if err != nil {
+		return fmt.Errorf("failed to create bucket %s: %w", slugsBucket, err)
+	}
+	for id, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("failed to marshal content %s: %w", id, err)
+		}
+		if err := b.Put([]byte(id), data); err != nil {
+			return err
+		}
+		if err := slugs.Put([]byte(item.Slug), []byte(id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildIndex creates the index bucket for field from the stored items.
+func buildIndex(tx *bbolt.Tx, field string) error {
+	idx, err := tx.CreateBucket(indexBucket(field))
+	if err != nil {
+		return fmt.Errorf("failed to create %s index: %w", field, err)
+	}
+	return tx.Bucket([]byte(contentBucket)).ForEach(func(k, v []byte) error {
+		var item models.Content
+		if err := json.Unmarshal(v, &item); err != nil {
+			log.Printf("BoltStore: Error unmarshaling content %s while indexing: %v", string(k), err)
+			return nil
+		}
+		return idx.Put([]byte(indexKey(field, item)), nil)
+	})
+}
+
+// indexBucket names the bucket holding the sorted index of field.
+func indexBucket(field string) []byte {
+	return []byte(indexPrefix + field)
+}
+
+// indexBuckets names the buckets of every sorted index.
+func indexBuckets() []string {
+	names := make([]string, 0, len(indexedFields))
+	for _, field := range indexedFields {
+		names = append(names, string(indexBucket(field)))
+	}
+	return names
+}
+
+// decodeImport converts the "content" bucket of an export file into items,
+// forcing each item ID to match its key.
+func decodeImport(data map[string]map[string]json.RawMessage) (map[string]models.Content, error) {
+	bucket, ok := data[contentBucket]
+	if !ok {
+		return nil, fmt.Errorf("import data is missing the '%s' bucket", contentBucket)
+	}
+
+	items := make(map[string]models.Content, len(bucket))
+	for id, raw := range bucket {
+		var item models.Content
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return nil, fmt.Errorf("invalid import item '%s': %w", id, err)
+		}
+		item.ID = id
+		items[id] = item
+	}
+	return items, nil
+}
+
+// boltCursor adapts a bbolt cursor to indexCursor.
+type boltCursor struct {
+	c *bbolt.Cursor
+}
+
+func (c boltCursor) First() []byte          { k, _ := c.c.First(); return k }
+func (c boltCursor) Last() []byte           { k, _ := c.c.Last(); return k }
+func (c boltCursor) Seek(key []byte) []byte { k, _ := c.c.Seek(key); return k }
+func (c boltCursor) Next() []byte           { k, _ := c.c.Next(); return k }
+func (c boltCursor) Prev() []byte           { k, _ := c.c.Prev(); return k }
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"cms/internal/models"
+
+	"go.etcd.io/bbolt"
+)
+
+const mediaBucket = "media" // media ID -> models.Media JSON
+
+// ErrMediaNotFound is returned for an unknown media ID.
+var ErrMediaNotFound = errors.New("media not found")
+
+// MediaStore persists the metadata of uploaded files. The files are kept
+// in a media.BlobStore. Uploads are shared by every storage mode, like
+// user accounts.
+type MediaStore struct {
+	db *bbolt.DB
+}
+
+// NewMediaStore creates a media store on an open bbolt database.
+func NewMediaStore(db *bbolt.DB) (*MediaStore, error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(mediaBucket))
+		if err != nil {
+			return fmt.Errorf("failed to create bucket %s: %w", mediaBucket, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MediaStore{db: db}, nil
+}
+
+// List returns all uploads, newest first.
+func (s *MediaStore) List() ([]models.Media, error) {
+	var items []models.Media
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(mediaBucket)).ForEach(func(k, v []byte) error {
+			var m models.Media
+			if err := json.Unmarshal(v, &m); err != nil {
+				return fmt.Errorf("failed to unmarshal media %s: %w", string(k), err)
+			}
+			items = append(items, m)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing media: %w", err)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+	return items, nil
+}
+
+// Get retrieves an upload by ID.
+func (s *MediaStore) Get(id string) (models.Media, error) {
+	var m models.Media
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		v := tx.Bucket([]byte(mediaBucket)).Get([]byte(id))
+		if v == nil {
+			return ErrMediaNotFound
+		}
+		return json.Unmarshal(v, &m)
+	})
+	return m, err
+}
+
+// Put creates or replaces the metadata of an upload.
+func (s *MediaStore) Put(m models.Media) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return putJSON(tx.Bucket([]byte(mediaBucket)), m.ID, m)
+	})
+}
+
+// Delete removes the metadata of an upload.
+func (s *MediaStore) Delete(id string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(mediaBucket))
+		if b.Get([]byte(id)) == nil {
+			return ErrMediaNotFound
+		}
+		return b.Delete([]byte(id))
+	})
+}
@@ -0,0 +1,429 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+
+	"cms/internal/models"
+	"cms/internal/search"
+)
+
+// MemoryStore is a ContentStore that keeps all items in process memory.
+// Data is lost when the process exits.
+type MemoryStore struct {
+	mu           sync.RWMutex
+	items        map[string]models.Content
+	revisions    map[string][]models.Revision // Oldest first
+	indexes      map[string]*keyIndex         // Sorted index keys per indexed field
+	relations    map[string]*keyIndex         // Relation keys per taxonomy, see relationKeys
+	slugs        map[string]string            // Current slug -> ID
+	oldSlugs     map[string]string            // Previous slug -> ID, for redirects
+	fulltext     *search.Index
+	maxItems     int // 0 means unlimited
+	maxRevisions int // 0 means unlimited
+}
+
+// NewMemoryStore creates an in-memory store holding a copy of seed.
+// maxItems caps the number of items and maxRevisions the revisions kept
+// per item (0 for no limit).
+func NewMemoryStore(seed map[string]models.Content, maxItems, maxRevisions int) *MemoryStore {
+	items := make(map[string]models.Content, len(seed))
+	for id, item := range seed {
+		item.ID = id
+		item.Tags = slices.Clone(item.Tags) // Term slices are the only shared references
+		item.Categories = slices.Clone(item.Categories)
+		items[id] = item
+	}
+	assignSlugs(items)
+	s := &MemoryStore{
+		items:        items,
+		revisions:    make(map[string][]models.Revision),
+		maxItems:     maxItems,
+		maxRevisions: maxRevisions,
+		oldSlugs:     make(map[string]string),
+	}
+	s.reindex()
+	s.fulltext = search.NewIndex(s.values())
+	return s
+}
+
+// Store implements Backend; a MemoryStore serves every scope.
+func (s *MemoryStore) Store(scope string) (ContentStore, error) {
+	return s, nil
+}
+
+// Stores implements Enumerator.
+func (s *MemoryStore) Stores() []ContentStore {
+	return []ContentStore{s}
+}
+
+// Get retrieves a content item by ID.
+func (s *MemoryStore) Get(id string) (models.Content, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	item, ok := s.items[id]
+	if !ok {
+		return models.Content{}, ErrNotFound
+	}
+	return item, nil
+}
+
+// GetBySlug retrieves the item using slug, or that used it before.
+func (s *MemoryStore) GetBySlug(slug string) (models.Content, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.slugs[slug]
+	if !ok {
+		id, ok = s.oldSlugs[slug]
+	}
+	item, found := s.items[id]
+	if !ok || !found {
+		return models.Content{}, ErrNotFound
+	}
+	return item, nil
+}
+
+// List retrieves all content items ordered by ID.
+func (s *MemoryStore) List() ([]models.Content, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items := make([]models.Content, 0, len(s.items))
+	for _, item := range s.items {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items, nil
+}
+
+// Query returns one page of the items matching q, walking the sorted index
+// of the requested field.
+func (s *MemoryStore) Query(q ListQuery) (ListResult, error) {
+	field, desc, err := ParseSort(q.Sort)
+	if err != nil {
+		return ListResult{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := relatedIDs(q, func(taxonomy, term string) []string {
+		return relatedKeys(&sliceCursor{keys: *s.relations[taxonomy]}, term)
+	})
+	return scanIndex(&sliceCursor{keys: *s.indexes[field]}, field, desc, q, func(id string) (models.Content, bool) {
+		if ids != nil && !ids[id] {
+			return models.Content{}, false
+		}
+		item, ok := s.items[id]
+		return item, ok
+	})
+}
+
+// Search returns the best full-text matches for query.
+func (s *MemoryStore) Search(query string, limit int) ([]models.SearchResult, error) {
+	return s.fulltext.Search(query, limit), nil
+}
+
+// Create adds a new content item, honoring the item cap.
+func (s *MemoryStore) Create(item models.Content) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.create(item); err != nil {
+		return err
+	}
+	s.fulltext.Put(item)
+	return nil
+}
+
+// Update replaces an existing content item if it is still at item.Version.
+func (s *MemoryStore) Update(item models.Content) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved, err := s.update(item)
+	if err != nil {
+		return err
+	}
+	s.fulltext.Put(saved)
+	return nil
+}
+
+// Batch saves the writes under one lock, undoing the ones already made
+// if a later write fails.
+func (s *MemoryStore) Batch(writes []BatchWrite) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Slug moves are undone by restoring the maps; items and their index
+	// keys one by one, newest first
+	slugs, oldSlugs := maps.Clone(s.slugs), maps.Clone(s.oldSlugs)
+	prev := make([]models.Content, 0, len(writes)) // Zero for creates
+	saved := make([]models.Content, 0, len(writes))
+	for i, w := range writes {
+		item, prevItem := w.Item, s.items[w.Item.ID]
+		var err error
+		if w.Create {
+			err = s.create(item)
+		} else {
+			item, err = s.update(item)
+		}
+		if err != nil {
+			for j := len(saved) - 1; j >= 0; j-- {
+				s.indexRemove(saved[j])
+				delete(s.items, saved[j].ID)
+				if prev[j].ID != "" {
+					s.items[prev[j].ID] = prev[j]
+					s.indexAdd(prev[j])
+				}
+			}
+			s.slugs, s.oldSlugs = slugs, oldSlugs
+			return &BatchError{Index: i, Err: err}
+		}
+		prev = append(prev, prevItem)
+		saved = append(saved, item)
+	}
+	for _, item := range saved {
+		s.fulltext.Put(item)
+	}
+	return nil
+}
+
+// Delete removes a content item and its revisions by ID if it is still at version.
+func (s *MemoryStore) Delete(id string, version int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if item.Version != version {
+		return ErrVersionConflict
+	}
+	s.indexRemove(item)
+	s.fulltext.Remove(id)
+	delete(s.items, id)
+	delete(s.revisions, id)
+	if s.slugs[item.Slug] == id {
+		delete(s.slugs, item.Slug)
+	}
+	for old, owner := range s.oldSlugs {
+		if owner == id {
+			delete(s.oldSlugs, old)
+		}
+	}
+	return nil
+}
+
+// Export returns all content in the bucket -> id -> JSON export format.
+func (s *MemoryStore) Export() (map[string]map[string]json.RawMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	exportData := map[string]map[string]json.RawMessage{
+		contentBucket: make(map[string]json.RawMessage, len(s.items)),
+	}
+	for id, item := range s.items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal content %s: %w", id, err)
+		}
+		exportData[contentBucket][id] = data
+	}
+	return exportData, nil
+}
+
+// Import replaces all content with the items of the "content" bucket in data.
+func (s *MemoryStore) Import(data map[string]map[string]json.RawMessage) error {
+	items, err := decodeImport(data)
+	if err != nil {
+		return err
+	}
+	if s.maxItems > 0 && len(items) > s.maxItems {
+		return fmt.Errorf("%w: %d items exceed the limit of %d", ErrLimitReached, len(items), s.maxItems)
+	}
+
+	assignSlugs(items)
+
+	s.mu.Lock()
+	s.items = items
+	s.revisions = make(map[string][]models.Revision) // Imported items start with a fresh history
+	s.oldSlugs = make(map[string]string)
+	s.reindex()
+	s.fulltext.Rebuild(s.values())
+	s.mu.Unlock()
+	return nil
+}
+
+// AddRevision records a version of an item, numbering it after the item's
+// latest revision and pruning the oldest revisions beyond the limit.
+func (s *MemoryStore) AddRevision(rev models.Revision) (models.Revision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	revs := s.revisions[rev.ContentID]
+	rev.Number = 1
+	if len(revs) > 0 {
+		rev.Number = revs[len(revs)-1].Number + 1
+	}
+	revs = append(revs, rev)
+	if s.maxRevisions > 0 && len(revs) > s.maxRevisions {
+		revs = append([]models.Revision(nil), revs[len(revs)-s.maxRevisions:]...)
+	}
+	s.revisions[rev.ContentID] = revs
+	return rev, nil
+}
+
+// Revisions lists the revisions of an item, newest first.
+func (s *MemoryStore) Revisions(id string) ([]models.Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	revs := s.revisions[id]
+	out := make([]models.Revision, 0, len(revs))
+	for i := len(revs) - 1; i >= 0; i-- {
+		out = append(out, revs[i])
+	}
+	return out, nil
+}
+
+// Revision retrieves one revision of an item.
+func (s *MemoryStore) Revision(id string, number int) (models.Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, rev := range s.revisions[id] {
+		if rev.Number == number {
+			return rev, nil
+		}
+	}
+	return models.Revision{}, ErrRevisionNotFound
+}
+
+// create adds a new item. Callers hold the write lock.
+func (s *MemoryStore) create(item models.Content) error {
+	if _, ok := s.items[item.ID]; ok {
+		return ErrExists
+	}
+	if s.maxItems > 0 && len(s.items) >= s.maxItems {
+		return ErrLimitReached
+	}
+	if !s.slugFree(item) {
+		return ErrSlugTaken
+	}
+	s.items[item.ID] = item
+	s.moveSlug("", item)
+	s.indexAdd(item)
+	return nil
+}
+
+// update replaces an item if it is still at item.Version and returns it as
+// saved, with the next version. Callers hold the write lock.
+func (s *MemoryStore) update(item models.Content) (models.Content, error) {
+	old, ok := s.items[item.ID]
+	if !ok {
+		return item, ErrNotFound
+	}
+	if old.Version != item.Version {
+		return item, ErrVersionConflict
+	}
+	item.Version++
+	if !s.slugFree(item) {
+		return item, ErrSlugTaken
+	}
+	s.indexRemove(old)
+	s.items[item.ID] = item
+	s.moveSlug(old.Slug, item)
+	s.indexAdd(item)
+	return item, nil
+}
+
+// values returns the items in no particular order.
+func (s *MemoryStore) values() []models.Content {
+	items := make([]models.Content, 0, len(s.items))
+	for _, item := range s.items {
+		items = append(items, item)
+	}
+	return items
+}
+
+// slugFree reports whether item's slug is unused by other items. Callers
+// hold the lock.
+func (s *MemoryStore) slugFree(item models.Content) bool {
+	owner, ok := s.slugs[item.Slug]
+	return item.Slug == "" || !ok || owner == item.ID
+}
+
+// moveSlug points item's slug at it and keeps oldSlug, if it differs, as a
+// redirect. Callers hold the write lock.
+func (s *MemoryStore) moveSlug(oldSlug string, item models.Content) {
+	if oldSlug == item.Slug {
+		return
+	}
+	if oldSlug != "" {
+		delete(s.slugs, oldSlug)
+		s.oldSlugs[oldSlug] = item.ID
+	}
+	if item.Slug != "" {
+		s.slugs[item.Slug] = item.ID
+		delete(s.oldSlugs, item.Slug)
+	}
+}
+
+// reindex rebuilds every sorted index, the relation indexes and the slug
+// map from the items. Callers hold the write lock (or own the store exclusively).
+func (s *MemoryStore) reindex() {
+	s.indexes = make(map[string]*keyIndex, len(indexedFields))
+	for _, field := range indexedFields {
+		keys := make(keyIndex, 0, len(s.items))
+		for _, item := range s.items {
+			keys = append(keys, indexKey(field, item))
+		}
+		sort.Strings(keys)
+		s.indexes[field] = &keys
+	}
+	s.relations = make(map[string]*keyIndex, len(models.Taxonomies))
+	for _, taxonomy := range models.Taxonomies {
+		var keys keyIndex
+		for _, item := range s.items {
+			keys = append(keys, relationKeys(taxonomy, item)...)
+		}
+		sort.Strings(keys)
+		s.relations[taxonomy] = &keys
+	}
+	s.slugs = make(map[string]string, len(s.items))
+	for id, item := range s.items {
+		if item.Slug != "" {
+			s.slugs[item.Slug] = id
+		}
+	}
+}
+
+func (s *MemoryStore) indexAdd(item models.Content) {
+	for _, field := range indexedFields {
+		s.indexes[field].insert(indexKey(field, item))
+	}
+	for _, taxonomy := range models.Taxonomies {
+		for _, key := range relationKeys(taxonomy, item) {
+			s.relations[taxonomy].insert(key)
+		}
+	}
+}
+
+func (s *MemoryStore) indexRemove(item models.Content) {
+	for _, field := range indexedFields {
+		s.indexes[field].remove(indexKey(field, item))
+	}
+	for _, taxonomy := range models.Taxonomies {
+		for _, key := range relationKeys(taxonomy, item) {
+			s.relations[taxonomy].remove(key)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"cms/internal/models"
+)
+
+// Sort fields accepted by ListQuery.Sort. A leading "-" sorts descending.
+const (
+	SortUpdatedAt = "updated_at"
+	SortCreatedAt = "created_at"
+	SortTitle     = "title"
+	SortPublished = "published_at"
+
+	// DefaultSort lists the most recently updated items first.
+	DefaultSort = "-" + SortUpdatedAt
+)
+
+// indexedFields are the fields the stores keep sorted indexes for.
+var indexedFields = []string{SortUpdatedAt, SortCreatedAt, SortTitle, SortPublished}
+
+// maxTitleKey bounds the title part of an index key.
+const maxTitleKey = 256
+
+// Errors returned by ContentStore.Query.
+var (
+	ErrInvalidSort   = errors.New("invalid sort")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// ListQuery selects, orders and pages content items.
+type ListQuery struct {
+	Status        string    // Only items with this status, if set
+	Search        string    // Case-insensitive substring of title, slug or body, if set
+	CreatedAfter  time.Time // Only items created strictly after this time, if set
+	CreatedBefore time.Time // Only items created strictly before this time, if set
+	PublicAt      time.Time // Only items readers may see at this time (see Content.Public), if set
+	Tag           string    // Only items with this tag slug, if set
+	Categories    []string  // Only items in any of these category slugs, if set
+	Trashed       bool      // List trashed items instead of live ones
+	Sort          string    // One of the Sort* fields, optionally prefixed with "-"; empty means DefaultSort
+	Limit         int       // Page size; 0 returns every match
+	Cursor        string    // NextCursor of the previous page, if any
+}
+
+// ListResult is one page of a query.
+type ListResult struct {
+	Items      []models.Content
+	NextCursor string // Cursor for the following page, empty on the last page
+}
+
+// ParseSort splits a sort specification into its field and direction.
+func ParseSort(spec string) (field string, desc bool, err error) {
+	if spec == "" {
+		spec = DefaultSort
+	}
+	field, desc = strings.TrimPrefix(spec, "-"), strings.HasPrefix(spec, "-")
+	for _, f := range indexedFields {
+		if f == field {
+			return field, desc, nil
+		}
+	}
+	return "", false, ErrInvalidSort
+}
+
+// Match reports whether item passes the query's filters.
+func (q ListQuery) Match(item models.Content) bool {
+	if item.Trashed() != q.Trashed {
+		return false
+	}
+	if q.Status != "" && item.Status != q.Status {
+		return false
+	}
+	if !q.CreatedAfter.IsZero() && !item.CreatedAt.After(q.CreatedAfter) {
+		return false
+	}
+	if !q.CreatedBefore.IsZero() && !item.CreatedAt.Before(q.CreatedBefore) {
+		return false
+	}
+	if !q.PublicAt.IsZero() && !item.Public(q.PublicAt) {
+		return false
+	}
+	if q.Tag != "" && !slices.Contains(item.Tags, q.Tag) {
+		return false
+	}
+	if len(q.Categories) > 0 && !slices.ContainsFunc(item.Categories, func(c string) bool { return slices.Contains(q.Categories, c) }) {
+		return false
+	}
+	if q.Search != "" {
+		needle := strings.ToLower(q.Search)
+		if !strings.Contains(strings.ToLower(item.Title), needle) &&
+			!strings.Contains(strings.ToLower(item.Slug), needle) &&
+			!strings.Contains(strings.ToLower(item.Content), needle) {
+			return false
+		}
+	}
+	return true
+}
+
+// indexKey returns the key of item in the index for field. Keys sort by the
+// field value and end in "\x00" + ID, so equal values stay distinct.
+func indexKey(field string, item models.Content) string {
+	var value string
+	switch field {
+	case SortUpdatedAt:
+		value = timeKey(item.UpdatedAt)
+	case SortCreatedAt:
+		value = timeKey(item.CreatedAt)
+	case SortPublished:
+		value = timeKey(item.PublishTime())
+	case SortTitle:
+		value = strings.ToLower(item.Title)
+		if len(value) > maxTitleKey {
+			value = value[:maxTitleKey]
+		}
+	}
+	return value + "\x00" + item.ID
+}
+
+// timeKey formats t so that keys sort chronologically.
+func timeKey(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000000")
+}
+
+// idFromKey extracts the item ID from an index key.
+func idFromKey(key []byte) string {
+	return string(key[bytes.LastIndexByte(key, 0)+1:])
+}
+
+// indexCursor walks an index in key order. Every method returns the key it
+// lands on, or nil when it moves past either end.
+type indexCursor interface {
+	First() []byte
+	Last() []byte
+	Seek(key []byte) []byte // First key >= key
+	Next() []byte
+	Prev() []byte
+}
+
+// scanIndex runs q over a sorted index of field, loading matching items
+// with load until a page is full. Only the part of the index between the
+// cursor (or range bound) and the end of the page is visited.
+func scanIndex(c indexCursor, field string, desc bool, q ListQuery, load func(id string) (models.Content, bool)) (ListResult, error) {
+	spec := field
+	if desc {
+		spec = "-" + field
+	}
+	after, err := decodeCursor(q.Cursor, spec)
+	if err != nil {
+		return ListResult{}, err
+	}
+
+	// Time bounds narrow the scan when the index is ordered by them
+	var lo, hi []byte
+	switch {
+	case field == SortCreatedAt:
+		if !q.CreatedAfter.IsZero() {
+			lo = []byte(timeKey(q.CreatedAfter) + "\xff")
+		}
+		if !q.CreatedBefore.IsZero() {
+			hi = []byte(timeKey(q.CreatedBefore))
+		}
+	case field == SortPublished && !q.PublicAt.IsZero():
+		hi = []byte(timeKey(q.PublicAt) + "\xff") // Public items were published by then
+	}
+
+	var k []byte
+	switch {
+	case !desc && after != nil:
+		if k = c.Seek(after); bytes.Equal(k, after) {
+			k = c.Next()
+		}
+	case !desc && lo != nil:
+		k = c.Seek(lo)
+	case !desc:
+		k = c.First()
+	default:
+		upper := hi
+		if after != nil {
+			upper = after
+		}
+		if upper == nil {
+			k = c.Last()
+		} else if k = c.Seek(upper); k == nil {
+			k = c.Last()
+		} else {
+			k = c.Prev()
+		}
+	}
+
+	res := ListResult{Items: make([]models.Content, 0)}
+	var last []byte
+	for ; k != nil; k = step(c, desc) {
+		if !desc && hi != nil && bytes.Compare(k, hi) >= 0 {
+			break
+		}
+		if desc && lo != nil && bytes.Compare(k, lo) < 0 {
+			break
+		}
+		item, ok := load(idFromKey(k))
+		if !ok || !q.Match(item) {
+			continue
+		}
+		if q.Limit > 0 && len(res.Items) == q.Limit {
+			res.NextCursor = encodeCursor(spec, last)
+			break
+		}
+		res.Items = append(res.Items, item)
+		last = append(last[:0], k...)
+	}
+	return res, nil
+}
+
+func step(c indexCursor, desc bool) []byte {
+	if desc {
+		return c.Prev()
+	}
+	return c.Next()
+}
+
+// encodeCursor makes an opaque cursor pointing after key in the given sort.
+func encodeCursor(spec string, key []byte) string {
+	return base64.RawURLEncoding.EncodeToString(append([]byte(spec+"\n"), key...))
+}
+
+// decodeCursor returns the index key a cursor points after, or nil for no
+// cursor. Cursors issued for another sort order are rejected.
+func decodeCursor(cursor, spec string) ([]byte, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	prefix := []byte(spec + "\n")
+	if !bytes.HasPrefix(raw, prefix) || len(raw) == len(prefix) {
+		return nil, ErrInvalidCursor
+	}
+	return raw[len(prefix):], nil
+}
+
+// keyIndex is an in-memory sorted index of keys.
+type keyIndex []string
+
+func (x *keyIndex) insert(key string) {
+	i := sort.SearchStrings(*x, key)
+	if i < len(*x) && (*x)[i] == key {
+		return
+	}
+	*x = append(*x, "")
+	copy((*x)[i+1:], (*x)[i:])
+	(*x)[i] = key
+}
+
+func (x *keyIndex) remove(key string) {
+	i := sort.SearchStrings(*x, key)
+	if i < len(*x) && (*x)[i] == key {
+		*x = append((*x)[:i], (*x)[i+1:]...)
+	}
+}
+
+// sliceCursor implements indexCursor over a keyIndex.
+type sliceCursor struct {
+	keys keyIndex
+	pos  int
+}
+
+func (c *sliceCursor) at() []byte {
+	if c.pos < 0 || c.pos >= len(c.keys) {
+		return nil
+	}
+	return []byte(c.keys[c.pos])
+}
+
+func (c *sliceCursor) First() []byte { c.pos = 0; return c.at() }
+func (c *sliceCursor) Last() []byte  { c.pos = len(c.keys) - 1; return c.at() }
+func (c *sliceCursor) Next() []byte  { c.pos++; return c.at() }
+func (c *sliceCursor) Prev() []byte  { c.pos--; return c.at() }
+func (c *sliceCursor) Seek(key []byte) []byte {
+	c.pos = sort.SearchStrings(c.keys, string(key))
+	return c.at()
+}
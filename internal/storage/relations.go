@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"cms/internal/models"
+
+	"go.etcd.io/bbolt"
+)
+
+// relationPrefix names the bucket relating the terms of a taxonomy to
+// items, e.g. "rel_tags". Keys are term + "\x00" + item ID, so the items of
+// a term are one prefix scan away.
+const relationPrefix = "rel_"
+
+// relationKeys returns the keys of item in the relation index of taxonomy.
+func relationKeys(taxonomy string, item models.Content) []string {
+	terms := item.Terms(taxonomy)
+	keys := make([]string, 0, len(terms))
+	for _, term := range terms {
+		keys = append(keys, term+"\x00"+item.ID)
+	}
+	return keys
+}
+
+// relatedKeys collects the IDs of the items related to term from a
+// relation index.
+func relatedKeys(c indexCursor, term string) []string {
+	prefix := []byte(term + "\x00")
+	var ids []string
+	for k := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k = c.Next() {
+		ids = append(ids, idFromKey(k))
+	}
+	return ids
+}
+
+// relatedIDs returns the IDs of the items passing q's taxonomy filters,
+// looking up the items of each term with related, or nil when q has none.
+func relatedIDs(q ListQuery, related func(taxonomy, term string) []string) map[string]bool {
+	var ids map[string]bool
+	if q.Tag != "" {
+		ids = make(map[string]bool)
+		for _, id := range related(models.TaxonomyTags, q.Tag) {
+			ids[id] = true
+		}
+	}
+	if len(q.Categories) > 0 {
+		inCategory := make(map[string]bool)
+		for _, category := range q.Categories {
+			for _, id := range related(models.TaxonomyCategories, category) {
+				if ids == nil || ids[id] {
+					inCategory[id] = true
+				}
+			}
+		}
+		ids = inCategory
+	}
+	return ids
+}
+
+// relationBucket names the bucket holding the relation index of taxonomy.
+func relationBucket(taxonomy string) []byte {
+	return []byte(relationPrefix + taxonomy)
+}
+
+// relationBuckets names the buckets of every relation index.
+func relationBuckets() []string {
+	names := make([]string, 0, len(models.Taxonomies))
+	for _, taxonomy := range models.Taxonomies {
+		names = append(names, string(relationBucket(taxonomy)))
+	}
+	return names
+}
+
+// buildRelations creates the relation index of taxonomy from the stored
+// items, for databases created by older versions.
+func buildRelations(tx *bbolt.Tx, taxonomy string) error {
+	idx, err := tx.CreateBucket(relationBucket(taxonomy))
+	if err != nil {
+		return fmt.Errorf("failed to create %s relation index: %w", taxonomy, err)
+	}
+	return tx.Bucket([]byte(contentBucket)).ForEach(func(k, v []byte) error {
+		var item models.Content
+		if err := json.Unmarshal(v, &item); err != nil {
+			log.Printf("BoltStore: Error unmarshaling content %s while indexing %s: %v", string(k), taxonomy, err)
+			return nil
+		}
+		for _, key := range relationKeys(taxonomy, item) {
+			if err := idx.Put([]byte(key), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"cms/internal/models"
+)
+
+// SandboxBackend gives every scope (visitor session) its own MemoryStore
+// cloned from a seed, so the same binary can run as a public playground.
+// Idle sandboxes are dropped after the configured TTL.
+type SandboxBackend struct {
+	mu        sync.Mutex
+	sandboxes map[string]*sandbox
+	seed      map[string]models.Content
+	view      *MemoryStore // The seed as public readers see it; no sandbox writes to it
+	maxItems  int
+	maxRevs   int
+	ttl       time.Duration
+	stop      chan struct{}
+}
+
+type sandbox struct {
+	store    *MemoryStore
+	lastSeen time.Time
+}
+
+// NewSandboxBackend creates a sandbox backend. maxItems caps the items per
+// sandbox and maxRevs the revisions per item (0 for no limit); ttl is how
+// long an idle sandbox is kept (0 keeps sandboxes until reset).
+func NewSandboxBackend(seed map[string]models.Content, maxItems, maxRevs int, ttl time.Duration) *SandboxBackend {
+	b := &SandboxBackend{
+		sandboxes: make(map[string]*sandbox),
+		seed:      seed,
+		view:      NewMemoryStore(seed, 0, 0),
+		maxItems:  maxItems,
+		maxRevs:   maxRevs,
+		ttl:       ttl,
+		stop:      make(chan struct{}),
+	}
+	if ttl > 0 {
+		go b.sweepLoop()
+	}
+	return b
+}
+
+// Store returns the sandbox for scope, creating it from the seed if needed.
+func (b *SandboxBackend) Store(scope string) (ContentStore, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sb, ok := b.sandboxes[scope]
+	if !ok {
+		log.Printf("SandboxBackend: Initializing sandbox (%d active)", len(b.sandboxes)+1)
+		sb = &sandbox{store: NewMemoryStore(b.seed, b.maxItems, b.maxRevs)}
+		b.sandboxes[scope] = sb
+	}
+	sb.lastSeen = time.Now()
+	return sb.store, nil
+}
+
+// View implements Viewer, returning the seed content shared by every visitor.
+func (b *SandboxBackend) View() ContentStore {
+	return b.view
+}
+
+// Stores implements Enumerator, returning every active sandbox.
+func (b *SandboxBackend) Stores() []ContentStore {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stores := make([]ContentStore, 0, len(b.sandboxes))
+	for _, sb := range b.sandboxes {
+		stores = append(stores, sb.store)
+	}
+	return stores
+}
+
+// Reset discards the sandbox for scope; the next access starts from the seed again.
+func (b *SandboxBackend) Reset(scope string) {
+	b.mu.Lock()
+	delete(b.sandboxes, scope)
+	b.mu.Unlock()
+}
+
+// Close stops the background sweeper.
+func (b *SandboxBackend) Close() {
+	if b.ttl > 0 {
+		close(b.stop)
+	}
+}
+
+// sweepLoop periodically removes sandboxes idle for longer than the TTL.
+func (b *SandboxBackend) sweepLoop() {
+	interval := b.ttl / 4
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			b.sweep(time.Now())
+		case <-b.stop:
+			return
+		}
+	}
+}
+
+func (b *SandboxBackend) sweep(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	removed := 0
+	for scope, sb := range b.sandboxes {
+		if now.Sub(sb.lastSeen) > b.ttl {
+			delete(b.sandboxes, scope)
+			removed++
+		}
+	}
+	if removed > 0 {
+		log.Printf("SandboxBackend: Removed %d idle sandboxes (%d active)", removed, len(b.sandboxes))
+	}
+}
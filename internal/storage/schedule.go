@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"cms/internal/models"
+)
+
+// maxScheduleWait bounds how long the scheduler sleeps, in case a schedule
+// is changed without a call to Wake.
+const maxScheduleWait = time.Minute
+
+// Scheduler publishes scheduled items when their publish time comes and
+// archives published items when they expire.
+type Scheduler struct {
+	stores Enumerator
+	wake   chan struct{}
+	stop   chan struct{}
+}
+
+// NewScheduler starts a scheduler for the stores of a backend.
+func NewScheduler(stores Enumerator) *Scheduler {
+	s := &Scheduler{
+		stores: stores,
+		wake:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+	}
+	go s.scheduleLoop()
+	return s
+}
+
+// Wake makes the scheduler recompute its next run, e.g. after an item's
+// schedule changed. It is safe to call on a nil Scheduler.
+func (s *Scheduler) Wake() {
+	if s == nil {
+		return
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default: // A wake-up is already pending
+	}
+}
+
+// Close stops the background scheduler.
+func (s *Scheduler) Close() {
+	close(s.stop)
+}
+
+// scheduleLoop runs at start-up, whenever the next item falls due and on
+// Wake, waiting at most maxScheduleWait between runs.
+func (s *Scheduler) scheduleLoop() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+		case <-s.wake:
+		case <-s.stop:
+			return
+		}
+		timer.Reset(s.run(time.Now().UTC())) // Reset discards a stale expiry since Go 1.23
+	}
+}
+
+// run applies every due transition and returns how long to wait for the next.
+func (s *Scheduler) run(now time.Time) time.Duration {
+	wait := maxScheduleWait
+	changed := 0
+	for _, store := range s.stores.Stores() {
+		n, next, err := applySchedules(store, now)
+		if err != nil {
+			log.Printf("Scheduler: Error applying schedules: %v", err)
+		}
+		changed += n
+		if !next.IsZero() && next.Sub(now) < wait {
+			wait = next.Sub(now)
+		}
+	}
+	if changed > 0 {
+		log.Printf("Scheduler: Changed the status of %d items", changed)
+	}
+	return wait
+}
+
+// applySchedules publishes and archives the items of store that are due at
+// now, recording a revision for each. It returns how many items changed and
+// the earliest future transition, zero if none is pending.
+func applySchedules(store ContentStore, now time.Time) (int, time.Time, error) {
+	items, err := store.List()
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	changed := 0
+	var next time.Time
+	pending := func(t time.Time) {
+		if next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+	for _, item := range items {
+		if item.Trashed() {
+			continue
+		}
+		status := nextStatus(item, now)
+		if status == item.Status {
+			switch {
+			case item.Status == models.StatusScheduled:
+				pending(item.PublishedAt)
+			case item.Status == models.StatusPublished && !item.ExpiresAt.IsZero():
+				pending(item.ExpiresAt)
+			}
+			continue
+		}
+
+		// Reload to keep edits saved since the listing
+		item, err = store.Get(item.ID)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return changed, next, err
+		}
+		if item.Trashed() || nextStatus(item, now) != status {
+			continue
+		}
+		item.Status = status
+		item.UpdatedAt = now
+		if err := store.Update(item); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue // Deleted meanwhile
+			}
+			return changed, next, err
+		}
+		rev := models.Revision{
+			ContentID: item.ID,
+			Action:    models.RevisionSchedule,
+			CreatedAt: now,
+			Snapshot:  item,
+		}
+		if _, err := store.AddRevision(rev); err != nil {
+			log.Printf("Scheduler: Error recording revision of %s: %v", item.ID, err)
+		}
+		if status == models.StatusPublished && !item.ExpiresAt.IsZero() {
+			pending(item.ExpiresAt)
+		}
+		changed++
+	}
+	return changed, next, nil
+}
+
+// nextStatus returns the status item should have at now.
+func nextStatus(item models.Content, now time.Time) string {
+	status := item.Status
+	if status == models.StatusScheduled && !item.PublishedAt.After(now) {
+		status = models.StatusPublished
+	}
+	if status == models.StatusPublished && !item.ExpiresAt.IsZero() && !item.ExpiresAt.After(now) {
+		status = models.StatusArchived
+	}
+	return status
+}
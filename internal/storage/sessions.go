@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+const sessionsBucket = "sessions"
+
+// BoltSessionProvider implements the fasthttp/session Provider interface on
+// top of bbolt, so sessions survive restarts and deploys.
+//
+// Each record is stored as an 8-byte big-endian expiry timestamp
+// (Unix nanoseconds, 0 for no expiry) followed by the encoded session data.
+type BoltSessionProvider struct {
+	db *bbolt.DB
+}
+
+// NewBoltSessionProvider creates a session provider on an open bbolt database.
+func NewBoltSessionProvider(db *bbolt.DB) (*BoltSessionProvider, error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(sessionsBucket))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bucket %s: %w", sessionsBucket, err)
+	}
+	return &BoltSessionProvider{db: db}, nil
+}
+
+// Get returns the data of the given session id, or nil if it does not exist
+// or has expired.
+func (p *BoltSessionProvider) Get(id []byte) ([]byte, error) {
+	var data []byte
+	err := p.db.View(func(tx *bbolt.Tx) error {
+		v := tx.Bucket([]byte(sessionsBucket)).Get(id)
+		if len(v) < 8 || isExpired(v, time.Now()) {
+			return nil // Expired records are removed by GC
+		}
+		// Copy v because it's only valid during the transaction
+		data = make([]byte, len(v)-8)
+		copy(data, v[8:])
+		return nil
+	})
+	return data, err
+}
+
+// Save stores the session data with the given expiration.
+func (p *BoltSessionProvider) Save(id, data []byte, expiration time.Duration) error {
+	return p.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(sessionsBucket)).Put(id, encodeSession(data, expiration))
+	})
+}
+
+// Destroy removes the session with the given id.
+func (p *BoltSessionProvider) Destroy(id []byte) error {
+	return p.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(sessionsBucket)).Delete(id)
+	})
+}
+
+// Regenerate moves the session data from id to newID and refreshes its expiration.
+func (p *BoltSessionProvider) Regenerate(id, newID []byte, expiration time.Duration) error {
+	return p.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(sessionsBucket))
+		v := b.Get(id)
+		if len(v) < 8 {
+			return nil // Nothing to move
+		}
+		record := encodeSession(v[8:], expiration)
+		if err := b.Delete(id); err != nil {
+			return err
+		}
+		return b.Put(newID, record)
+	})
+}
+
+// Count returns the total of stored sessions, including expired ones not yet collected.
+func (p *BoltSessionProvider) Count() int {
+	count := 0
+	_ = p.db.View(func(tx *bbolt.Tx) error {
+		count = tx.Bucket([]byte(sessionsBucket)).Stats().KeyN
+		return nil
+	})
+	return count
+}
+
+// NeedGC indicates that expired sessions must be swept periodically.
+func (p *BoltSessionProvider) NeedGC() bool {
+	return true
+}
+
+// GC deletes all expired sessions.
+func (p *BoltSessionProvider) GC() error {
+	now := time.Now()
+	removed := 0
+	err := p.db.Update(func(tx *bbolt.Tx) error {
+		c := tx.Bucket([]byte(sessionsBucket)).Cursor()
+		for k, v := c.First(); k != nil; {
+			if len(v) < 8 || isExpired(v, now) {
+				if err := c.Delete(); err != nil {
+					return err
+				}
+				removed++
+				// Delete moves the cursor onto the next item
+				k, v = c.Seek(k)
+				continue
+			}
+			k, v = c.Next()
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error sweeping sessions: %w", err)
+	}
+	if removed > 0 {
+		log.Printf("BoltSessionProvider: Removed %d expired sessions", removed)
+	}
+	return nil
+}
+
+func encodeSession(data []byte, expiration time.Duration) []byte {
+	var expiresAt int64
+	if expiration > 0 {
+		expiresAt = time.Now().Add(expiration).UnixNano()
+	}
+	record := make([]byte, 8+len(data))
+	binary.BigEndian.PutUint64(record, uint64(expiresAt))
+	copy(record[8:], data)
+	return record
+}
+
+func isExpired(record []byte, now time.Time) bool {
+	expiresAt := int64(binary.BigEndian.Uint64(record))
+	return expiresAt != 0 && now.UnixNano() >= expiresAt
+}
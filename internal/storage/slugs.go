@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"sort"
+
+	"cms/internal/models"
+	"cms/internal/slug"
+)
+
+// assignSlugs gives every item in items a valid slug no other item uses.
+// Older items keep their slug when it is valid; the rest get one generated
+// from the title. Returns the number of items whose slug changed.
+func assignSlugs(items map[string]models.Content) int {
+	ordered := make([]models.Content, 0, len(items))
+	for _, item := range items {
+		ordered = append(ordered, item)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if !ordered[i].CreatedAt.Equal(ordered[j].CreatedAt) {
+			return ordered[i].CreatedAt.Before(ordered[j].CreatedAt)
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	taken := make(map[string]bool, len(items))
+	changed := 0
+	for _, item := range ordered {
+		if !slug.Valid(item.Slug) || taken[item.Slug] {
+			item.Slug = slug.Unique(slug.Make(item.Title), func(s string) bool { return taken[s] })
+			items[item.ID] = item
+			changed++
+		}
+		taken[item.Slug] = true
+	}
+	return changed
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"cms/internal/models"
+)
+
+// Errors returned by ContentStore implementations.
+var (
+	ErrNotFound        = errors.New("content not found")
+	ErrExists          = errors.New("content already exists")
+	ErrLimitReached    = errors.New("content limit reached")
+	ErrSlugTaken       = errors.New("slug already in use")
+	ErrVersionConflict = errors.New("content was changed by another update")
+
+	ErrRevisionNotFound = errors.New("revision not found")
+)
+
+// ContentStore is the persistence contract used by the HTTP handlers.
+// Implementations must be safe for concurrent use.
+type ContentStore interface {
+	// Get retrieves a content item by ID. Returns ErrNotFound if it does not exist.
+	Get(id string) (models.Content, error)
+	// GetBySlug retrieves the item using slug, or the item that used it
+	// before its slug changed (callers compare the returned item's Slug).
+	// Trashed items keep their slug. Returns ErrNotFound if neither exists.
+	GetBySlug(slug string) (models.Content, error)
+	// List retrieves all content items, including trashed ones.
+	List() ([]models.Content, error)
+	// Query returns one page of the items matching q, in the requested order.
+	// Returns ErrInvalidSort or ErrInvalidCursor for malformed queries.
+	Query(q ListQuery) (ListResult, error)
+	// Search returns up to limit items outside the trash matching a
+	// full-text query, best match first.
+	Search(query string, limit int) ([]models.SearchResult, error)
+	// Create adds a new content item. Returns ErrExists if the ID is already
+	// taken and ErrSlugTaken if another item uses its slug.
+	Create(item models.Content) error
+	// Update replaces an existing content item. Returns ErrNotFound if it does
+	// not exist and ErrSlugTaken if another item uses its slug. The previous
+	// slug, if it changed, keeps resolving to the item through GetBySlug.
+	// item.Version must be the stored version, otherwise ErrVersionConflict
+	// is returned; the item is saved with its Version incremented.
+	Update(item models.Content) error
+	// Batch saves a list of creates and updates in one transaction: either
+	// all of them are saved or, if one fails, none is. Each write is checked
+	// as by Create or Update and sees the writes before it, so an item may
+	// be updated twice, at consecutive versions. Failures are *BatchError.
+	Batch(writes []BatchWrite) error
+	// Delete permanently removes a content item and its revisions by ID.
+	// Returns ErrNotFound if it does not exist, and ErrVersionConflict if it
+	// is no longer at version, so an item restored meanwhile is kept.
+	// Moving an item to the trash is an Update that sets DeletedAt.
+	Delete(id string, version int) error
+	// Export returns the store contents in the bucket -> id -> JSON export format.
+	Export() (map[string]map[string]json.RawMessage, error)
+	// Import replaces the store contents with data in the export format.
+	// Revision history is cleared; missing, invalid and duplicate slugs are
+	// regenerated from the titles.
+	Import(data map[string]map[string]json.RawMessage) error
+
+	// AddRevision records a version of an item, assigning the next revision
+	// number and pruning the oldest revisions beyond the retention limit.
+	AddRevision(rev models.Revision) (models.Revision, error)
+	// Revisions lists the revisions of an item, newest first.
+	Revisions(id string) ([]models.Revision, error)
+	// Revision retrieves one revision. Returns ErrRevisionNotFound if it does not exist.
+	Revision(id string, number int) (models.Revision, error)
+}
+
+// BatchWrite is one write of a ContentStore.Batch.
+type BatchWrite struct {
+	Item   models.Content
+	Create bool // Create a new item rather than update an existing one
+}
+
+// BatchError reports the write that made a Batch fail.
+type BatchError struct {
+	Index int // Position of the write in the batch
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch write %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// Backend hands out the ContentStore serving a request scope.
+// Shared backends ignore the scope; the sandbox backend keys
+// per-visitor stores by it (typically the session ID).
+type Backend interface {
+	Store(scope string) (ContentStore, error)
+}
+
+// Resetter is implemented by backends whose scoped data can be discarded.
+type Resetter interface {
+	Reset(scope string)
+}
+
+// Enumerator is implemented by backends that can list every store they
+// serve, for background maintenance such as purging the trash.
+type Enumerator interface {
+	Stores() []ContentStore
+}
+
+// Viewer is implemented by scoped backends to serve anonymous readers the
+// shared content without creating a scope for them.
+type Viewer interface {
+	View() ContentStore
+}
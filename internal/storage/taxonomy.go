@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cms/internal/models"
+
+	"go.etcd.io/bbolt"
+)
+
+// termsPrefix names the bucket of each taxonomy, e.g. "terms_tags".
+const termsPrefix = "terms_"
+
+// Errors returned by TaxonomyStore.
+var (
+	ErrTermNotFound    = errors.New("term not found")
+	ErrTermExists      = errors.New("term already exists")
+	ErrTermHasChildren = errors.New("category has subcategories")
+	ErrInvalidParent   = errors.New("invalid parent category")
+)
+
+// TaxonomyStore persists tags and categories, keyed by slug. Terms are
+// shared by every storage mode, like user accounts; items refer to them by
+// slug and each content store indexes those relations itself.
+type TaxonomyStore struct {
+	db *bbolt.DB
+}
+
+// NewTaxonomyStore creates a taxonomy store on an open bbolt database.
+func NewTaxonomyStore(db *bbolt.DB) (*TaxonomyStore, error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		for _, taxonomy := range models.Taxonomies {
+			if _, err := tx.CreateBucketIfNotExists(termsBucket(taxonomy)); err != nil {
+				return fmt.Errorf("failed to create bucket %s: %w", termsBucket(taxonomy), err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &TaxonomyStore{db: db}, nil
+}
+
+// Terms lists the terms of a taxonomy. Tags are ordered by name;
+// categories are ordered as a tree, each parent followed by its children.
+func (s *TaxonomyStore) Terms(taxonomy string) ([]models.Term, error) {
+	var terms []models.Term
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		terms, err = loadTerms(tx, taxonomy)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s: %w", taxonomy, err)
+	}
+	if taxonomy == models.TaxonomyCategories {
+		return treeOrder(terms), nil
+	}
+	sort.SliceStable(terms, func(i, j int) bool {
+		return strings.ToLower(terms[i].Name) < strings.ToLower(terms[j].Name)
+	})
+	return terms, nil
+}
+
+// Term retrieves a term by slug.
+func (s *TaxonomyStore) Term(taxonomy, slug string) (models.Term, error) {
+	var term models.Term
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(termsBucket(taxonomy))
+		if b == nil {
+			return ErrTermNotFound
+		}
+		v := b.Get([]byte(slug))
+		if v == nil {
+			return ErrTermNotFound
+		}
+		return json.Unmarshal(v, &term)
+	})
+	return term, err
+}
+
+// Match returns up to limit tags whose name or slug contains query
+// (case-insensitive), those starting with it first.
+func (s *TaxonomyStore) Match(query string, limit int) ([]models.Term, error) {
+	tags, err := s.Terms(models.TaxonomyTags)
+	if err != nil {
+		return nil, err
+	}
+	needle := strings.ToLower(strings.TrimSpace(query))
+	var prefixed, contained []models.Term
+	for _, tag := range tags {
+		name := strings.ToLower(tag.Name)
+		switch {
+		case strings.HasPrefix(name, needle) || strings.HasPrefix(tag.Slug, needle):
+			prefixed = append(prefixed, tag)
+		case strings.Contains(name, needle) || strings.Contains(tag.Slug, needle):
+			contained = append(contained, tag)
+		}
+	}
+	matches := append(prefixed, contained...)
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// CreateTerm adds a term. Returns ErrTermExists if the slug is taken and
+// ErrInvalidParent if a category's parent does not exist.
+func (s *TaxonomyStore) CreateTerm(taxonomy string, term models.Term) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(termsBucket(taxonomy))
+		if b == nil {
+			return ErrTermNotFound
+		}
+		if b.Get([]byte(term.Slug)) != nil {
+			return ErrTermExists
+		}
+		if term.Parent != "" && b.Get([]byte(term.Parent)) == nil {
+			return ErrInvalidParent
+		}
+		return putJSON(b, term.Slug, term)
+	})
+}
+
+// UpdateTerm replaces a term's name, description and parent. Returns
+// ErrInvalidParent if the parent does not exist or lies below the term.
+func (s *TaxonomyStore) UpdateTerm(taxonomy string, term models.Term) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(termsBucket(taxonomy))
+		if b == nil || b.Get([]byte(term.Slug)) == nil {
+			return ErrTermNotFound
+		}
+		if term.Parent != "" {
+			if b.Get([]byte(term.Parent)) == nil {
+				return ErrInvalidParent
+			}
+			terms, err := loadTerms(tx, taxonomy)
+			if err != nil {
+				return err
+			}
+			for _, slug := range subtree(terms, term.Slug) {
+				if slug == term.Parent {
+					return ErrInvalidParent
+				}
+			}
+		}
+		return putJSON(b, term.Slug, term)
+	})
+}
+
+// DeleteTerm removes a term. Returns ErrTermHasChildren if a category
+// still has subcategories. Items keep referring to the term until
+// their relations are removed.
+func (s *TaxonomyStore) DeleteTerm(taxonomy, slug string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(termsBucket(taxonomy))
+		if b == nil || b.Get([]byte(slug)) == nil {
+			return ErrTermNotFound
+		}
+		terms, err := loadTerms(tx, taxonomy)
+		if err != nil {
+			return err
+		}
+		for _, t := range terms {
+			if t.Parent == slug {
+				return ErrTermHasChildren
+			}
+		}
+		return b.Delete([]byte(slug))
+	})
+}
+
+// Subtree returns the slug of a category followed by the slugs of all
+// categories below it.
+func (s *TaxonomyStore) Subtree(slug string) ([]string, error) {
+	var slugs []string
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		terms, err := loadTerms(tx, models.TaxonomyCategories)
+		if err != nil {
+			return err
+		}
+		slugs = subtree(terms, slug)
+		return nil
+	})
+	return slugs, err
+}
+
+// loadTerms reads every term of a taxonomy in slug order.
+func loadTerms(tx *bbolt.Tx, taxonomy string) ([]models.Term, error) {
+	b := tx.Bucket(termsBucket(taxonomy))
+	if b == nil {
+		return nil, ErrTermNotFound
+	}
+	var terms []models.Term
+	err := b.ForEach(func(k, v []byte) error {
+		var term models.Term
+		if err := json.Unmarshal(v, &term); err != nil {
+			return fmt.Errorf("failed to unmarshal term %s: %w", string(k), err)
+		}
+		terms = append(terms, term)
+		return nil
+	})
+	return terms, err
+}
+
+// treeOrder sorts categories depth-first, siblings by name. Categories
+// whose parent is missing are treated as top-level.
+func treeOrder(terms []models.Term) []models.Term {
+	known := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		known[t.Slug] = true
+	}
+	children := make(map[string][]models.Term)
+	for _, t := range terms {
+		parent := t.Parent
+		if !known[parent] {
+			parent = ""
+		}
+		children[parent] = append(children[parent], t)
+	}
+
+	ordered := make([]models.Term, 0, len(terms))
+	var walk func(parent string)
+	walk = func(parent string) {
+		siblings := children[parent]
+		sort.SliceStable(siblings, func(i, j int) bool {
+			return strings.ToLower(siblings[i].Name) < strings.ToLower(siblings[j].Name)
+		})
+		for _, t := range siblings {
+			ordered = append(ordered, t)
+			walk(t.Slug)
+		}
+	}
+	walk("")
+	return ordered
+}
+
+// subtree returns slug and the slugs of all terms below it.
+func subtree(terms []models.Term, slug string) []string {
+	slugs := []string{slug}
+	seen := map[string]bool{slug: true}
+	for i := 0; i < len(slugs); i++ {
+		for _, t := range terms {
+			if t.Parent == slugs[i] && !seen[t.Slug] {
+				seen[t.Slug] = true
+				slugs = append(slugs, t.Slug)
+			}
+		}
+	}
+	return slugs
+}
+
+// termsBucket names the bucket holding the terms of a taxonomy.
+func termsBucket(taxonomy string) []byte {
+	return []byte(termsPrefix + taxonomy)
+}
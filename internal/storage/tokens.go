@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cms/internal/auth"
+	"cms/internal/models"
+
+	"go.etcd.io/bbolt"
+)
+
+const tokensBucket = "api_tokens"
+
+// ErrTokenNotFound is returned when an API token does not exist.
+var ErrTokenNotFound = errors.New("token not found")
+
+// TokenStore persists API tokens in the api_tokens bucket, keyed by token hash.
+type TokenStore struct {
+	db *bbolt.DB
+}
+
+// NewTokenStore creates a token store on an open bbolt database.
+func NewTokenStore(db *bbolt.DB) (*TokenStore, error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(tokensBucket))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bucket %s: %w", tokensBucket, err)
+	}
+	return &TokenStore{db: db}, nil
+}
+
+// GetByHash retrieves a token by the hash of its value.
+func (s *TokenStore) GetByHash(hash string) (models.APIToken, error) {
+	var token models.APIToken
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		v := tx.Bucket([]byte(tokensBucket)).Get([]byte(hash))
+		if v == nil {
+			return ErrTokenNotFound
+		}
+		return json.Unmarshal(v, &token)
+	})
+	return token, err
+}
+
+// List retrieves the tokens owned by username, or all tokens if username is empty.
+func (s *TokenStore) List(username string) ([]models.APIToken, error) {
+	var tokens []models.APIToken
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(tokensBucket)).ForEach(func(k, v []byte) error {
+			var token models.APIToken
+			if err := json.Unmarshal(v, &token); err != nil {
+				return fmt.Errorf("failed to unmarshal token %s: %w", string(k), err)
+			}
+			if username == "" || strings.EqualFold(token.Username, username) {
+				tokens = append(tokens, token)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// Create stores a new token.
+func (s *TokenStore) Create(token models.APIToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token %s: %w", token.ID, err)
+	}
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(tokensBucket)).Put([]byte(token.Hash), data)
+	})
+}
+
+// Issue generates a token for username with the given scopes and stores it.
+// It returns the stored record and the token value, which is not kept.
+func (s *TokenStore) Issue(username, name string, scopes []string) (models.APIToken, string, error) {
+	raw, hash, err := auth.GenerateToken()
+	if err != nil {
+		return models.APIToken{}, "", err
+	}
+	token := models.APIToken{
+		ID:        hash[:16],
+		Name:      name,
+		Username:  username,
+		Scopes:    scopes,
+		Hash:      hash,
+		Prefix:    raw[:len(auth.TokenPrefix)+6],
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.Create(token); err != nil {
+		return models.APIToken{}, "", err
+	}
+	return token, raw, nil
+}
+
+// Touch records that the token with hash was used at t.
+func (s *TokenStore) Touch(hash string, t time.Time) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(tokensBucket))
+		v := b.Get([]byte(hash))
+		if v == nil {
+			return ErrTokenNotFound
+		}
+		var token models.APIToken
+		if err := json.Unmarshal(v, &token); err != nil {
+			return err
+		}
+		token.LastUsedAt = t
+		data, err := json.Marshal(token)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(hash), data)
+	})
+}
+
+// Delete revokes the token with id. If username is not empty, the token
+// must belong to that user.
+func (s *TokenStore) Delete(id, username string) error {
+	return s.deleteWhere(func(t models.APIToken) bool {
+		return t.ID == id && (username == "" || strings.EqualFold(t.Username, username))
+	}, true)
+}
+
+// DeleteByUser revokes all tokens owned by username.
+func (s *TokenStore) DeleteByUser(username string) error {
+	return s.deleteWhere(func(t models.APIToken) bool {
+		return strings.EqualFold(t.Username, username)
+	}, false)
+}
+
+// deleteWhere removes matching tokens; with mustMatch it returns
+// ErrTokenNotFound when nothing matched.
+func (s *TokenStore) deleteWhere(match func(models.APIToken) bool, mustMatch bool) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(tokensBucket))
+		var keys [][]byte
+		err := b.ForEach(func(k, v []byte) error {
+			var token models.APIToken
+			if err := json.Unmarshal(v, &token); err != nil {
+				return fmt.Errorf("failed to unmarshal token %s: %w", string(k), err)
+			}
+			if match(token) {
+				keys = append(keys, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if mustMatch && len(keys) == 0 {
+			return ErrTokenNotFound
+		}
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
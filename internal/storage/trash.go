@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"errors"
+	"log"
+	"time"
+)
+
+// TrashPurger permanently deletes items that have been in the trash for
+// longer than the retention period.
+type TrashPurger struct {
+	stores    Enumerator
+	retention time.Duration
+	stop      chan struct{}
+}
+
+// NewTrashPurger starts a purger for the stores of a backend. A retention
+// of 0 keeps trashed items until they are deleted by hand; no purger runs.
+func NewTrashPurger(stores Enumerator, retention time.Duration) *TrashPurger {
+	p := &TrashPurger{
+		stores:    stores,
+		retention: retention,
+		stop:      make(chan struct{}),
+	}
+	if retention > 0 {
+		go p.purgeLoop()
+	}
+	return p
+}
+
+// Close stops the background purger.
+func (p *TrashPurger) Close() {
+	if p.retention > 0 {
+		close(p.stop)
+	}
+}
+
+// purgeLoop runs a purge at start-up and then periodically.
+func (p *TrashPurger) purgeLoop() {
+	interval := p.retention / 24
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	if interval > time.Hour {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	p.purge(time.Now())
+	for {
+		select {
+		case <-ticker.C:
+			p.purge(time.Now())
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+func (p *TrashPurger) purge(now time.Time) {
+	cutoff := now.Add(-p.retention)
+	removed := 0
+	for _, store := range p.stores.Stores() {
+		n, err := purgeTrash(store, cutoff)
+		if err != nil {
+			log.Printf("TrashPurger: Error purging trash: %v", err)
+		}
+		removed += n
+	}
+	if removed > 0 {
+		log.Printf("TrashPurger: Permanently deleted %d trashed items", removed)
+	}
+}
+
+// purgeTrash permanently deletes the items of store trashed before cutoff
+// and returns how many were removed. Items restored since the listing are
+// at a newer version and kept.
+func purgeTrash(store ContentStore, cutoff time.Time) (int, error) {
+	items, err := store.List()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, item := range items {
+		if !item.Trashed() || item.DeletedAt.After(cutoff) {
+			continue
+		}
+		err := store.Delete(item.ID, item.Version)
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrVersionConflict) {
+			continue
+		}
+		if err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
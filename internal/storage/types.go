@@ -0,0 +1,244 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"cms/internal/models"
+	"cms/internal/schema"
+
+	"go.etcd.io/bbolt"
+)
+
+const (
+	typesBucket     = "types"      // type name -> schema.Type JSON
+	typeItemsBucket = "type_items" // type name -> nested bucket of id -> models.Item JSON
+)
+
+// Errors returned by TypeStore.
+var (
+	ErrTypeNotFound = errors.New("content type not found")
+	ErrTypeExists   = errors.New("content type already exists")
+	ErrTypeInUse    = errors.New("content type still has items")
+	ErrItemNotFound = errors.New("item not found")
+)
+
+// TypeStore persists custom content types and their items. Types are
+// shared by every storage mode, like user accounts.
+type TypeStore struct {
+	db *bbolt.DB
+}
+
+// NewTypeStore creates a type store on an open bbolt database.
+func NewTypeStore(db *bbolt.DB) (*TypeStore, error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range []string{typesBucket, typeItemsBucket} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return fmt.Errorf("failed to create bucket %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &TypeStore{db: db}, nil
+}
+
+// Types lists all content types ordered by name.
+func (s *TypeStore) Types() ([]schema.Type, error) {
+	var types []schema.Type
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(typesBucket)).ForEach(func(k, v []byte) error {
+			var t schema.Type
+			if err := json.Unmarshal(v, &t); err != nil {
+				return fmt.Errorf("failed to unmarshal type %s: %w", string(k), err)
+			}
+			types = append(types, t)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing types: %w", err)
+	}
+	return types, nil
+}
+
+// Type retrieves a content type by name.
+func (s *TypeStore) Type(name string) (schema.Type, error) {
+	var t schema.Type
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		v := tx.Bucket([]byte(typesBucket)).Get([]byte(name))
+		if v == nil {
+			return ErrTypeNotFound
+		}
+		return json.Unmarshal(v, &t)
+	})
+	return t, err
+}
+
+// CreateType adds a new content type. Returns ErrTypeExists if the name is taken.
+func (s *TypeStore) CreateType(t schema.Type) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(typesBucket))
+		if b.Get([]byte(t.Name)) != nil {
+			return ErrTypeExists
+		}
+		if _, err := tx.Bucket([]byte(typeItemsBucket)).CreateBucketIfNotExists([]byte(t.Name)); err != nil {
+			return fmt.Errorf("failed to create item bucket for type %s: %w", t.Name, err)
+		}
+		return putJSON(b, t.Name, t)
+	})
+}
+
+// UpdateType replaces a content type's definition. Existing items keep
+// their values until they are next saved.
+func (s *TypeStore) UpdateType(t schema.Type) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(typesBucket))
+		if b.Get([]byte(t.Name)) == nil {
+			return ErrTypeNotFound
+		}
+		return putJSON(b, t.Name, t)
+	})
+}
+
+// DeleteType removes a content type. Returns ErrTypeInUse if it still has items.
+func (s *TypeStore) DeleteType(name string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(typesBucket))
+		if b.Get([]byte(name)) == nil {
+			return ErrTypeNotFound
+		}
+		items := tx.Bucket([]byte(typeItemsBucket))
+		if ib := items.Bucket([]byte(name)); ib != nil {
+			if ib.Stats().KeyN > 0 {
+				return ErrTypeInUse
+			}
+			if err := items.DeleteBucket([]byte(name)); err != nil {
+				return fmt.Errorf("failed to delete item bucket for type %s: %w", name, err)
+			}
+		}
+		return b.Delete([]byte(name))
+	})
+}
+
+// Counts returns the number of items of each type.
+func (s *TypeStore) Counts() (map[string]int, error) {
+	counts := make(map[string]int)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(typeItemsBucket)).ForEachBucket(func(k []byte) error {
+			counts[string(k)] = tx.Bucket([]byte(typeItemsBucket)).Bucket(k).Stats().KeyN
+			return nil
+		})
+	})
+	return counts, err
+}
+
+// Items lists the items of a type, most recently updated first.
+func (s *TypeStore) Items(typeName string) ([]models.Item, error) {
+	var items []models.Item
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		b, err := itemBucket(tx, typeName)
+		if err != nil {
+			return err
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var item models.Item
+			if err := json.Unmarshal(v, &item); err != nil {
+				return fmt.Errorf("failed to unmarshal item %s/%s: %w", typeName, string(k), err)
+			}
+			items = append(items, item)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].UpdatedAt.After(items[j].UpdatedAt)
+	})
+	return items, nil
+}
+
+// Item retrieves an item of a type by ID.
+func (s *TypeStore) Item(typeName, id string) (models.Item, error) {
+	var item models.Item
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		b, err := itemBucket(tx, typeName)
+		if err != nil {
+			return err
+		}
+		v := b.Get([]byte(id))
+		if v == nil {
+			return ErrItemNotFound
+		}
+		return json.Unmarshal(v, &item)
+	})
+	return item, err
+}
+
+// CreateItem adds a new item. Returns ErrExists if the ID is already taken.
+func (s *TypeStore) CreateItem(item models.Item) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b, err := itemBucket(tx, item.Type)
+		if err != nil {
+			return err
+		}
+		if b.Get([]byte(item.ID)) != nil {
+			return ErrExists
+		}
+		return putJSON(b, item.ID, item)
+	})
+}
+
+// UpdateItem replaces an existing item.
+func (s *TypeStore) UpdateItem(item models.Item) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b, err := itemBucket(tx, item.Type)
+		if err != nil {
+			return err
+		}
+		if b.Get([]byte(item.ID)) == nil {
+			return ErrItemNotFound
+		}
+		return putJSON(b, item.ID, item)
+	})
+}
+
+// DeleteItem removes an item.
+func (s *TypeStore) DeleteItem(typeName, id string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b, err := itemBucket(tx, typeName)
+		if err != nil {
+			return err
+		}
+		if b.Get([]byte(id)) == nil {
+			return ErrItemNotFound
+		}
+		return b.Delete([]byte(id))
+	})
+}
+
+// itemBucket returns the bucket holding the items of a type, or
+// ErrTypeNotFound if the type does not exist.
+func itemBucket(tx *bbolt.Tx, typeName string) (*bbolt.Bucket, error) {
+	if tx.Bucket([]byte(typesBucket)).Get([]byte(typeName)) == nil {
+		return nil, ErrTypeNotFound
+	}
+	b := tx.Bucket([]byte(typeItemsBucket)).Bucket([]byte(typeName))
+	if b == nil {
+		return nil, ErrTypeNotFound
+	}
+	return b, nil
+}
+
+func putJSON(b *bbolt.Bucket, key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", key, err)
+	}
+	return b.Put([]byte(key), data)
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"cms/internal/models"
+
+	"go.etcd.io/bbolt"
+)
+
+const usersBucket = "users"
+
+// Errors returned by UserStore.
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrUserExists   = errors.New("user already exists")
+)
+
+// UserStore persists user accounts in the users bucket, keyed by
+// lower-cased username.
+type UserStore struct {
+	db *bbolt.DB
+}
+
+// NewUserStore creates a user store on an open bbolt database.
+func NewUserStore(db *bbolt.DB) (*UserStore, error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(usersBucket))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bucket %s: %w", usersBucket, err)
+	}
+	return &UserStore{db: db}, nil
+}
+
+func userKey(username string) []byte {
+	return []byte(strings.ToLower(username))
+}
+
+// Get retrieves a user by username (case-insensitive).
+func (s *UserStore) Get(username string) (models.User, error) {
+	var user models.User
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		v := tx.Bucket([]byte(usersBucket)).Get(userKey(username))
+		if v == nil {
+			return ErrUserNotFound
+		}
+		return json.Unmarshal(v, &user)
+	})
+	return user, err
+}
+
+// List retrieves all users ordered by username.
+func (s *UserStore) List() ([]models.User, error) {
+	var users []models.User
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(usersBucket)).ForEach(func(k, v []byte) error {
+			var user models.User
+			if err := json.Unmarshal(v, &user); err != nil {
+				return fmt.Errorf("failed to unmarshal user %s: %w", string(k), err)
+			}
+			users = append(users, user)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing users: %w", err)
+	}
+	return users, nil
+}
+
+// Count returns the number of users.
+func (s *UserStore) Count() (int, error) {
+	count := 0
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		count = tx.Bucket([]byte(usersBucket)).Stats().KeyN
+		return nil
+	})
+	return count, err
+}
+
+// Create adds a new user.
+func (s *UserStore) Create(user models.User) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(usersBucket))
+		if b.Get(userKey(user.Username)) != nil {
+			return ErrUserExists
+		}
+		return putUser(b, user)
+	})
+}
+
+// Update replaces an existing user.
+func (s *UserStore) Update(user models.User) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(usersBucket))
+		if b.Get(userKey(user.Username)) == nil {
+			return ErrUserNotFound
+		}
+		return putUser(b, user)
+	})
+}
+
+// Delete removes a user by username.
+func (s *UserStore) Delete(username string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(usersBucket))
+		if b.Get(userKey(username)) == nil {
+			return ErrUserNotFound
+		}
+		return b.Delete(userKey(username))
+	})
+}
+
+func putUser(b *bbolt.Bucket, user models.User) error {
+	data, err := json.Marshal(user)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user %s: %w", user.Username, err)
+	}
+	return b.Put(userKey(user.Username), data)
+}
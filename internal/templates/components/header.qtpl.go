@@ -21,12 +21,16 @@ var (
 // Define the interface expected by the Header component
 type HeaderData interface {
 	IsAuthenticated() bool
+	IsSandbox() bool
+	CurrentUsername() string
+	CanManageUsers() bool
+	CSRFToken() string
 	// Add other needed methods from PageData if necessary
 }
 
-//line internal/templates/components/header.qtpl:9
+//line internal/templates/components/header.qtpl:13
 func StreamHeader(qw422016 *qt422016.Writer, data HeaderData) {
-//line internal/templates/components/header.qtpl:9
+//line internal/templates/components/header.qtpl:13
 	qw422016.N().S(`
 <script defer src="https://cdn.jsdelivr.net/npm/alpinejs@3.x.x/dist/cdn.min.js"></script>
 
@@ -46,7 +50,21 @@ func StreamHeader(qw422016 *qt422016.Writer, data HeaderData) {
         </button>
       </div>
       <div class="hidden lg:flex lg:items-center lg:gap-x-8">
+        `)
+//line internal/templates/components/header.qtpl:32
+	if data.IsAuthenticated() {
+//line internal/templates/components/header.qtpl:32
+		qw422016.N().S(`
+        <form action="/search" method="GET" role="search">
+          <input type="search" name="q" placeholder="Search content" aria-label="Search content" class="w-48 rounded-md border border-gray-300 dark:border-gray-600 bg-white dark:bg-gray-700 px-3 py-1.5 text-sm text-gray-900 dark:text-gray-100 focus:ring-indigo-500 focus:border-indigo-500">
+        </form>
+        `)
+//line internal/templates/components/header.qtpl:36
+	}
+//line internal/templates/components/header.qtpl:36
+	qw422016.N().S(`
         <a href="/content" class="text-sm font-semibold leading-6 text-gray-900 dark:text-gray-100 hover:text-indigo-600 dark:hover:text-indigo-400">Content</a>
+        <a href="/media" class="text-sm font-semibold leading-6 text-gray-900 dark:text-gray-100 hover:text-indigo-600 dark:hover:text-indigo-400">Media</a>
         <a href="/settings" class="text-sm font-semibold leading-6 text-gray-900 dark:text-gray-100 hover:text-indigo-600 dark:hover:text-indigo-400">Settings</a>
         <a href="#" onclick="document.getElementById('importExportModal').showModal(); return false;" class="text-sm font-semibold leading-6 text-gray-900 dark:text-gray-100 hover:text-indigo-600 dark:hover:text-indigo-400">Import/Export</a>
         <button @click="theme = (theme === 'dark' ? 'light' : 'dark')" class="p-2 rounded-md text-gray-600 dark:text-gray-400 hover:bg-gray-100 dark:hover:bg-gray-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-indigo-500 dark:focus:ring-offset-gray-800">
@@ -54,17 +72,31 @@ func StreamHeader(qw422016 *qt422016.Writer, data HeaderData) {
           <svg x-show="theme === 'dark'" xmlns="http://www.w3.org/2000/svg" class="h-5 w-5" viewBox="0 0 20 20" fill="currentColor"><path fill-rule="evenodd" d="M10 2a1 1 0 011 1v1a1 1 0 11-2 0V3a1 1 0 011-1zm4 8a4 4 0 11-8 0 4 4 0 018 0zm-.464 4.95l.707.707a1 1 0 001.414-1.414l-.707-.707a1 1 0 00-1.414 1.414zm2.12-10.607a1 1 0 010 1.414l-.706.707a1 1 0 11-1.414-1.414l.707-.707a1 1 0 011.414 0zM17 11a1 1 0 100-2h-1a1 1 0 100 2h1zm-7 4a1 1 0 011 1v1a1 1 0 11-2 0v-1a1 1 0 011-1zM5.05 6.464A1 1 0 106.465 5.05l-.708-.707a1 1 0 00-1.414 1.414l.707.707zm-.707 7.072l.707-.707a1 1 0 10-1.414-1.414l-.707.707a1 1 0 001.414 1.414zM3 11a1 1 0 100-2H2a1 1 0 100 2h1z" clip-rule="evenodd" /></svg>
           <svg x-show="theme === 'light'" xmlns="http://www.w3.org/2000/svg" class="h-5 w-5" viewBox="0 0 20 20" fill="currentColor"><path d="M17.293 13.293A8 8 0 016.707 2.707a8.001 8.001 0 1010.586 10.586z" /></svg>
         </button>
+        `)
+//line internal/templates/components/header.qtpl:46
+	if data.CanManageUsers() {
+//line internal/templates/components/header.qtpl:46
+		qw422016.N().S(`
         <a href="/admin" class="text-sm font-semibold leading-6 text-gray-500 dark:text-gray-400 hover:text-indigo-600 dark:hover:text-indigo-400">(Admin)</a>
         `)
-//line internal/templates/components/header.qtpl:37
+//line internal/templates/components/header.qtpl:48
+	}
+//line internal/templates/components/header.qtpl:48
+	qw422016.N().S(`
+        `)
+//line internal/templates/components/header.qtpl:49
 	if data.IsAuthenticated() {
-//line internal/templates/components/header.qtpl:37
+//line internal/templates/components/header.qtpl:49
 		qw422016.N().S(`
-        <a href="/logout" class="text-sm font-semibold leading-6 text-gray-900 dark:text-gray-100 hover:text-red-600 dark:hover:text-red-400">Logout</a>
+        <a href="/logout" class="text-sm font-semibold leading-6 text-gray-900 dark:text-gray-100 hover:text-red-600 dark:hover:text-red-400" title="Signed in as `)
+//line internal/templates/components/header.qtpl:50
+		qw422016.E().S(data.CurrentUsername())
+//line internal/templates/components/header.qtpl:50
+		qw422016.N().S(`">Logout</a>
         `)
-//line internal/templates/components/header.qtpl:39
+//line internal/templates/components/header.qtpl:51
 	}
-//line internal/templates/components/header.qtpl:39
+//line internal/templates/components/header.qtpl:51
 	qw422016.N().S(`
       </div>
     </nav>
@@ -96,23 +128,47 @@ func StreamHeader(qw422016 *qt422016.Writer, data HeaderData) {
         <div class="mt-6 flow-root">
           <div class="-my-6 divide-y divide-gray-500/10 dark:divide-gray-700">
             <div class="space-y-2 py-6">
+              `)
+//line internal/templates/components/header.qtpl:82
+	if data.IsAuthenticated() {
+//line internal/templates/components/header.qtpl:82
+		qw422016.N().S(`
+              <form action="/search" method="GET" role="search" class="-mx-3 px-3 pb-2">
+                <input type="search" name="q" placeholder="Search content" aria-label="Search content" class="w-full rounded-md border border-gray-300 dark:border-gray-600 bg-white dark:bg-gray-700 px-3 py-2 text-base text-gray-900 dark:text-gray-100">
+              </form>
+              `)
+//line internal/templates/components/header.qtpl:86
+	}
+//line internal/templates/components/header.qtpl:86
+	qw422016.N().S(`
               <a href="/content" class="-mx-3 block rounded-lg px-3 py-2 text-base font-semibold leading-7 text-gray-900 dark:text-gray-100 hover:bg-gray-50 dark:hover:bg-gray-700" @click="$dispatch('close-mobile-menu')">Content</a>
+              <a href="/media" class="-mx-3 block rounded-lg px-3 py-2 text-base font-semibold leading-7 text-gray-900 dark:text-gray-100 hover:bg-gray-50 dark:hover:bg-gray-700" @click="$dispatch('close-mobile-menu')">Media</a>
               <a href="/settings" class="-mx-3 block rounded-lg px-3 py-2 text-base font-semibold leading-7 text-gray-900 dark:text-gray-100 hover:bg-gray-50 dark:hover:bg-gray-700" @click="$dispatch('close-mobile-menu')">Settings</a>
               <a href="#" onclick="document.getElementById('importExportModal').showModal(); $dispatch('close-mobile-menu'); return false;" class="-mx-3 block rounded-lg px-3 py-2 text-base font-semibold leading-7 text-gray-900 dark:text-gray-100 hover:bg-gray-50 dark:hover:bg-gray-700">Import/Export</a>
+              `)
+//line internal/templates/components/header.qtpl:91
+	if data.CanManageUsers() {
+//line internal/templates/components/header.qtpl:91
+		qw422016.N().S(`
               <a href="/admin" class="-mx-3 block rounded-lg px-3 py-2 text-base font-semibold leading-7 text-gray-500 dark:text-gray-400 hover:bg-gray-50 dark:hover:bg-gray-700" @click="$dispatch('close-mobile-menu')">(Admin)</a>
+              `)
+//line internal/templates/components/header.qtpl:93
+	}
+//line internal/templates/components/header.qtpl:93
+	qw422016.N().S(`
             </div>
             `)
-//line internal/templates/components/header.qtpl:75
+//line internal/templates/components/header.qtpl:95
 	if data.IsAuthenticated() {
-//line internal/templates/components/header.qtpl:75
+//line internal/templates/components/header.qtpl:95
 		qw422016.N().S(`
             <div class="py-6">
               <a href="/logout" class="-mx-3 block rounded-lg px-3 py-2.5 text-base font-semibold leading-7 text-gray-900 dark:text-gray-100 hover:bg-red-50 dark:hover:bg-red-700 hover:text-red-600 dark:hover:text-red-300" @click="$dispatch('close-mobile-menu')">Logout</a>
             </div>
             `)
-//line internal/templates/components/header.qtpl:79
+//line internal/templates/components/header.qtpl:99
 	}
-//line internal/templates/components/header.qtpl:79
+//line internal/templates/components/header.qtpl:99
 	qw422016.N().S(`
           </div>
         </div>
@@ -124,10 +180,20 @@ func StreamHeader(qw422016 *qt422016.Writer, data HeaderData) {
     <h3 class="font-bold text-lg mb-6">Import / Export Database</h3>
     <div class="space-y-6">
         <form action="/api/export" method="POST">
+            <input type="hidden" name="csrf_token" value="`)
+//line internal/templates/components/header.qtpl:110
+	qw422016.E().S(data.CSRFToken())
+//line internal/templates/components/header.qtpl:110
+	qw422016.N().S(`">
             <button type="submit" class="w-full inline-flex justify-center rounded-md border border-transparent shadow-sm px-4 py-2 bg-blue-600 text-base font-medium text-white hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500 dark:focus:ring-offset-gray-800">Export JSON</button>
         </form>
         
         <form action="/api/import" method="POST" enctype="multipart/form-data" x-data="{ fileName: '' }" class="space-y-4">
+            <input type="hidden" name="csrf_token" value="`)
+//line internal/templates/components/header.qtpl:115
+	qw422016.E().S(data.CSRFToken())
+//line internal/templates/components/header.qtpl:115
+	qw422016.N().S(`">
             <div>
               <label class="block text-sm font-medium mb-1">Import JSON File: <a class="text-indigo-600 dark:text-indigo-400 hover:text-indigo-700 dark:hover:text-indigo-300" href="https://raw.githubusercontent.com/fastygo/crud/refs/heads/main/crud_export.json">example.json</a></label>
               <input type="file" name="importFile" @change="fileName = $event.target.files[0] ? $event.target.files[0].name : ''" accept=".json" class="block w-full text-sm text-gray-500 dark:text-gray-300 file:mr-4 file:py-2 file:px-4 file:rounded-md file:border-0 file:text-sm file:font-semibold file:bg-indigo-50 dark:file:bg-indigo-900 file:text-indigo-700 dark:file:text-indigo-300 hover:file:bg-indigo-100 dark:hover:file:bg-indigo-800 cursor-pointer" required>
@@ -135,37 +201,56 @@ func StreamHeader(qw422016 *qt422016.Writer, data HeaderData) {
             </div>
             <button type="submit" :disabled="!fileName" class="w-full inline-flex justify-center rounded-md border border-transparent shadow-sm px-4 py-2 bg-green-600 text-base font-medium text-white hover:bg-green-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-green-500 disabled:opacity-50 disabled:cursor-not-allowed dark:focus:ring-offset-gray-800">Import</button>
         </form>
+        `)
+//line internal/templates/components/header.qtpl:123
+	if data.IsSandbox() {
+//line internal/templates/components/header.qtpl:123
+		qw422016.N().S(`
+        <form action="/api/sandbox/reset" method="POST" onsubmit="return confirm('Discard your changes and restore the demo content?');">
+            <input type="hidden" name="csrf_token" value="`)
+//line internal/templates/components/header.qtpl:125
+		qw422016.E().S(data.CSRFToken())
+//line internal/templates/components/header.qtpl:125
+		qw422016.N().S(`">
+            <p class="text-sm text-gray-500 dark:text-gray-400 mb-2">This is a sandbox: your changes are private to your session and expire automatically.</p>
+            <button type="submit" class="w-full inline-flex justify-center rounded-md border border-transparent shadow-sm px-4 py-2 bg-red-600 text-base font-medium text-white hover:bg-red-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-red-500 dark:focus:ring-offset-gray-800">Reset Sandbox</button>
+        </form>
+        `)
+//line internal/templates/components/header.qtpl:129
+	}
+//line internal/templates/components/header.qtpl:129
+	qw422016.N().S(`
     </div>
     <div class="mt-6 text-right">
         <button class="px-4 py-2 bg-gray-200 dark:bg-gray-600 text-gray-800 dark:text-gray-200 rounded-md hover:bg-gray-300 dark:hover:bg-gray-500" onclick="document.getElementById('importExportModal').close();">Close</button>
     </div>
 </dialog>
 `)
-//line internal/templates/components/header.qtpl:106
+//line internal/templates/components/header.qtpl:135
 }
 
-//line internal/templates/components/header.qtpl:106
+//line internal/templates/components/header.qtpl:135
 func WriteHeader(qq422016 qtio422016.Writer, data HeaderData) {
-//line internal/templates/components/header.qtpl:106
+//line internal/templates/components/header.qtpl:135
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line internal/templates/components/header.qtpl:106
+//line internal/templates/components/header.qtpl:135
 	StreamHeader(qw422016, data)
-//line internal/templates/components/header.qtpl:106
+//line internal/templates/components/header.qtpl:135
 	qt422016.ReleaseWriter(qw422016)
-//line internal/templates/components/header.qtpl:106
+//line internal/templates/components/header.qtpl:135
 }
 
-//line internal/templates/components/header.qtpl:106
+//line internal/templates/components/header.qtpl:135
 func Header(data HeaderData) string {
-//line internal/templates/components/header.qtpl:106
+//line internal/templates/components/header.qtpl:135
 	qb422016 := qt422016.AcquireByteBuffer()
-//line internal/templates/components/header.qtpl:106
+//line internal/templates/components/header.qtpl:135
 	WriteHeader(qb422016, data)
-//line internal/templates/components/header.qtpl:106
+//line internal/templates/components/header.qtpl:135
 	qs422016 := string(qb422016.B)
-//line internal/templates/components/header.qtpl:106
+//line internal/templates/components/header.qtpl:135
 	qt422016.ReleaseByteBuffer(qb422016)
-//line internal/templates/components/header.qtpl:106
+//line internal/templates/components/header.qtpl:135
 	return qs422016
-//line internal/templates/components/header.qtpl:106
+//line internal/templates/components/header.qtpl:135
 }
@@ -57,6 +57,22 @@ func StreamBaseLayout(qw422016 *qt422016.Writer, data PageData, pageContent func
     <link rel="icon" type="image/svg+xml" href="data:image/svg+xml;base64,PHN2ZyB4bWxucz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciIHZpZXdCb3g9IjAgMCA0NyA0MCIgZmlsbD0iIzBlYTVlOSI+DQogICAgPHBhdGggZD0iTTIzLjUgNi41QzE3LjUgNi41IDEzLjc1IDkuNSAxMi4yNSAxNS41QzE0LjUgMTIuNSAxNy4xMjUgMTEuMzc1IDIwLjEyNSAxMi4xMjVDMjEuODM2NyAxMi41NTI5IDIzLjA2MDEgMTMuNzk0NyAyNC40MTQyIDE1LjE2OTJDMjYuNjIwMiAxNy40MDg0IDI5LjE3MzQgMjAgMzQuNzUgMjBDNDAuNzUgMjAgNDQuNSAxNyA0NiAxMUM0My43NSAxNCA0MS4xMjUgMTUuMTI1IDM4LjEyNSAxNC4zNzVDMzYuNDEzMyAxMy45NDcxIDM1LjE4OTkgMTIuNzA1MyAzMy44MzU3IDExLjMzMDhDMzEuNjI5NyA5LjA5MTU4IDI5LjA3NjYgNi41IDIzLjUgNi41Wk0xMi4yNSAyMEM2LjI1IDIwIDIuNSAyMyAxIDI5QzMuMjUgMjYgNS44NzUgMjQuODc1IDguODc1IDI1LjYyNUMxMC41ODY3IDI2LjA1MjkgMTEuODEwMSAyNy4yOTQ3IDEzLjE2NDIgMjguNjY5M0MxNS4zNzAyIDMwLjkwODQgMTcuOTIzNCAzMy41IDIzLjUgMzMuNUMyOS41IDMzLjUgMzMuMjUgMzAuNSAzNC43NSAyNC41QzMyLjUgMjcuNSAyOS44NzUgMjguNjI1IDI2Ljg3NSAyNy44NzVDMjUuMTYzMyAyNy40NDcxIDIzLjkzOTkgMjYuMjA1MyAyMi41ODU4IDI0LjgzMDdDMjAuMzc5OCAyMi41OTE2IDE3LjgyNjYgMjAgMTIuMjUgMjBaIj48L3BhdGg+DQo8L3N2Zz4=" />
     
     <script src="https://cdn.jsdelivr.net/npm/@tailwindcss/browser@4"></script>
+    <script>
+        // problemMessage turns API problem details (RFC 7807) into one line,
+        // listing the fields at fault.
+        function problemMessage(problem) {
+            const message = problem.detail || problem.title || 'Request failed';
+            const fields = (problem.errors || []).map(e => e.field + ': ' + e.detail);
+            return fields.length ? message + ' (' + fields.join('; ') + ')' : message;
+        }
+
+        // responseError reads the error message of a failed fetch response.
+        async function responseError(response) {
+            const type = response.headers.get('Content-Type') || '';
+            if (!type.includes('json')) return await response.text();
+            return problemMessage(await response.json());
+        }
+    </script>
     <style>
         /* Basic style to prevent flash of unstyled content with Alpine.js */
         [x-cloak] { display: none !important; }
@@ -69,54 +85,54 @@ func StreamBaseLayout(qw422016 *qt422016.Writer, data PageData, pageContent func
 <body class="bg-gray-50 dark:bg-gray-900 text-gray-900 dark:text-gray-100 flex flex-col min-h-screen antialiased">
     <!-- Render the header component, passing the page data -->
     `)
-//line internal/templates/layouts/base.qtpl:38
+//line internal/templates/layouts/base.qtpl:54
 	qw422016.N().S(components.Header(data))
-//line internal/templates/layouts/base.qtpl:38
+//line internal/templates/layouts/base.qtpl:54
 	qw422016.N().S(`
 
     <main class="container mx-auto px-4 sm:px-6 lg:px-8 py-8 mt-16 flex-grow">
         <!-- Render the page-specific content passed as a function -->
         `)
-//line internal/templates/layouts/base.qtpl:42
+//line internal/templates/layouts/base.qtpl:58
 	qw422016.N().S(pageContent())
-//line internal/templates/layouts/base.qtpl:42
+//line internal/templates/layouts/base.qtpl:58
 	qw422016.N().S(`
     </main>
 
     <!-- Render the footer component -->
     `)
-//line internal/templates/layouts/base.qtpl:46
+//line internal/templates/layouts/base.qtpl:62
 	qw422016.N().S(components.Footer())
-//line internal/templates/layouts/base.qtpl:46
+//line internal/templates/layouts/base.qtpl:62
 	qw422016.N().S(`
 </body>
 </html>
 `)
-//line internal/templates/layouts/base.qtpl:49
+//line internal/templates/layouts/base.qtpl:65
 }
 
-//line internal/templates/layouts/base.qtpl:49
+//line internal/templates/layouts/base.qtpl:65
 func WriteBaseLayout(qq422016 qtio422016.Writer, data PageData, pageContent func() string) {
-//line internal/templates/layouts/base.qtpl:49
+//line internal/templates/layouts/base.qtpl:65
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line internal/templates/layouts/base.qtpl:49
+//line internal/templates/layouts/base.qtpl:65
 	StreamBaseLayout(qw422016, data, pageContent)
-//line internal/templates/layouts/base.qtpl:49
+//line internal/templates/layouts/base.qtpl:65
 	qt422016.ReleaseWriter(qw422016)
-//line internal/templates/layouts/base.qtpl:49
+//line internal/templates/layouts/base.qtpl:65
 }
 
-//line internal/templates/layouts/base.qtpl:49
+//line internal/templates/layouts/base.qtpl:65
 func BaseLayout(data PageData, pageContent func() string) string {
-//line internal/templates/layouts/base.qtpl:49
+//line internal/templates/layouts/base.qtpl:65
 	qb422016 := qt422016.AcquireByteBuffer()
-//line internal/templates/layouts/base.qtpl:49
+//line internal/templates/layouts/base.qtpl:65
 	WriteBaseLayout(qb422016, data, pageContent)
-//line internal/templates/layouts/base.qtpl:49
+//line internal/templates/layouts/base.qtpl:65
 	qs422016 := string(qb422016.B)
-//line internal/templates/layouts/base.qtpl:49
+//line internal/templates/layouts/base.qtpl:65
 	qt422016.ReleaseByteBuffer(qb422016)
-//line internal/templates/layouts/base.qtpl:49
+//line internal/templates/layouts/base.qtpl:65
 	return qs422016
-//line internal/templates/layouts/base.qtpl:49
+//line internal/templates/layouts/base.qtpl:65
 }
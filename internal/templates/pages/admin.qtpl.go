@@ -11,72 +11,129 @@ import "cms/internal/models"
 import "cms/internal/templates/layouts"
 
 //line internal/templates/pages/admin.qtpl:3
+import "html"
+
+//line internal/templates/pages/admin.qtpl:4
 import "strings"
 
-//line internal/templates/pages/admin.qtpl:5
+//line internal/templates/pages/admin.qtpl:6
 import (
 	qtio422016 "io"
 
 	qt422016 "github.com/valyala/quicktemplate"
 )
 
-//line internal/templates/pages/admin.qtpl:5
+//line internal/templates/pages/admin.qtpl:6
 var (
 	_ = qtio422016.Copy
 	_ = qt422016.AcquireByteBuffer
 )
 
-//line internal/templates/pages/admin.qtpl:6
-type AdminData = models.BasePageData
+//line internal/templates/pages/admin.qtpl:7
+// UsersData struct is defined in models package
+type AdminData = models.UsersData
 
-//line internal/templates/pages/admin.qtpl:9
+//line internal/templates/pages/admin.qtpl:11
 func StreamAdminPage(qw422016 *qt422016.Writer, data *AdminData) {
-//line internal/templates/pages/admin.qtpl:9
+//line internal/templates/pages/admin.qtpl:11
 	qw422016.N().S(`
     `)
-//line internal/templates/pages/admin.qtpl:11
+//line internal/templates/pages/admin.qtpl:13
 	pageContent := func() string {
 		var sb strings.Builder
-		sb.WriteString(`<div class="text-center py-16 sm:py-24">
-                    <h1 class="text-4xl font-bold text-gray-700 dark:text-gray-200 mb-4">Admin Panel</h1>
-                    <p class="text-lg text-gray-500 dark:text-gray-400">This page is under construction.</p>
+		sb.WriteString(`<div class="px-4 sm:px-6 lg:px-8">
+                <div class="sm:flex sm:items-center">
+                    <div class="sm:flex-auto">
+                        <h1 class="text-2xl font-semibold leading-6 text-gray-900 dark:text-white">Users</h1>
+                        <p class="mt-2 text-sm text-gray-700 dark:text-gray-300">Accounts that can sign in to the CMS and their roles.</p>
+                    </div>
+                    <div class="mt-4 sm:ml-16 sm:mt-0 sm:flex-none">
+                        <a href="/admin/users/new" class="block rounded-md bg-indigo-600 px-4 py-2 text-center text-sm font-semibold text-white shadow-sm hover:bg-indigo-500 dark:bg-indigo-500 dark:hover:bg-indigo-400">Add User</a>
+                    </div>
                 </div>`)
+
+		if data.Message != "" {
+			sb.WriteString(`<p class="mt-4 rounded-md bg-green-50 p-3 text-sm text-green-800 dark:bg-green-800/30 dark:text-green-300">`)
+			sb.WriteString(html.EscapeString(data.Message))
+			sb.WriteString(`</p>`)
+		}
+
+		sb.WriteString(`
+                <div class="mt-8 overflow-hidden shadow ring-1 ring-black ring-opacity-5 dark:ring-white dark:ring-opacity-10 sm:rounded-lg">
+                    <table class="min-w-full divide-y divide-gray-300 dark:divide-gray-700">
+                        <thead class="bg-gray-50 dark:bg-gray-700">
+                            <tr>
+                                <th scope="col" class="py-3.5 pl-4 pr-3 text-left text-sm font-semibold text-gray-900 dark:text-gray-100 sm:pl-6">Username</th>
+                                <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Role</th>
+                                <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Created At</th>
+                                <th scope="col" class="relative py-3.5 pl-3 pr-4 sm:pr-6"><span class="sr-only">Edit</span></th>
+                            </tr>
+                        </thead>
+                        <tbody class="divide-y divide-gray-200 dark:divide-gray-700 bg-white dark:bg-gray-800">`)
+
+		for _, user := range data.Users {
+			name := html.EscapeString(user.Username)
+			sb.WriteString(`<tr class="hover:bg-gray-50 dark:hover:bg-gray-700">
+                    <td class="whitespace-nowrap py-4 pl-4 pr-3 text-sm font-medium text-gray-900 dark:text-gray-100 sm:pl-6">`)
+			sb.WriteString(name)
+			sb.WriteString(`</td>
+                    <td class="whitespace-nowrap px-3 py-4 text-sm text-gray-500 dark:text-gray-400">`)
+			sb.WriteString(html.EscapeString(user.Role))
+			sb.WriteString(`</td>
+                    <td class="whitespace-nowrap px-3 py-4 text-sm text-gray-500 dark:text-gray-400">`)
+			sb.WriteString(user.CreatedAt.Format("2006-01-02 15:04"))
+			sb.WriteString(`</td>
+                    <td class="relative whitespace-nowrap py-4 pl-3 pr-4 text-right text-sm font-medium sm:pr-6">
+                        <a href="/admin/users/`)
+			sb.WriteString(html.EscapeString(strings.ToLower(user.Username)))
+			sb.WriteString(`/edit" class="text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">Edit<span class="sr-only">, `)
+			sb.WriteString(name)
+			sb.WriteString(`</span></a>
+                    </td>
+                </tr>`)
+		}
+
+		sb.WriteString(`
+                        </tbody>
+                    </table>
+                </div>
+            </div>`)
 		return sb.String()
 	}
 
-//line internal/templates/pages/admin.qtpl:19
+//line internal/templates/pages/admin.qtpl:74
 	qw422016.N().S(`
     `)
-//line internal/templates/pages/admin.qtpl:20
+//line internal/templates/pages/admin.qtpl:75
 	qw422016.N().S(layouts.BaseLayout(data, pageContent))
-//line internal/templates/pages/admin.qtpl:20
+//line internal/templates/pages/admin.qtpl:75
 	qw422016.N().S(`
 `)
-//line internal/templates/pages/admin.qtpl:21
+//line internal/templates/pages/admin.qtpl:76
 }
 
-//line internal/templates/pages/admin.qtpl:21
+//line internal/templates/pages/admin.qtpl:76
 func WriteAdminPage(qq422016 qtio422016.Writer, data *AdminData) {
-//line internal/templates/pages/admin.qtpl:21
+//line internal/templates/pages/admin.qtpl:76
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line internal/templates/pages/admin.qtpl:21
+//line internal/templates/pages/admin.qtpl:76
 	StreamAdminPage(qw422016, data)
-//line internal/templates/pages/admin.qtpl:21
+//line internal/templates/pages/admin.qtpl:76
 	qt422016.ReleaseWriter(qw422016)
-//line internal/templates/pages/admin.qtpl:21
+//line internal/templates/pages/admin.qtpl:76
 }
 
-//line internal/templates/pages/admin.qtpl:21
+//line internal/templates/pages/admin.qtpl:76
 func AdminPage(data *AdminData) string {
-//line internal/templates/pages/admin.qtpl:21
+//line internal/templates/pages/admin.qtpl:76
 	qb422016 := qt422016.AcquireByteBuffer()
-//line internal/templates/pages/admin.qtpl:21
+//line internal/templates/pages/admin.qtpl:76
 	WriteAdminPage(qb422016, data)
-//line internal/templates/pages/admin.qtpl:21
+//line internal/templates/pages/admin.qtpl:76
 	qs422016 := string(qb422016.B)
-//line internal/templates/pages/admin.qtpl:21
+//line internal/templates/pages/admin.qtpl:76
 	qt422016.ReleaseByteBuffer(qb422016)
-//line internal/templates/pages/admin.qtpl:21
+//line internal/templates/pages/admin.qtpl:76
 	return qs422016
-//line internal/templates/pages/admin.qtpl:21
+//line internal/templates/pages/admin.qtpl:76
 }
@@ -5,53 +5,165 @@
 package pages
 
 //line internal/templates/pages/edit.qtpl:1
-import "cms/internal/models"
+import "cms/internal/media"
 
 //line internal/templates/pages/edit.qtpl:2
-import "cms/internal/templates/layouts"
+import "cms/internal/models"
 
 //line internal/templates/pages/edit.qtpl:3
-import "strings"
+import "cms/internal/slug"
+
+//line internal/templates/pages/edit.qtpl:4
+import "cms/internal/templates/layouts"
 
 //line internal/templates/pages/edit.qtpl:5
+import "cms/internal/workflow"
+
+//line internal/templates/pages/edit.qtpl:6
+import "encoding/json"
+
+//line internal/templates/pages/edit.qtpl:7
+import "html"
+
+//line internal/templates/pages/edit.qtpl:8
+import "strconv"
+
+//line internal/templates/pages/edit.qtpl:9
+import "strings"
+
+//line internal/templates/pages/edit.qtpl:10
+import "time"
+
+//line internal/templates/pages/edit.qtpl:12
 import (
 	qtio422016 "io"
 
 	qt422016 "github.com/valyala/quicktemplate"
 )
 
-//line internal/templates/pages/edit.qtpl:5
+//line internal/templates/pages/edit.qtpl:12
 var (
 	_ = qtio422016.Copy
 	_ = qt422016.AcquireByteBuffer
 )
 
-//line internal/templates/pages/edit.qtpl:6
+//line internal/templates/pages/edit.qtpl:13
 // EditData struct is defined in models package
 type EditData = models.EditData
 
-//line internal/templates/pages/edit.qtpl:10
+// editForm holds the fields bound to the Alpine form.
+type editForm struct {
+	ID          string   `json:"id,omitempty"`
+	Title       string   `json:"title"`
+	Slug        string   `json:"slug"`
+	Status      string   `json:"status"`
+	Format      string   `json:"format"`
+	Content     string   `json:"content"`
+	PublishedAt string   `json:"published_at,omitempty"` // RFC 3339, converted to local time by the form
+	ExpiresAt   string   `json:"expires_at,omitempty"`
+	Tags        []string `json:"tags"`       // Names, resolved to slugs by the server
+	Categories  []string `json:"categories"` // Slugs
+}
+
+// fieldError renders the server's validation message for an item field,
+// shown after a failed save.
+func fieldError(field string) string {
+	return `<p x-show="fieldErrors.` + field + `" x-text="fieldErrors.` + field + `" class="mt-2 text-sm text-red-600 dark:text-red-400"></p>`
+}
+
+// formTime formats t for the form, or "" for the zero time.
+func formTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// formDataJSON encodes the item for the form's initial state. json.Marshal
+// escapes <, > and &, so the result is safe inside a <script> element.
+func formDataJSON(data *EditData) string {
+	form := editForm{Status: models.StatusDraft, Format: models.FormatHTML}
+	if len(data.Statuses) > 0 {
+		form.Status = data.Statuses[0]
+	}
+	if !data.IsNew {
+		form = editForm{
+			ID:          data.Item.ID,
+			Title:       data.Item.Title,
+			Slug:        data.Item.Slug,
+			Status:      data.Item.Status,
+			Format:      data.Item.Format,
+			Content:     data.Item.Content,
+			PublishedAt: formTime(data.Item.PublishedAt),
+			ExpiresAt:   formTime(data.Item.ExpiresAt),
+		}
+		if form.Format == "" {
+			form.Format = models.FormatHTML
+		}
+	}
+	form.Tags = append([]string{}, data.TagNames...)
+	form.Categories = append([]string{}, data.Item.Categories...)
+	b, err := json.Marshal(form)
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
+
+//line internal/templates/pages/edit.qtpl:76
 func StreamEditPage(qw422016 *qt422016.Writer, data *EditData) {
-//line internal/templates/pages/edit.qtpl:10
+//line internal/templates/pages/edit.qtpl:76
 	qw422016.N().S(`
     `)
-//line internal/templates/pages/edit.qtpl:12
+//line internal/templates/pages/edit.qtpl:78
 	pageContent := func() string {
 		var sb strings.Builder
 		actionURL := "/api/content"
 		method := "POST"
 		pageTitle := "Create New Content"
 		if !data.IsNew {
-			actionURL = "/api/content/" + data.Item.ID
+			actionURL = "/api/content/" + html.EscapeString(data.Item.ID)
 			method = "PUT"
-			pageTitle = "Edit: " + data.Item.Title
+			pageTitle = "Edit: " + html.EscapeString(data.Item.Title)
 		}
 
 		sb.WriteString(`<div class="bg-white dark:bg-gray-800 p-6 md:p-8 rounded-lg shadow-md w-full max-w-3xl mx-auto" x-data="contentForm()">
                 <h1 class="text-2xl font-semibold mb-6 text-gray-900 dark:text-white">`)
 		sb.WriteString(pageTitle)
-		sb.WriteString(`</h1>
-                
+		sb.WriteString(`</h1>`)
+		if data.Feedback != nil {
+			sb.WriteString(`<div class="mb-6 rounded-md bg-yellow-50 p-3 text-sm text-yellow-800 dark:bg-yellow-800/30 dark:text-yellow-200"><p class="font-medium">Review feedback`)
+			if data.Feedback.Editor != "" {
+				sb.WriteString(` from ` + html.EscapeString(data.Feedback.Editor))
+			}
+			sb.WriteString(`:</p><p class="mt-1 whitespace-pre-line">`)
+			sb.WriteString(html.EscapeString(data.Feedback.Comment))
+			sb.WriteString(`</p></div>`)
+		}
+		sb.WriteString(`
+                <div x-show="conflict" x-cloak class="mb-6 rounded-md border border-yellow-300 bg-yellow-50 p-4 text-sm text-yellow-900 dark:border-yellow-700 dark:bg-yellow-800/30 dark:text-yellow-100">
+                    <p class="font-medium">Someone else saved this item while you were editing it<span x-show="conflict && conflict.updatedAt" x-text="conflict ? ' (' + new Date(conflict.updatedAt).toLocaleString() + ')' : ''"></span>.</p>
+                    <p class="mt-1">Choose which version of each changed field to keep, then save again. Fields only they changed default to their version.</p>
+                    <template x-for="field in (conflict ? conflict.fields : [])" :key="field.key">
+                        <fieldset class="mt-4">
+                            <legend class="font-medium" x-text="field.label"></legend>
+                            <div class="mt-2 grid grid-cols-1 sm:grid-cols-2 gap-3">
+                                <label class="block rounded-md border border-yellow-300 dark:border-yellow-700 bg-white dark:bg-gray-800 p-2 cursor-pointer" :class="field.choice === 'mine' && 'ring-2 ring-indigo-500'">
+                                    <span class="flex items-center gap-2"><input type="radio" value="mine" x-model="field.choice"> Yours</span>
+                                    <pre class="mt-1 max-h-48 overflow-auto whitespace-pre-wrap break-words text-xs text-gray-800 dark:text-gray-200" x-text="showValue(field.mine)"></pre>
+                                </label>
+                                <label class="block rounded-md border border-yellow-300 dark:border-yellow-700 bg-white dark:bg-gray-800 p-2 cursor-pointer" :class="field.choice === 'theirs' && 'ring-2 ring-indigo-500'">
+                                    <span class="flex items-center gap-2"><input type="radio" value="theirs" x-model="field.choice"> Theirs</span>
+                                    <pre class="mt-1 max-h-48 overflow-auto whitespace-pre-wrap break-words text-xs text-gray-800 dark:text-gray-200" x-text="showValue(field.theirs)"></pre>
+                                </label>
+                            </div>
+                        </fieldset>
+                    </template>
+                    <div class="mt-4 flex items-center gap-4">
+                        <button type="button" @click="resolveConflict()" :disabled="loading" class="px-3 py-1.5 rounded-md bg-indigo-600 text-white hover:bg-indigo-700 disabled:opacity-50">Save with these choices</button>
+                        <button type="button" @click="window.location.reload()" class="text-gray-700 hover:text-gray-900 dark:text-gray-300 dark:hover:text-white">Discard your changes</button>
+                    </div>
+                </div>
                 <form @submit.prevent="submitForm('`)
 		sb.WriteString(actionURL)
 		sb.WriteString(`', '`)
@@ -59,21 +171,23 @@ func StreamEditPage(qw422016 *qt422016.Writer, data *EditData) {
 		sb.WriteString(`')" class="space-y-6">
                     <div>
                         <label for="title" class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Title</label>
-                        <input type="text" id="title" name="title" x-model="formData.title" required 
+                        <input type="text" id="title" name="title" x-model="formData.title" required maxlength="` + strconv.Itoa(models.MaxTitleLength) + `"
                                class="block w-full px-4 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm 
                                       bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 
                                       focus:ring-indigo-500 focus:border-indigo-500 
                                       dark:focus:ring-indigo-400 dark:focus:border-indigo-400">
+                        ` + fieldError("title") + `
                     </div>
 
                     <div>
                         <label for="slug" class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Slug</label>
-                        <input type="text" id="slug" name="slug" x-model="formData.slug" pattern="^[a-z0-9]+(?:-[a-z0-9]+)*$" 
+                        <input type="text" id="slug" name="slug" x-model="formData.slug" pattern="` + html.EscapeString(slug.Pattern.String()) + `" maxlength="` + strconv.Itoa(slug.MaxLength) + `"
                                class="block w-full px-4 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm 
                                       bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 
                                       focus:ring-indigo-500 focus:border-indigo-500 
                                       dark:focus:ring-indigo-400 dark:focus:border-indigo-400">
-                        <p class="mt-2 text-xs text-gray-500 dark:text-gray-400">Optional. If empty, one will be generated. Use lowercase letters, numbers, and hyphens.</p>
+                        <p class="mt-2 text-xs text-gray-500 dark:text-gray-400">Optional. If empty, one is generated from the title (existing items keep their slug). Use lowercase letters, numbers, and hyphens; old slugs redirect to the new one.</p>
+                        ` + fieldError("slug") + `
                     </div>
 
                     <div>
@@ -83,20 +197,132 @@ func StreamEditPage(qw422016 *qt422016.Writer, data *EditData) {
                                        bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 
                                        focus:ring-indigo-500 focus:border-indigo-500 
                                        dark:focus:ring-indigo-400 dark:focus:border-indigo-400">
-                            <option value="draft">Draft</option>
-                            <option value="published">Published</option>
-                            <!-- Add other statuses if needed e.g., archived -->
+`)
+		for _, status := range data.Statuses {
+			sb.WriteString(`<option value="` + html.EscapeString(status) + `">` + html.EscapeString(workflow.StateLabel(status)) + `</option>`)
+		}
+		sb.WriteString(`
                         </select>
+                        ` + fieldError("status") + `
+                    </div>
+
+                    <div x-show="formData.status === 'scheduled' || formData.status === 'published'" class="grid grid-cols-1 sm:grid-cols-2 gap-4">
+                        <div>
+                            <label for="publish_at" class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Publish at</label>
+                            <input type="datetime-local" id="publish_at" x-model="publishAt" :required="formData.status === 'scheduled'"
+                                   class="block w-full px-4 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm 
+                                          bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 
+                                          focus:ring-indigo-500 focus:border-indigo-500 
+                                          dark:focus:ring-indigo-400 dark:focus:border-indigo-400">
+                            ` + fieldError("published_at") + `
+                        </div>
+                        <div>
+                            <label for="expires_at" class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Expires at (optional)</label>
+                            <input type="datetime-local" id="expires_at" x-model="expiresAt"
+                                   class="block w-full px-4 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm 
+                                          bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 
+                                          focus:ring-indigo-500 focus:border-indigo-500 
+                                          dark:focus:ring-indigo-400 dark:focus:border-indigo-400">
+                            ` + fieldError("expires_at") + `
+                        </div>
+                        <p class="sm:col-span-2 -mt-2 text-xs text-gray-500 dark:text-gray-400">Times are in your time zone. A scheduled item is published at its publish time; a published item is archived when it expires.</p>
                     </div>
                     
                     <div>
-                        <label for="content" class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Content</label>
-                        <textarea id="content" name="content" x-model="formData.content" rows="12" 
+                        <label for="format" class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Format</label>
+                        <select id="format" name="format" x-model="formData.format" 
+                                class="block w-full pl-4 pr-10 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm 
+                                       bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 
+                                       focus:ring-indigo-500 focus:border-indigo-500 
+                                       dark:focus:ring-indigo-400 dark:focus:border-indigo-400">
+                            <option value="html">HTML</option>
+                            <option value="markdown">Markdown</option>
+                            <option value="plain">Plain text</option>
+                        </select>
+                        <p class="mt-2 text-xs text-gray-500 dark:text-gray-400">HTML is cleaned against an allowlist; scripts and event handlers are removed.</p>
+                        ` + fieldError("format") + `
+                    </div>
+
+                    <div>
+                        <label for="tags" class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Tags</label>
+                        <div class="relative">
+                            <div class="flex flex-wrap items-center gap-2 px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm bg-white dark:bg-gray-700">
+                                <template x-for="tag in formData.tags" :key="tag">
+                                    <span class="inline-flex items-center gap-1 rounded-full bg-indigo-50 px-2.5 py-0.5 text-sm text-indigo-700 dark:bg-indigo-900/40 dark:text-indigo-300">
+                                        <span x-text="tag"></span>
+                                        <button type="button" @click="removeTag(tag)" class="hover:text-indigo-900 dark:hover:text-indigo-100" :aria-label="'Remove ' + tag">&times;</button>
+                                    </span>
+                                </template>
+                                <input type="text" id="tags" x-model="tagInput" @input.debounce.250ms="suggestTags()" @keydown.enter.prevent="addTag(tagInput)" @keydown.comma.prevent="addTag(tagInput)" @keydown.backspace="tagInput === '' && formData.tags.pop()" autocomplete="off"
+                                       class="flex-1 min-w-[8rem] border-0 p-0 bg-transparent text-gray-900 dark:text-gray-100 focus:ring-0">
+                            </div>
+                            <ul x-show="suggestions.length > 0" @click.outside="suggestions = []" class="absolute z-10 mt-1 w-full rounded-md border border-gray-200 dark:border-gray-600 bg-white dark:bg-gray-700 shadow-lg text-sm">
+                                <template x-for="suggestion in suggestions" :key="suggestion.slug">
+                                    <li><button type="button" @click="addTag(suggestion.name)" class="block w-full px-4 py-2 text-left text-gray-900 dark:text-gray-100 hover:bg-gray-100 dark:hover:bg-gray-600" x-text="suggestion.name"></button></li>
+                                </template>
+                            </ul>
+                        </div>
+                        <p class="mt-2 text-xs text-gray-500 dark:text-gray-400">Press Enter or comma to add a tag. New tags are created when the item is saved.</p>
+                        ` + fieldError("tags") + `
+                    </div>
+`)
+		if len(data.Categories) > 0 {
+			sb.WriteString(`<fieldset>
+                        <legend class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Categories</legend>
+                        <div class="space-y-1">`)
+			for _, entry := range data.Categories {
+				sb.WriteString(`<label class="flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300" style="padding-left: ` + strconv.Itoa(entry.Depth*24) + `px">
+                            <input type="checkbox" value="` + html.EscapeString(entry.Term.Slug) + `" x-model="formData.categories" class="rounded border-gray-300 dark:border-gray-600 text-indigo-600 focus:ring-indigo-500">
+                            ` + html.EscapeString(entry.Term.Name) + `
+                        </label>`)
+			}
+			sb.WriteString(`</div>
+                        ` + fieldError("categories") + `
+                    </fieldset>`)
+		}
+		sb.WriteString(`
+                    <div>
+                        <div class="flex items-center justify-between mb-1">
+                            <label for="content" class="block text-sm font-medium text-gray-700 dark:text-gray-300">Content</label>
+                            <div class="flex items-center gap-4">
+                                <button type="button" @click="togglePicker()" class="text-sm text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">Insert media</button>
+                                <button type="button" @click="togglePreview()" class="text-sm text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300" x-text="previewing ? 'Edit' : 'Preview'"></button>
+                            </div>
+                        </div>
+                        <div x-show="picking" class="mb-2 rounded-md border border-gray-300 dark:border-gray-600 p-3 text-sm text-gray-700 dark:text-gray-300">
+                            <div class="flex flex-wrap items-end gap-3">
+                                <label class="flex flex-col gap-1">Search<input type="search" x-model="mediaQuery" @input.debounce.300ms="loadMedia()" class="px-3 py-1.5 border border-gray-300 dark:border-gray-600 rounded-md bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100"></label>
+                                <label class="flex flex-col gap-1">Image size<select x-model="mediaSize" class="px-3 py-1.5 border border-gray-300 dark:border-gray-600 rounded-md bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100">
+                                    <option value="">Original</option>`)
+		for _, preset := range media.Presets {
+			sb.WriteString(`<option value="` + preset.Name + `">` + preset.Name + ` (` + strconv.Itoa(preset.Width) + `&times;` + strconv.Itoa(preset.Height) + `)</option>`)
+		}
+		sb.WriteString(`</select></label>`)
+		if !data.IsSandbox() { // The media library is shared, see sandboxSharedNotice
+			sb.WriteString(`<label class="flex flex-col gap-1">Upload<input type="file" @change="uploadMedia($event)" accept="image/jpeg,image/png,image/gif,image/webp,application/pdf" class="text-sm"></label>`)
+		}
+		sb.WriteString(`<a href="/media" target="_blank" class="py-1.5 text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">Media library</a>
+                            </div>
+                            <p x-show="mediaError" x-text="mediaError" class="mt-2 text-red-600 dark:text-red-400"></p>
+                            <p x-show="mediaItems.length === 0" class="mt-3 text-gray-500 dark:text-gray-400">No files found.</p>
+                            <ul class="mt-3 grid grid-cols-3 sm:grid-cols-5 gap-2 max-h-64 overflow-y-auto">
+                                <template x-for="item in mediaItems" :key="item.id">
+                                    <li>
+                                        <button type="button" @click="insertMedia(item)" :title="item.filename" class="flex h-20 w-full items-center justify-center overflow-hidden rounded bg-gray-100 dark:bg-gray-700 hover:ring-2 hover:ring-indigo-500">
+                                            <img x-show="item.mime.startsWith('image/')" :src="item.url + '?size=thumb'" :alt="item.alt" loading="lazy" class="max-h-20 object-contain">
+                                            <span x-show="!item.mime.startsWith('image/')" class="px-1 text-xs break-all" x-text="item.filename"></span>
+                                        </button>
+                                    </li>
+                                </template>
+                            </ul>
+                        </div>
+                        <textarea id="content" name="content" x-model="formData.content" x-show="!previewing" @input.debounce.500ms="refreshPreview()" rows="12" maxlength="` + strconv.Itoa(models.MaxContentLength) + `"
                                   class="block w-full px-4 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm 
                                          bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 
                                          focus:ring-indigo-500 focus:border-indigo-500 
                                          dark:focus:ring-indigo-400 dark:focus:border-indigo-400"></textarea>
-                        <!-- Consider adding a WYSIWYG editor here later -->
+                        <div x-show="previewing" x-html="previewHTML" class="prose dark:prose-invert max-w-none min-h-[12rem] px-4 py-2 border border-gray-300 dark:border-gray-600 rounded-md"></div>
+                        ` + fieldError("content") + `
                     </div>
 
                     <!-- Hidden fields for IDs, timestamps will be handled server-side -->
@@ -126,74 +352,247 @@ func StreamEditPage(qw422016 *qt422016.Writer, data *EditData) {
             <script>
                 function contentForm() {
                     return {
-                        formData: {
-                            id: `)
-		if !data.IsNew {
-			sb.WriteString("`" + data.Item.ID + "`")
-		} else {
-			sb.WriteString(`null`)
-		}
+                        formData: `)
+		sb.WriteString(formDataJSON(data))
 		sb.WriteString(`,
-                            title: `)
-		if !data.IsNew {
-			sb.WriteString("`" + strings.ReplaceAll(data.Item.Title, "`", "\\`") + "`")
-		} else {
-			sb.WriteString(`''`)
-		}
-		sb.WriteString(`,
-                            slug: `)
-		if !data.IsNew {
-			sb.WriteString("`" + strings.ReplaceAll(data.Item.Slug, "`", "\\`") + "`")
-		} else {
+                        etag: `)
+		if data.IsNew {
 			sb.WriteString(`''`)
-		}
-		sb.WriteString(`,
-                            status: `)
-		if !data.IsNew {
-			sb.WriteString("`" + data.Item.Status + "`")
 		} else {
-			sb.WriteString(`'draft'`)
+			sb.WriteString(strconv.Quote(data.Item.ETag())) // Sent as If-Match so stale saves are rejected
 		}
 		sb.WriteString(`,
-                            content: `)
-		if !data.IsNew {
-			// Escape backticks and backslashes for JS template literal
-			escapedContent := strings.ReplaceAll(data.Item.Content, "\\", "\\\\")
-			escapedContent = strings.ReplaceAll(escapedContent, "`", "\\`")
-			sb.WriteString("`" + escapedContent + "`")
-		} else {
-			sb.WriteString(`''`)
-		}
-		sb.WriteString(`
-                        },
+                        original: {},
+                        conflict: null,
+                        fieldErrors: {}, // Validation messages by field, from the last save
                         loading: false,
                         message: '',
                         success: false,
+                        previewing: false,
+                        previewHTML: '',
+                        publishAt: '',
+                        expiresAt: '',
+                        tagInput: '',
+                        picking: false,
+                        mediaItems: [],
+                        mediaQuery: '',
+                        mediaSize: 'medium',
+                        mediaError: '',
+                        suggestions: [],
+
+                        init() {
+                            this.publishAt = this.localTime(this.formData.published_at);
+                            this.expiresAt = this.localTime(this.formData.expires_at);
+                            this.original = this.fieldValues();
+                        },
+
+                        // Fields compared when a save conflicts with someone else's
+                        conflictFields: [
+                            { key: 'title', label: 'Title' },
+                            { key: 'slug', label: 'Slug' },
+                            { key: 'status', label: 'Status' },
+                            { key: 'publishAt', label: 'Publish at' },
+                            { key: 'expiresAt', label: 'Expires at' },
+                            { key: 'format', label: 'Format' },
+                            { key: 'tags', label: 'Tags' },
+                            { key: 'categories', label: 'Categories' },
+                            { key: 'content', label: 'Content' },
+                        ],
+
+                        // fieldValues returns the form's values, or an item's from the API
+                        fieldValues(item) {
+                            const v = item ? {
+                                title: item.title || '',
+                                slug: item.slug || '',
+                                status: item.status || '',
+                                publishAt: this.localTime(item.published_at),
+                                expiresAt: this.localTime(item.expires_at),
+                                format: item.format || 'html',
+                                tags: item.tags || [],
+                                categories: item.categories || [],
+                                content: item.content || '',
+                            } : Object.assign({}, this.formData, { publishAt: this.publishAt, expiresAt: this.expiresAt });
+                            const values = {};
+                            for (const f of this.conflictFields) values[f.key] = Array.isArray(v[f.key]) ? [...v[f.key]] : v[f.key];
+                            return values;
+                        },
+
+                        // sameValue compares field values; tags are compared by slug,
+                        // as the API returns slugs where the form has names
+                        sameValue(key, a, b) {
+                            if (!Array.isArray(a)) return a === b;
+                            const norm = list => list.map(t => key === 'tags' ? t.toLowerCase().replace(/[^a-z0-9]+/g, '-').replace(/^-|-$/g, '') : t).sort().join(',');
+                            return norm(a) === norm(b);
+                        },
+
+                        showValue(value) {
+                            if (Array.isArray(value)) return value.length ? value.join(', ') : '(none)';
+                            return value === '' ? '(empty)' : value;
+                        },
+
+                        // localTime converts an RFC 3339 time to a datetime-local value
+                        localTime(iso) {
+                            if (!iso || iso.startsWith('0001-')) return ''; // Go's zero time
+                            const d = new Date(iso);
+                            d.setMinutes(d.getMinutes() - d.getTimezoneOffset());
+                            return d.toISOString().slice(0, 16);
+                        },
+
+                        addTag(name) {
+                            name = name.replace(/,/g, '').trim();
+                            const exists = this.formData.tags.some(t => t.toLowerCase() === name.toLowerCase());
+                            if (name && !exists) this.formData.tags.push(name);
+                            this.tagInput = '';
+                            this.suggestions = [];
+                        },
+
+                        removeTag(name) {
+                            this.formData.tags = this.formData.tags.filter(t => t !== name);
+                        },
+
+                        async suggestTags() {
+                            const q = this.tagInput.trim();
+                            if (!q) {
+                                this.suggestions = [];
+                                return;
+                            }
+                            try {
+                                const response = await fetch('/api/tags?q=' + encodeURIComponent(q));
+                                if (!response.ok) throw new Error(await responseError(response));
+                                const tags = await response.json();
+                                this.suggestions = (tags || []).filter(t => !this.formData.tags.some(name => name.toLowerCase() === t.name.toLowerCase()));
+                            } catch (error) {
+                                console.error('Tag suggestion error:', error);
+                            }
+                        },
+
+                        togglePicker() {
+                            this.picking = !this.picking;
+                            if (this.picking) this.loadMedia();
+                        },
+
+                        async loadMedia() {
+                            try {
+                                const response = await fetch('/api/media?q=' + encodeURIComponent(this.mediaQuery));
+                                if (!response.ok) throw new Error(await responseError(response));
+                                this.mediaItems = await response.json();
+                            } catch (error) {
+                                this.mediaError = 'Error loading media: ' + error.message;
+                            }
+                        },
+
+                        async uploadMedia(event) {
+                            const file = event.target.files[0];
+                            if (!file) return;
+                            this.mediaError = '';
+                            const body = new FormData();
+                            body.append('file', file);
+                            try {
+                                const response = await fetch('/api/media', {
+                                    method: 'POST',
+                                    headers: { 'X-CSRF-Token': this.csrfToken() },
+                                    body: body
+                                });
+                                if (!response.ok) throw new Error(await responseError(response));
+                                this.insertMedia(await response.json());
+                                this.loadMedia();
+                            } catch (error) {
+                                this.mediaError = 'Error uploading file: ' + error.message;
+                            } finally {
+                                event.target.value = '';
+                            }
+                        },
+
+                        // insertMedia puts an image or link to item at the cursor, written in the item's format
+                        insertMedia(item) {
+                            const image = item.mime.startsWith('image/');
+                            const url = image && this.mediaSize ? item.url + '?size=' + this.mediaSize : item.url;
+                            const escape = s => s.replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;').replace(/"/g, '&quot;');
+                            let snippet = url;
+                            if (this.formData.format === 'markdown') {
+                                const text = (image ? item.alt || '' : item.filename).replace(/[\[\]]/g, '');
+                                snippet = image ? '![' + text + '](' + url + ')' : '[' + text + '](' + url + ')';
+                            } else if (this.formData.format === 'html') {
+                                snippet = image ? '<img src="' + escape(url) + '" alt="' + escape(item.alt || '') + '">' : '<a href="' + escape(url) + '">' + escape(item.filename) + '</a>';
+                            }
+                            const textarea = document.getElementById('content');
+                            const start = textarea.selectionStart ?? this.formData.content.length;
+                            const end = textarea.selectionEnd ?? start;
+                            this.formData.content = this.formData.content.slice(0, start) + snippet + this.formData.content.slice(end);
+                            this.picking = false;
+                            this.$nextTick(() => {
+                                textarea.focus();
+                                textarea.setSelectionRange(start + snippet.length, start + snippet.length);
+                            });
+                            this.refreshPreview();
+                        },
+
+                        csrfToken() {
+                            // Double-submit the CSRF cookie issued with this page
+                            return (document.cookie.match(/(?:^|; )csrf_token=([^;]*)/) || [])[1] || '';
+                        },
+
+                        togglePreview() {
+                            this.previewing = !this.previewing;
+                            if (this.previewing) this.refreshPreview();
+                        },
+
+                        async refreshPreview() {
+                            if (!this.previewing) return;
+                            try {
+                                const response = await fetch('/api/content/preview', {
+                                    method: 'POST',
+                                    headers: {
+                                        'Content-Type': 'application/json',
+                                        'X-CSRF-Token': this.csrfToken(),
+                                    },
+                                    body: JSON.stringify({ format: this.formData.format, content: this.formData.content })
+                                });
+                                if (!response.ok) throw new Error(await responseError(response));
+                                this.previewHTML = (await response.json()).html; // Sanitized server-side
+                            } catch (error) {
+                                console.error('Preview error:', error);
+                            }
+                        },
 
                         async submitForm(url, method) {
+                            if (this.tagInput.trim()) this.addTag(this.tagInput); // Keep a tag still being typed
                             this.loading = true;
                             this.message = '';
                             this.success = false;
-                            
-                            // Basic slug generation if slug is empty
-                            if (!this.formData.slug && this.formData.title) {
-                                this.formData.slug = this.formData.title.toLowerCase().replace(/\s+/g, '-').replace(/[^a-z0-9-]/g, '');
-                            }
+                            this.fieldErrors = {};
 
                             try {
+                                const headers = {
+                                    'Content-Type': 'application/json',
+                                    'X-CSRF-Token': this.csrfToken(),
+                                };
+                                if (method === 'PUT' && this.etag) headers['If-Match'] = this.etag;
                                 const response = await fetch(url, {
                                     method: method,
-                                    headers: {
-                                        'Content-Type': 'application/json',
-                                    },
-                                    body: JSON.stringify(this.formData)
+                                    headers: headers,
+                                    body: JSON.stringify(Object.assign({}, this.formData, {
+                                        // Omitted times are left as they are; datetime-local values are local time
+                                        published_at: this.publishAt ? new Date(this.publishAt).toISOString() : undefined,
+                                        expires_at: this.expiresAt ? new Date(this.expiresAt).toISOString() : undefined,
+                                    }))
                                 });
 
+                                if (response.status === 412) {
+                                    // Retry at once if their save matches the form, so nothing is lost
+                                    if (await this.loadConflict(url, method)) return await this.submitForm(url, method);
+                                    return;
+                                }
                                 if (!response.ok) {
-                                    const errorText = await response.text();
-                                    throw new Error(response.statusText + ": " + errorText);
+                                    const type = response.headers.get('Content-Type') || '';
+                                    if (!type.includes('json')) throw new Error(await response.text());
+                                    const problem = await response.json();
+                                    for (const e of problem.errors || []) this.fieldErrors[e.field] = e.detail;
+                                    throw new Error(problemMessage(problem));
                                 }
                                 
+                                this.etag = response.headers.get('ETag') || this.etag;
+                                this.conflict = null;
                                 this.success = true;
                                 this.message = 'Content saved successfully!';
 
@@ -219,6 +618,46 @@ func StreamEditPage(qw422016 *qt422016.Writer, data *EditData) {
                             } finally {
                                 this.loading = false;
                             }
+                        },
+
+                        // loadConflict fetches the version that was saved in the
+                        // meantime and lists the fields where it differs from the form.
+                        // Returns true if there are none.
+                        async loadConflict(url, method) {
+                            const response = await fetch(url);
+                            if (!response.ok) throw new Error('Error loading the current version: ' + await responseError(response));
+                            const item = await response.json();
+                            const mine = this.fieldValues();
+                            const theirs = this.fieldValues(item);
+                            const fields = [];
+                            for (const f of this.conflictFields) {
+                                if (this.sameValue(f.key, mine[f.key], theirs[f.key])) continue;
+                                const changedByMe = !this.sameValue(f.key, mine[f.key], this.original[f.key]);
+                                fields.push({ key: f.key, label: f.label, mine: mine[f.key], theirs: theirs[f.key], choice: changedByMe ? 'mine' : 'theirs' });
+                            }
+                            this.etag = response.headers.get('ETag');
+                            this.original = theirs;
+                            if (fields.length === 0) return true;
+                            this.conflict = { url: url, method: method, updatedAt: item.updated_at, fields: fields };
+                            this.message = 'Not saved: this item was changed by someone else. Review the differences above.';
+                            window.scrollTo({ top: 0, behavior: 'smooth' });
+                            return false;
+                        },
+
+                        // resolveConflict applies the chosen versions and saves over
+                        // the version the choices were made against
+                        resolveConflict() {
+                            const { url, method, fields } = this.conflict;
+                            for (const field of fields) {
+                                if (field.choice !== 'theirs') continue;
+                                if (field.key === 'publishAt' || field.key === 'expiresAt') {
+                                    this[field.key] = field.theirs;
+                                } else {
+                                    this.formData[field.key] = field.theirs;
+                                }
+                            }
+                            this.conflict = null;
+                            this.submitForm(url, method);
                         }
                     }
                 }
@@ -241,39 +680,39 @@ func StreamEditPage(qw422016 *qt422016.Writer, data *EditData) {
 		return sb.String()
 	}
 
-//line internal/templates/pages/edit.qtpl:216
+//line internal/templates/pages/edit.qtpl:641
 	qw422016.N().S(`
     `)
-//line internal/templates/pages/edit.qtpl:217
+//line internal/templates/pages/edit.qtpl:642
 	qw422016.N().S(layouts.BaseLayout(data, pageContent))
-//line internal/templates/pages/edit.qtpl:217
+//line internal/templates/pages/edit.qtpl:642
 	qw422016.N().S(`
 `)
-//line internal/templates/pages/edit.qtpl:218
+//line internal/templates/pages/edit.qtpl:643
 }
 
-//line internal/templates/pages/edit.qtpl:218
+//line internal/templates/pages/edit.qtpl:643
 func WriteEditPage(qq422016 qtio422016.Writer, data *EditData) {
-//line internal/templates/pages/edit.qtpl:218
+//line internal/templates/pages/edit.qtpl:643
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line internal/templates/pages/edit.qtpl:218
+//line internal/templates/pages/edit.qtpl:643
 	StreamEditPage(qw422016, data)
-//line internal/templates/pages/edit.qtpl:218
+//line internal/templates/pages/edit.qtpl:643
 	qt422016.ReleaseWriter(qw422016)
-//line internal/templates/pages/edit.qtpl:218
+//line internal/templates/pages/edit.qtpl:643
 }
 
-//line internal/templates/pages/edit.qtpl:218
+//line internal/templates/pages/edit.qtpl:643
 func EditPage(data *EditData) string {
-//line internal/templates/pages/edit.qtpl:218
+//line internal/templates/pages/edit.qtpl:643
 	qb422016 := qt422016.AcquireByteBuffer()
-//line internal/templates/pages/edit.qtpl:218
+//line internal/templates/pages/edit.qtpl:643
 	WriteEditPage(qb422016, data)
-//line internal/templates/pages/edit.qtpl:218
+//line internal/templates/pages/edit.qtpl:643
 	qs422016 := string(qb422016.B)
-//line internal/templates/pages/edit.qtpl:218
+//line internal/templates/pages/edit.qtpl:643
 	qt422016.ReleaseByteBuffer(qb422016)
-//line internal/templates/pages/edit.qtpl:218
+//line internal/templates/pages/edit.qtpl:643
 	return qs422016
-//line internal/templates/pages/edit.qtpl:218
+//line internal/templates/pages/edit.qtpl:643
 }
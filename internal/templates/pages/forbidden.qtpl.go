@@ -0,0 +1,83 @@
+// Code generated by qtc from "forbidden.qtpl". DO NOT EDIT.
+// See https://github.com/valyala/quicktemplate for details.
+
+//line internal/templates/pages/forbidden.qtpl:1
+package pages
+
+//line internal/templates/pages/forbidden.qtpl:1
+import "cms/internal/models"
+
+//line internal/templates/pages/forbidden.qtpl:2
+import "cms/internal/templates/layouts"
+
+//line internal/templates/pages/forbidden.qtpl:3
+import "strings"
+
+//line internal/templates/pages/forbidden.qtpl:5
+import (
+	qtio422016 "io"
+
+	qt422016 "github.com/valyala/quicktemplate"
+)
+
+//line internal/templates/pages/forbidden.qtpl:5
+var (
+	_ = qtio422016.Copy
+	_ = qt422016.AcquireByteBuffer
+)
+
+//line internal/templates/pages/forbidden.qtpl:6
+type ForbiddenData = models.BasePageData
+
+//line internal/templates/pages/forbidden.qtpl:9
+func StreamForbiddenPage(qw422016 *qt422016.Writer, data *ForbiddenData) {
+//line internal/templates/pages/forbidden.qtpl:9
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/forbidden.qtpl:11
+	pageContent := func() string {
+		var sb strings.Builder
+		sb.WriteString(`<div class="text-center py-16 sm:py-24">
+                    <h1 class="text-4xl font-bold text-gray-700 dark:text-gray-200 mb-4">403 - Forbidden</h1>
+                    <p class="text-lg text-gray-500 dark:text-gray-400 mb-8">Your role does not allow you to access this page.</p>
+                    <a href="/content" class="px-5 py-2.5 bg-indigo-600 text-white rounded-md hover:bg-indigo-700 dark:bg-indigo-500 dark:hover:bg-indigo-400">Back to Content</a>
+                </div>`)
+		return sb.String()
+	}
+
+//line internal/templates/pages/forbidden.qtpl:20
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/forbidden.qtpl:21
+	qw422016.N().S(layouts.BaseLayout(data, pageContent))
+//line internal/templates/pages/forbidden.qtpl:21
+	qw422016.N().S(`
+`)
+//line internal/templates/pages/forbidden.qtpl:22
+}
+
+//line internal/templates/pages/forbidden.qtpl:22
+func WriteForbiddenPage(qq422016 qtio422016.Writer, data *ForbiddenData) {
+//line internal/templates/pages/forbidden.qtpl:22
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/templates/pages/forbidden.qtpl:22
+	StreamForbiddenPage(qw422016, data)
+//line internal/templates/pages/forbidden.qtpl:22
+	qt422016.ReleaseWriter(qw422016)
+//line internal/templates/pages/forbidden.qtpl:22
+}
+
+//line internal/templates/pages/forbidden.qtpl:22
+func ForbiddenPage(data *ForbiddenData) string {
+//line internal/templates/pages/forbidden.qtpl:22
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/templates/pages/forbidden.qtpl:22
+	WriteForbiddenPage(qb422016, data)
+//line internal/templates/pages/forbidden.qtpl:22
+	qs422016 := string(qb422016.B)
+//line internal/templates/pages/forbidden.qtpl:22
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/templates/pages/forbidden.qtpl:22
+	return qs422016
+//line internal/templates/pages/forbidden.qtpl:22
+}
@@ -11,50 +11,120 @@ import "cms/internal/models"
 import "cms/internal/templates/layouts"
 
 //line internal/templates/pages/list.qtpl:3
-import "strings"
+import "cms/internal/workflow"
+
+//line internal/templates/pages/list.qtpl:4
+import "encoding/json"
 
 //line internal/templates/pages/list.qtpl:5
+import "html"
+
+//line internal/templates/pages/list.qtpl:6
+import "strings"
+
+//line internal/templates/pages/list.qtpl:8
 import (
 	qtio422016 "io"
 
 	qt422016 "github.com/valyala/quicktemplate"
 )
 
-//line internal/templates/pages/list.qtpl:5
+//line internal/templates/pages/list.qtpl:8
 var (
 	_ = qtio422016.Copy
 	_ = qt422016.AcquireByteBuffer
 )
 
-//line internal/templates/pages/list.qtpl:6
+//line internal/templates/pages/list.qtpl:9
 // ListData struct is defined in models package
 type ListData = models.ListData
 
-//line internal/templates/pages/list.qtpl:10
+// bulkItem is what the bulk actions script knows about a selectable row.
+type bulkItem struct {
+	Title string `json:"title"`
+	ETag  string `json:"etag"` // Sent as if_match, so items changed since the page loaded are not overwritten
+}
+
+// bulkItemsJSON encodes the selectable items for the bulk actions script.
+func bulkItemsJSON(data *ListData) string {
+	items := make(map[string]bulkItem, len(data.Selectable))
+	for _, item := range data.Items {
+		if data.Selectable[item.ID] {
+			items[item.ID] = bulkItem{Title: item.Title, ETag: item.ETag()}
+		}
+	}
+	b, err := json.Marshal(items)
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
+
+//line internal/templates/pages/list.qtpl:34
 func StreamListPage(qw422016 *qt422016.Writer, data *ListData) {
-//line internal/templates/pages/list.qtpl:10
+//line internal/templates/pages/list.qtpl:34
 	qw422016.N().S(`
     `)
-//line internal/templates/pages/list.qtpl:12
+//line internal/templates/pages/list.qtpl:36
 	pageContent := func() string {
 		var sb strings.Builder
-		sb.WriteString(`<div class="px-4 sm:px-6 lg:px-8">
+		bulk := len(data.Selectable) > 0
+		inputClass := `px-3 py-1.5 border border-gray-300 dark:border-gray-600 rounded-md bg-white dark:bg-gray-700 text-sm text-gray-900 dark:text-gray-100`
+		sb.WriteString(`<div class="px-4 sm:px-6 lg:px-8" x-data="bulkActions(` + html.EscapeString(bulkItemsJSON(data)) + `)">
                 <div class="sm:flex sm:items-center">
                     <div class="sm:flex-auto">
                         <h1 class="text-2xl font-semibold leading-6 text-gray-900 dark:text-white">Content Items</h1>
                         <p class="mt-2 text-sm text-gray-700 dark:text-gray-300">A list of all the content items in the database.</p>
                     </div>
-                    <div class="mt-4 sm:ml-16 sm:mt-0 sm:flex-none">
+                    <div class="mt-4 sm:ml-16 sm:mt-0 sm:flex-none flex items-center gap-4">`)
+		if data.CanManageTerms() {
+			sb.WriteString(`<a href="/admin/taxonomies" class="text-sm font-semibold text-gray-600 hover:text-gray-900 dark:text-gray-400 dark:hover:text-gray-200">Tags &amp; categories</a>`)
+		}
+		sb.WriteString(`
+                        <a href="/types" class="text-sm font-semibold text-gray-600 hover:text-gray-900 dark:text-gray-400 dark:hover:text-gray-200">Content types</a>
+                        <a href="/content/review" class="text-sm font-semibold text-gray-600 hover:text-gray-900 dark:text-gray-400 dark:hover:text-gray-200">Review queue</a>
+                        <a href="/content/trash" class="text-sm font-semibold text-gray-600 hover:text-gray-900 dark:text-gray-400 dark:hover:text-gray-200">Trash</a>
                         <a href="/content/new" class="block rounded-md bg-indigo-600 px-4 py-2 text-center text-sm font-semibold text-white shadow-sm hover:bg-indigo-500 focus-visible:outline focus-visible:outline-2 focus-visible:outline-offset-2 focus-visible:outline-indigo-600 dark:bg-indigo-500 dark:hover:bg-indigo-400">Add Content</a>
                     </div>
+                </div>`)
+		if data.Message != "" {
+			sb.WriteString(`<p class="mt-4 rounded-md bg-green-50 p-3 text-sm text-green-800 dark:bg-green-800/30 dark:text-green-300">`)
+			sb.WriteString(html.EscapeString(data.Message))
+			sb.WriteString(`</p>`)
+		}
+		sb.WriteString(listFilterForm(data.Filter, data.Statuses, data.Categories))
+		if data.ErrorMessage != "" {
+			sb.WriteString(`<p class="mt-4 rounded-md bg-red-50 p-3 text-sm text-red-800 dark:bg-red-800/30 dark:text-red-300">`)
+			sb.WriteString(html.EscapeString(data.ErrorMessage))
+			sb.WriteString(`</p>`)
+		}
+		if bulk {
+			sb.WriteString(`<div x-show="selected.length > 0" x-cloak class="mt-6 flex flex-wrap items-end gap-3 rounded-md bg-gray-50 dark:bg-gray-700/50 p-3 text-sm text-gray-700 dark:text-gray-300">
+                    <span class="py-1.5 font-medium" x-text="selected.length + ' selected'"></span>
+                    <label class="flex flex-col gap-1">Status<select x-model="status" class="` + inputClass + `">`)
+			for _, status := range data.Statuses {
+				sb.WriteString(`<option value="` + html.EscapeString(status) + `">` + html.EscapeString(workflow.StateLabel(status)) + `</option>`)
+			}
+			sb.WriteString(`</select></label>
+                    <label class="flex flex-col gap-1">Comment<input type="text" x-model="comment" placeholder="Required for some changes" class="` + inputClass + `"></label>
+                    <button type="button" @click="changeStatus()" :disabled="loading" class="px-3 py-1.5 rounded-md bg-indigo-600 text-white hover:bg-indigo-700 disabled:opacity-50">Change status</button>
+                    <button type="button" @click="trash()" :disabled="loading" class="px-3 py-1.5 rounded-md text-red-600 hover:text-red-800 dark:text-red-400 dark:hover:text-red-300 disabled:opacity-50">Move to trash</button>
+                    <button type="button" @click="selected = []" class="ml-auto px-1 py-1.5 text-gray-600 hover:text-gray-900 dark:text-gray-400 dark:hover:text-gray-200">Clear selection</button>
                 </div>
+                <p x-show="error" x-text="error" x-cloak class="mt-4 rounded-md bg-red-50 p-3 text-sm text-red-800 dark:bg-red-800/30 dark:text-red-300"></p>`)
+		}
+		sb.WriteString(`
                 <div class="mt-8 flow-root">
                     <div class="-mx-4 -my-2 overflow-x-auto sm:-mx-6 lg:-mx-8">
                         <div class="inline-block min-w-full py-2 align-middle sm:px-6 lg:px-8">
                             <div class="overflow-hidden shadow ring-1 ring-black ring-opacity-5 dark:ring-white dark:ring-opacity-10 sm:rounded-lg">
                                 <table class="min-w-full divide-y divide-gray-300 dark:divide-gray-700">
                                     <thead class="bg-gray-50 dark:bg-gray-700">
-                                        <tr>
+                                        <tr>`)
+		if bulk {
+			sb.WriteString(`<th scope="col" class="w-8 py-3.5 pl-4 sm:pl-6"><input type="checkbox" aria-label="Select all" :checked="selected.length === Object.keys(items).length" @change="selected = $event.target.checked ? Object.keys(items) : []" class="rounded border-gray-300 dark:border-gray-600 text-indigo-600 focus:ring-indigo-500"></th>`)
+		}
+		sb.WriteString(`
                                             <th scope="col" class="py-3.5 pl-4 pr-3 text-left text-sm font-semibold text-gray-900 dark:text-gray-100 sm:pl-6">Title</th>
                                             <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Status</th>
                                             <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Updated At</th>
@@ -66,15 +136,27 @@ func StreamListPage(qw422016 *qt422016.Writer, data *ListData) {
                                     <tbody class="divide-y divide-gray-200 dark:divide-gray-700 bg-white dark:bg-gray-800">
             `)
 
+		columns := "4"
+		if bulk {
+			columns = "5"
+		}
 		if len(data.Items) == 0 {
-			sb.WriteString(`<tr><td colspan="4" class="whitespace-nowrap py-4 pl-4 pr-3 text-sm text-gray-500 dark:text-gray-400 sm:pl-6">No content items found.</td></tr>`)
+			sb.WriteString(`<tr><td colspan="` + columns + `" class="whitespace-nowrap py-4 pl-4 pr-3 text-sm text-gray-500 dark:text-gray-400 sm:pl-6">No content items found.</td></tr>`)
 		} else {
 			for _, item := range data.Items {
-				sb.WriteString(`<tr class="hover:bg-gray-50 dark:hover:bg-gray-700">
+				sb.WriteString(`<tr class="hover:bg-gray-50 dark:hover:bg-gray-700">`)
+				if bulk {
+					sb.WriteString(`<td class="w-8 py-4 pl-4 sm:pl-6">`)
+					if data.Selectable[item.ID] {
+						sb.WriteString(`<input type="checkbox" value="` + html.EscapeString(item.ID) + `" x-model="selected" aria-label="Select ` + html.EscapeString(item.Title) + `" class="rounded border-gray-300 dark:border-gray-600 text-indigo-600 focus:ring-indigo-500">`)
+					}
+					sb.WriteString(`</td>`)
+				}
+				sb.WriteString(`
                         <td class="whitespace-nowrap py-4 pl-4 pr-3 text-sm font-medium text-gray-900 dark:text-gray-100 sm:pl-6"><a href="/content/`)
-				sb.WriteString(item.ID)
+				sb.WriteString(html.EscapeString(item.ID))
 				sb.WriteString(`" class="text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">`)
-				sb.WriteString(item.Title)
+				sb.WriteString(html.EscapeString(item.Title))
 				sb.WriteString(`</a></td>
                         <td class="whitespace-nowrap px-3 py-4 text-sm text-gray-500 dark:text-gray-400">`)
 				// Add badge styling for status
@@ -85,16 +167,16 @@ func StreamListPage(qw422016 *qt422016.Writer, data *ListData) {
 				sb.WriteString(`<span class="inline-flex items-center rounded-md px-2 py-1 text-xs font-medium ring-1 ring-inset ring-gray-500/10 dark:ring-gray-400/20 `)
 				sb.WriteString(statusClass)
 				sb.WriteString(`">`)
-				sb.WriteString(item.Status)
+				sb.WriteString(html.EscapeString(item.Status))
 				sb.WriteString(`</span></td>
                         <td class="whitespace-nowrap px-3 py-4 text-sm text-gray-500 dark:text-gray-400">`)
 				sb.WriteString(item.UpdatedAt.Format("2006-01-02 15:04"))
 				sb.WriteString(`</td>
                         <td class="relative whitespace-nowrap py-4 pl-3 pr-4 text-right text-sm font-medium sm:pr-6">
                             <a href="/content/`)
-				sb.WriteString(item.ID)
+				sb.WriteString(html.EscapeString(item.ID))
 				sb.WriteString(`/edit" class="text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">Edit<span class="sr-only">, `)
-				sb.WriteString(item.Title)
+				sb.WriteString(html.EscapeString(item.Title))
 				sb.WriteString(`</span></a>
                         </td>
                     </tr>`)
@@ -107,44 +189,175 @@ func StreamListPage(qw422016 *qt422016.Writer, data *ListData) {
                             </div>
                         </div>
                     </div>
-                </div>
-            </div>`)
+                </div>`)
+		if data.FirstURL != "" || data.NextURL != "" {
+			sb.WriteString(`<nav class="mt-4 flex items-center justify-between text-sm">`)
+			if data.FirstURL != "" {
+				sb.WriteString(`<a href="` + html.EscapeString(data.FirstURL) + `" class="text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">&larr; First page</a>`)
+			} else {
+				sb.WriteString(`<span></span>`)
+			}
+			if data.NextURL != "" {
+				sb.WriteString(`<a href="` + html.EscapeString(data.NextURL) + `" class="text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">Next page &rarr;</a>`)
+			}
+			sb.WriteString(`</nav>`)
+		}
+		sb.WriteString(`
+            </div>
+
+            <script>
+                function bulkActions(items) {
+                    return {
+                        items: items, // ID -> {title, etag}
+                        selected: [],
+                        status: '`)
+		if len(data.Statuses) > 0 {
+			sb.WriteString(html.EscapeString(data.Statuses[0]))
+		}
+		sb.WriteString(`',
+                        comment: '',
+                        loading: false,
+                        error: '',
+
+                        csrfToken() {
+                            // Double-submit the CSRF cookie issued with this page
+                            return (document.cookie.match(/(?:^|; )csrf_token=([^;]*)/) || [])[1] || '';
+                        },
+
+                        changeStatus() {
+                            this.run(this.selected.map(id => ({ op: 'status', id: id, if_match: this.items[id].etag, status: this.status, comment: this.comment })), 'bulk-status');
+                        },
+
+                        trash() {
+                            if (!confirm('Move ' + this.selected.length + ' items to the trash?')) return;
+                            this.run(this.selected.map(id => ({ op: 'delete', id: id, if_match: this.items[id].etag })), 'bulk-trashed');
+                        },
+
+                        // run sends the operations as one batch; if any fails, none is saved
+                        async run(operations, message) {
+                            this.loading = true;
+                            this.error = '';
+                            try {
+                                const response = await fetch('/api/content/_bulk', {
+                                    method: 'POST',
+                                    headers: {
+                                        'Content-Type': 'application/json',
+                                        'X-CSRF-Token': this.csrfToken(),
+                                    },
+                                    body: JSON.stringify({ operations: operations })
+                                });
+                                if (response.ok) {
+                                    window.location.href = '/content?message=' + message;
+                                    return;
+                                }
+                                const type = response.headers.get('Content-Type') || '';
+                                if (!type.includes('json')) throw new Error(await response.text());
+                                const problem = await response.json();
+                                const failed = (problem.results || []).find(r => r.status !== 424);
+                                if (!failed) throw new Error(problemMessage(problem));
+                                const item = this.items[failed.id];
+                                throw new Error((item ? item.title : 'An item') + ': ' + failed.error + '. Nothing was changed.');
+                            } catch (error) {
+                                this.error = error.message;
+                            } finally {
+                                this.loading = false;
+                            }
+                        }
+                    }
+                }
+            </script>`)
 		return sb.String()
 	}
 
-//line internal/templates/pages/list.qtpl:87
+//line internal/templates/pages/list.qtpl:238
 	qw422016.N().S(`
     `)
-//line internal/templates/pages/list.qtpl:88
+//line internal/templates/pages/list.qtpl:239
 	qw422016.N().S(layouts.BaseLayout(data, pageContent))
-//line internal/templates/pages/list.qtpl:88
+//line internal/templates/pages/list.qtpl:239
 	qw422016.N().S(`
 `)
-//line internal/templates/pages/list.qtpl:89
+//line internal/templates/pages/list.qtpl:240
 }
 
-//line internal/templates/pages/list.qtpl:89
+//line internal/templates/pages/list.qtpl:240
 func WriteListPage(qq422016 qtio422016.Writer, data *ListData) {
-//line internal/templates/pages/list.qtpl:89
+//line internal/templates/pages/list.qtpl:240
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line internal/templates/pages/list.qtpl:89
+//line internal/templates/pages/list.qtpl:240
 	StreamListPage(qw422016, data)
-//line internal/templates/pages/list.qtpl:89
+//line internal/templates/pages/list.qtpl:240
 	qt422016.ReleaseWriter(qw422016)
-//line internal/templates/pages/list.qtpl:89
+//line internal/templates/pages/list.qtpl:240
 }
 
-//line internal/templates/pages/list.qtpl:89
+//line internal/templates/pages/list.qtpl:240
 func ListPage(data *ListData) string {
-//line internal/templates/pages/list.qtpl:89
+//line internal/templates/pages/list.qtpl:240
 	qb422016 := qt422016.AcquireByteBuffer()
-//line internal/templates/pages/list.qtpl:89
+//line internal/templates/pages/list.qtpl:240
 	WriteListPage(qb422016, data)
-//line internal/templates/pages/list.qtpl:89
+//line internal/templates/pages/list.qtpl:240
 	qs422016 := string(qb422016.B)
-//line internal/templates/pages/list.qtpl:89
+//line internal/templates/pages/list.qtpl:240
 	qt422016.ReleaseByteBuffer(qb422016)
-//line internal/templates/pages/list.qtpl:89
+//line internal/templates/pages/list.qtpl:240
 	return qs422016
-//line internal/templates/pages/list.qtpl:89
+//line internal/templates/pages/list.qtpl:240
+}
+
+//line internal/templates/pages/list.qtpl:242
+// listFilterForm renders the filter and sort controls of the list page.
+func listFilterForm(f models.ListFilter, statuses []string, categories []models.TermEntry) string {
+	var sb strings.Builder
+	inputClass := `px-3 py-1.5 border border-gray-300 dark:border-gray-600 rounded-md bg-white dark:bg-gray-700 text-sm text-gray-900 dark:text-gray-100`
+	option := func(value, label, selected string) {
+		sb.WriteString(`<option value="` + html.EscapeString(value) + `"`)
+		if value == selected {
+			sb.WriteString(` selected`)
+		}
+		sb.WriteString(`>` + html.EscapeString(label) + `</option>`)
+	}
+
+	sb.WriteString(`<form method="GET" action="/content" class="mt-6 flex flex-wrap items-end gap-3 text-sm text-gray-700 dark:text-gray-300">
+            <label class="flex flex-col gap-1">Search<input type="search" name="q" value="`)
+	sb.WriteString(html.EscapeString(f.Search))
+	sb.WriteString(`" placeholder="Title, slug or text" class="` + inputClass + `"></label>
+            <label class="flex flex-col gap-1">Status<select name="status" class="` + inputClass + `">`)
+	option("", "Any", f.Status)
+	for _, status := range statuses {
+		option(status, workflow.StateLabel(status), f.Status)
+	}
+	sb.WriteString(`</select></label>
+            <label class="flex flex-col gap-1">Tag<input type="text" name="tag" value="`)
+	sb.WriteString(html.EscapeString(f.Tag))
+	sb.WriteString(`" placeholder="Slug" class="` + inputClass + `"></label>`)
+	if len(categories) > 0 {
+		sb.WriteString(`<label class="flex flex-col gap-1">Category<select name="category" class="` + inputClass + `">`)
+		option("", "Any", f.Category)
+		for _, entry := range categories {
+			option(entry.Term.Slug, strings.Repeat("\u00a0\u00a0", entry.Depth)+entry.Term.Name, f.Category)
+		}
+		sb.WriteString(`</select></label>`)
+	}
+	sb.WriteString(`
+            <label class="flex flex-col gap-1">Created after<input type="date" name="created_after" value="`)
+	sb.WriteString(html.EscapeString(f.CreatedAfter))
+	sb.WriteString(`" class="` + inputClass + `"></label>
+            <label class="flex flex-col gap-1">Created before<input type="date" name="created_before" value="`)
+	sb.WriteString(html.EscapeString(f.CreatedBefore))
+	sb.WriteString(`" class="` + inputClass + `"></label>
+            <label class="flex flex-col gap-1">Sort<select name="sort" class="` + inputClass + `">`)
+	option("", "Recently updated", f.Sort)
+	option("updated_at", "Least recently updated", f.Sort)
+	option("-created_at", "Newest", f.Sort)
+	option("created_at", "Oldest", f.Sort)
+	option("-published_at", "Recently published", f.Sort)
+	option("title", "Title A-Z", f.Sort)
+	option("-title", "Title Z-A", f.Sort)
+	sb.WriteString(`</select></label>
+            <button type="submit" class="px-3 py-1.5 rounded-md bg-indigo-600 text-white hover:bg-indigo-700">Apply</button>
+            <a href="/content" class="px-1 py-1.5 text-gray-600 hover:text-gray-900 dark:text-gray-400 dark:hover:text-gray-200">Reset</a>
+        </form>`)
+	return sb.String()
 }
@@ -11,30 +11,33 @@ import "cms/internal/models"
 import "cms/internal/templates/layouts"
 
 //line internal/templates/pages/login.qtpl:3
+import "html"
+
+//line internal/templates/pages/login.qtpl:4
 import "strings"
 
-//line internal/templates/pages/login.qtpl:5
+//line internal/templates/pages/login.qtpl:6
 import (
 	qtio422016 "io"
 
 	qt422016 "github.com/valyala/quicktemplate"
 )
 
-//line internal/templates/pages/login.qtpl:5
+//line internal/templates/pages/login.qtpl:6
 var (
 	_ = qtio422016.Copy
 	_ = qt422016.AcquireByteBuffer
 )
 
-//line internal/templates/pages/login.qtpl:5
+//line internal/templates/pages/login.qtpl:6
 type LoginData = models.LoginData
 
-//line internal/templates/pages/login.qtpl:7
+//line internal/templates/pages/login.qtpl:8
 func StreamLoginPage(qw422016 *qt422016.Writer, data *LoginData) {
-//line internal/templates/pages/login.qtpl:7
+//line internal/templates/pages/login.qtpl:8
 	qw422016.N().S(`
     `)
-//line internal/templates/pages/login.qtpl:9
+//line internal/templates/pages/login.qtpl:10
 	pageContent := func() string {
 		var sb strings.Builder
 		sb.WriteString(`<div class="flex min-h-full flex-col justify-center px-6 py-12 lg:px-8">`)
@@ -46,6 +49,9 @@ func StreamLoginPage(qw422016 *qt422016.Writer, data *LoginData) {
 
 		sb.WriteString(`<div class="mt-10 sm:mx-auto sm:w-full sm:max-w-sm">`)
 		sb.WriteString(`<form class="space-y-6" action="/login" method="POST">`)
+		sb.WriteString(`<input type="hidden" name="csrf_token" value="`)
+		sb.WriteString(data.CSRFToken())
+		sb.WriteString(`">`)
 		// Username field
 		sb.WriteString(`<div>`)
 		sb.WriteString(`<label for="username" class="block text-sm font-medium leading-6 text-gray-900 dark:text-white">Username</label>`)
@@ -74,7 +80,7 @@ func StreamLoginPage(qw422016 *qt422016.Writer, data *LoginData) {
 			sb.WriteString(`</div>`)
 			sb.WriteString(`<div class="ml-3">`)
 			sb.WriteString(`<p class="text-sm font-medium text-yellow-800 dark:text-yellow-300">`)
-			sb.WriteString(data.LockoutMessage)
+			sb.WriteString(html.EscapeString(data.LockoutMessage))
 			sb.WriteString(`</p>`)
 			sb.WriteString(`</div>`)
 			sb.WriteString(`</div>`)
@@ -91,7 +97,7 @@ func StreamLoginPage(qw422016 *qt422016.Writer, data *LoginData) {
 			sb.WriteString(`</div>`)
 			sb.WriteString(`<div class="ml-3">`)
 			sb.WriteString(`<p class="text-sm font-medium text-red-800 dark:text-red-300">`)
-			sb.WriteString(data.ErrorMessage)
+			sb.WriteString(html.EscapeString(data.ErrorMessage))
 			sb.WriteString(`</p>`)
 			sb.WriteString(`</div>`)
 			sb.WriteString(`</div>`)
@@ -109,39 +115,39 @@ func StreamLoginPage(qw422016 *qt422016.Writer, data *LoginData) {
 		return sb.String()
 	}
 
-//line internal/templates/pages/login.qtpl:82
+//line internal/templates/pages/login.qtpl:86
 	qw422016.N().S(`
     `)
-//line internal/templates/pages/login.qtpl:83
+//line internal/templates/pages/login.qtpl:87
 	qw422016.N().S(layouts.PlainLayout(data, pageContent))
-//line internal/templates/pages/login.qtpl:83
+//line internal/templates/pages/login.qtpl:87
 	qw422016.N().S(`
 `)
-//line internal/templates/pages/login.qtpl:84
+//line internal/templates/pages/login.qtpl:88
 }
 
-//line internal/templates/pages/login.qtpl:84
+//line internal/templates/pages/login.qtpl:88
 func WriteLoginPage(qq422016 qtio422016.Writer, data *LoginData) {
-//line internal/templates/pages/login.qtpl:84
+//line internal/templates/pages/login.qtpl:88
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line internal/templates/pages/login.qtpl:84
+//line internal/templates/pages/login.qtpl:88
 	StreamLoginPage(qw422016, data)
-//line internal/templates/pages/login.qtpl:84
+//line internal/templates/pages/login.qtpl:88
 	qt422016.ReleaseWriter(qw422016)
-//line internal/templates/pages/login.qtpl:84
+//line internal/templates/pages/login.qtpl:88
 }
 
-//line internal/templates/pages/login.qtpl:84
+//line internal/templates/pages/login.qtpl:88
 func LoginPage(data *LoginData) string {
-//line internal/templates/pages/login.qtpl:84
+//line internal/templates/pages/login.qtpl:88
 	qb422016 := qt422016.AcquireByteBuffer()
-//line internal/templates/pages/login.qtpl:84
+//line internal/templates/pages/login.qtpl:88
 	WriteLoginPage(qb422016, data)
-//line internal/templates/pages/login.qtpl:84
+//line internal/templates/pages/login.qtpl:88
 	qs422016 := string(qb422016.B)
-//line internal/templates/pages/login.qtpl:84
+//line internal/templates/pages/login.qtpl:88
 	qt422016.ReleaseByteBuffer(qb422016)
-//line internal/templates/pages/login.qtpl:84
+//line internal/templates/pages/login.qtpl:88
 	return qs422016
-//line internal/templates/pages/login.qtpl:84
+//line internal/templates/pages/login.qtpl:88
 }
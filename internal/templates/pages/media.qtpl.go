@@ -0,0 +1,220 @@
+// Code generated by qtc from "media.qtpl". DO NOT EDIT.
+// See https://github.com/valyala/quicktemplate for details.
+
+//line internal/templates/pages/media.qtpl:1
+package pages
+
+//line internal/templates/pages/media.qtpl:1
+import "cms/internal/media"
+
+//line internal/templates/pages/media.qtpl:2
+import "cms/internal/models"
+
+//line internal/templates/pages/media.qtpl:3
+import "cms/internal/templates/layouts"
+
+//line internal/templates/pages/media.qtpl:4
+import "html"
+
+//line internal/templates/pages/media.qtpl:5
+import "strconv"
+
+//line internal/templates/pages/media.qtpl:6
+import "strings"
+
+//line internal/templates/pages/media.qtpl:8
+import (
+	qtio422016 "io"
+
+	qt422016 "github.com/valyala/quicktemplate"
+)
+
+//line internal/templates/pages/media.qtpl:8
+var (
+	_ = qtio422016.Copy
+	_ = qt422016.AcquireByteBuffer
+)
+
+//line internal/templates/pages/media.qtpl:9
+// MediaData struct is defined in models package
+type MediaData = models.MediaData
+
+// formatBytes renders a file size for people, e.g. "1.5 MB".
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return strconv.FormatFloat(float64(n)/(1<<20), 'f', 1, 64) + " MB"
+	case n >= 1<<10:
+		return strconv.FormatFloat(float64(n)/(1<<10), 'f', 1, 64) + " KB"
+	}
+	return strconv.FormatInt(n, 10) + " bytes"
+}
+
+//line internal/templates/pages/media.qtpl:24
+func StreamMediaPage(qw422016 *qt422016.Writer, data *MediaData) {
+//line internal/templates/pages/media.qtpl:24
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/media.qtpl:26
+	pageContent := func() string {
+		var sb strings.Builder
+		inputClass := `px-3 py-1.5 border border-gray-300 dark:border-gray-600 rounded-md bg-white dark:bg-gray-700 text-sm text-gray-900 dark:text-gray-100`
+		linkClass := `text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300`
+
+		sb.WriteString(`<div class="px-4 sm:px-6 lg:px-8" x-data="mediaLibrary()">
+                <div class="sm:flex sm:items-center">
+                    <div class="sm:flex-auto">
+                        <h1 class="text-2xl font-semibold leading-6 text-gray-900 dark:text-white">Media Library</h1>
+                        <p class="mt-2 text-sm text-gray-700 dark:text-gray-300">Images (JPEG, PNG, GIF, WebP) and PDF documents, up to ` + formatBytes(data.MaxSize) + ` each. Files are public once uploaded.</p>
+                    </div>
+                </div>`)
+		if data.Message != "" {
+			sb.WriteString(`<p class="mt-4 rounded-md bg-green-50 p-3 text-sm text-green-800 dark:bg-green-800/30 dark:text-green-300">`)
+			sb.WriteString(html.EscapeString(data.Message))
+			sb.WriteString(`</p>`)
+		}
+		if data.IsSandbox() {
+			sb.WriteString(sandboxSharedNotice)
+		} else {
+			sb.WriteString(`<form @submit.prevent="upload()" class="mt-6 flex flex-wrap items-end gap-3 text-sm text-gray-700 dark:text-gray-300">
+                    <label class="flex flex-col gap-1">File<input type="file" x-ref="file" multiple required accept="image/jpeg,image/png,image/gif,image/webp,application/pdf" class="text-sm"></label>
+                    <label class="flex flex-col gap-1">Alt text<input type="text" x-model="alt" maxlength="500" placeholder="Describes the image" class="` + inputClass + `"></label>
+                    <button type="submit" :disabled="loading" class="px-3 py-1.5 rounded-md bg-indigo-600 text-white hover:bg-indigo-700 disabled:opacity-50" x-text="loading ? 'Uploading...' : 'Upload'"></button>
+                </form>`)
+		}
+		sb.WriteString(`<p x-show="error" x-text="error" class="mt-4 rounded-md bg-red-50 p-3 text-sm text-red-800 dark:bg-red-800/30 dark:text-red-300"></p>`)
+
+		if len(data.Items) == 0 {
+			sb.WriteString(`<p class="mt-8 text-sm text-gray-500 dark:text-gray-400">No files yet.</p>`)
+		}
+		sb.WriteString(`<ul class="mt-8 grid grid-cols-1 gap-6 sm:grid-cols-2 lg:grid-cols-4">`)
+		for _, m := range data.Items {
+			fileURL := html.EscapeString(m.URL)
+			name := html.EscapeString(m.Filename)
+			sb.WriteString(`<li class="bg-white dark:bg-gray-800 rounded-lg shadow-sm overflow-hidden">
+                    <a href="` + fileURL + `" target="_blank" rel="noopener" class="flex h-40 items-center justify-center bg-gray-100 dark:bg-gray-700">`)
+			if media.IsImage(m.MIME) {
+				sb.WriteString(`<img src="` + fileURL + `?size=thumb" alt="` + html.EscapeString(m.Alt) + `" loading="lazy" class="max-h-40 max-w-full object-contain">`)
+			} else {
+				sb.WriteString(`<span class="text-2xl font-semibold text-gray-500 dark:text-gray-400">PDF</span>`)
+			}
+			sb.WriteString(`</a>
+                    <div class="p-4 text-sm">
+                        <p class="font-medium text-gray-900 dark:text-gray-100 truncate" title="` + name + `">` + name + `</p>
+                        <p class="mt-1 text-gray-500 dark:text-gray-400">` + formatBytes(m.Size))
+			if m.Width > 0 {
+				sb.WriteString(` &middot; ` + strconv.Itoa(m.Width) + `&times;` + strconv.Itoa(m.Height))
+			}
+			sb.WriteString(` &middot; ` + html.EscapeString(m.Uploader) + `</p>`)
+			if media.CanResize(m.MIME) {
+				sb.WriteString(`<p class="mt-1 text-gray-500 dark:text-gray-400">Sizes:`)
+				for _, preset := range data.Presets {
+					sb.WriteString(` <a href="` + fileURL + `?size=` + preset + `" target="_blank" rel="noopener" class="` + linkClass + `">` + preset + `</a>`)
+				}
+				sb.WriteString(`</p>`)
+			}
+			sb.WriteString(`<div class="mt-3 flex items-center gap-4">
+                            <button type="button" @click="copyURL('` + fileURL + `')" class="` + linkClass + `">Copy URL</button>`)
+			if data.Deletable[m.ID] {
+				sb.WriteString(`<button type="button" @click="remove('` + html.EscapeString(m.ID) + `')" class="ml-auto text-red-600 hover:text-red-800 dark:text-red-400 dark:hover:text-red-300">Delete</button>`)
+			}
+			sb.WriteString(`</div>
+                    </div>
+                </li>`)
+		}
+		sb.WriteString(`</ul>
+            </div>
+
+            <script>
+                function mediaLibrary() {
+                    return {
+                        alt: '',
+                        loading: false,
+                        error: '',
+
+                        csrfToken() {
+                            // Double-submit the CSRF cookie issued with this page
+                            return (document.cookie.match(/(?:^|; )csrf_token=([^;]*)/) || [])[1] || '';
+                        },
+
+                        async upload() {
+                            this.loading = true;
+                            this.error = '';
+                            try {
+                                for (const file of this.$refs.file.files) {
+                                    const body = new FormData();
+                                    body.append('file', file);
+                                    body.append('alt', this.alt);
+                                    const response = await fetch('/api/media', {
+                                        method: 'POST',
+                                        headers: { 'X-CSRF-Token': this.csrfToken() },
+                                        body: body
+                                    });
+                                    if (!response.ok) throw new Error(file.name + ': ' + await responseError(response));
+                                }
+                                window.location.href = '/media?message=uploaded';
+                            } catch (error) {
+                                this.error = error.message;
+                            } finally {
+                                this.loading = false;
+                            }
+                        },
+
+                        async remove(id) {
+                            if (!confirm('Delete this file? Content using it will show a broken link.')) return;
+                            const response = await fetch('/api/media/' + id, {
+                                method: 'DELETE',
+                                headers: { 'X-CSRF-Token': this.csrfToken() }
+                            });
+                            if (!response.ok) {
+                                this.error = 'Error deleting file: ' + await responseError(response);
+                                return;
+                            }
+                            window.location.href = '/media?message=deleted';
+                        },
+
+                        copyURL(path) {
+                            navigator.clipboard.writeText(window.location.origin + path);
+                        }
+                    }
+                }
+            </script>`)
+		return sb.String()
+	}
+
+//line internal/templates/pages/media.qtpl:151
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/media.qtpl:152
+	qw422016.N().S(layouts.BaseLayout(data, pageContent))
+//line internal/templates/pages/media.qtpl:152
+	qw422016.N().S(`
+`)
+//line internal/templates/pages/media.qtpl:153
+}
+
+//line internal/templates/pages/media.qtpl:153
+func WriteMediaPage(qq422016 qtio422016.Writer, data *MediaData) {
+//line internal/templates/pages/media.qtpl:153
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/templates/pages/media.qtpl:153
+	StreamMediaPage(qw422016, data)
+//line internal/templates/pages/media.qtpl:153
+	qt422016.ReleaseWriter(qw422016)
+//line internal/templates/pages/media.qtpl:153
+}
+
+//line internal/templates/pages/media.qtpl:153
+func MediaPage(data *MediaData) string {
+//line internal/templates/pages/media.qtpl:153
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/templates/pages/media.qtpl:153
+	WriteMediaPage(qb422016, data)
+//line internal/templates/pages/media.qtpl:153
+	qs422016 := string(qb422016.B)
+//line internal/templates/pages/media.qtpl:153
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/templates/pages/media.qtpl:153
+	return qs422016
+//line internal/templates/pages/media.qtpl:153
+}
@@ -0,0 +1,304 @@
+// Code generated by qtc from "public.qtpl". DO NOT EDIT.
+// See https://github.com/valyala/quicktemplate for details.
+
+//line internal/templates/pages/public.qtpl:1
+package pages
+
+//line internal/templates/pages/public.qtpl:1
+import "cms/internal/models"
+
+//line internal/templates/pages/public.qtpl:2
+import "cms/internal/templates/layouts"
+
+//line internal/templates/pages/public.qtpl:3
+import "html"
+
+//line internal/templates/pages/public.qtpl:4
+import "strings"
+
+//line internal/templates/pages/public.qtpl:6
+import (
+	qtio422016 "io"
+
+	qt422016 "github.com/valyala/quicktemplate"
+)
+
+//line internal/templates/pages/public.qtpl:6
+var (
+	_ = qtio422016.Copy
+	_ = qt422016.AcquireByteBuffer
+)
+
+//line internal/templates/pages/public.qtpl:7
+// PublicIndexData and PublicPostData structs are defined in models package
+type PublicIndexData = models.PublicIndexData
+type PublicPostData = models.PublicPostData
+type TermArchiveData = models.TermArchiveData
+
+//line internal/templates/pages/public.qtpl:13
+func StreamPublicIndexPage(qw422016 *qt422016.Writer, data *PublicIndexData) {
+//line internal/templates/pages/public.qtpl:13
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/public.qtpl:15
+	pageContent := func() string {
+		var sb strings.Builder
+		sb.WriteString(`<div class="px-4 sm:px-6 lg:px-8 max-w-3xl mx-auto">
+                <h1 class="text-3xl font-bold tracking-tight text-gray-900 dark:text-white">`)
+		sb.WriteString(html.EscapeString(data.Title()))
+		sb.WriteString(`</h1>`)
+		if len(data.Items) == 0 {
+			sb.WriteString(`<p class="mt-8 text-gray-500 dark:text-gray-400">Nothing has been published yet.</p>`)
+		}
+		sb.WriteString(postList(data.Prefix, data.Items))
+		sb.WriteString(postPager(data.FirstURL, data.NextURL))
+		sb.WriteString(`</div>`)
+		return sb.String()
+	}
+
+//line internal/templates/pages/public.qtpl:29
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/public.qtpl:30
+	qw422016.N().S(layouts.BaseLayout(data, pageContent))
+//line internal/templates/pages/public.qtpl:30
+	qw422016.N().S(`
+`)
+//line internal/templates/pages/public.qtpl:31
+}
+
+//line internal/templates/pages/public.qtpl:31
+func WritePublicIndexPage(qq422016 qtio422016.Writer, data *PublicIndexData) {
+//line internal/templates/pages/public.qtpl:31
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/templates/pages/public.qtpl:31
+	StreamPublicIndexPage(qw422016, data)
+//line internal/templates/pages/public.qtpl:31
+	qt422016.ReleaseWriter(qw422016)
+//line internal/templates/pages/public.qtpl:31
+}
+
+//line internal/templates/pages/public.qtpl:31
+func PublicIndexPage(data *PublicIndexData) string {
+//line internal/templates/pages/public.qtpl:31
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/templates/pages/public.qtpl:31
+	WritePublicIndexPage(qb422016, data)
+//line internal/templates/pages/public.qtpl:31
+	qs422016 := string(qb422016.B)
+//line internal/templates/pages/public.qtpl:31
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/templates/pages/public.qtpl:31
+	return qs422016
+//line internal/templates/pages/public.qtpl:31
+}
+
+//line internal/templates/pages/public.qtpl:33
+func StreamPublicPostPage(qw422016 *qt422016.Writer, data *PublicPostData) {
+//line internal/templates/pages/public.qtpl:33
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/public.qtpl:35
+	pageContent := func() string {
+		var sb strings.Builder
+		sb.WriteString(`<article class="prose dark:prose-invert prose-indigo lg:prose-lg mx-auto bg-white dark:bg-gray-800 p-6 md:p-8 rounded-lg shadow-md">
+                <h1>`)
+		sb.WriteString(html.EscapeString(data.Item.Title))
+		sb.WriteString(`</h1>
+                <p class="text-sm text-gray-500 dark:text-gray-400">`)
+		sb.WriteString(postByline(data.Item))
+		sb.WriteString(`</p>
+                <div class="mt-6">
+                    `)
+		// Body is rendered to sanitized HTML by the handler
+		sb.WriteString(data.Body)
+		sb.WriteString(`
+                </div>`)
+		if len(data.Categories) > 0 || len(data.Tags) > 0 {
+			sb.WriteString(`<div class="mt-8 not-prose flex flex-wrap gap-2 text-sm">`)
+			sb.WriteString(termLinks(data.Prefix+"/category/", data.Categories))
+			sb.WriteString(termLinks(data.Prefix+"/tag/", data.Tags))
+			sb.WriteString(`</div>`)
+		}
+		sb.WriteString(`
+                <div class="mt-8 border-t border-gray-200 dark:border-gray-700 pt-6">
+                    <a href="`)
+		sb.WriteString(html.EscapeString(data.Prefix))
+		sb.WriteString(`" class="text-gray-600 hover:text-gray-900 dark:text-gray-400 dark:hover:text-gray-200">&larr; All posts</a>
+                </div>
+            </article>`)
+		return sb.String()
+	}
+
+//line internal/templates/pages/public.qtpl:65
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/public.qtpl:66
+	qw422016.N().S(layouts.BaseLayout(data, pageContent))
+//line internal/templates/pages/public.qtpl:66
+	qw422016.N().S(`
+`)
+//line internal/templates/pages/public.qtpl:67
+}
+
+//line internal/templates/pages/public.qtpl:67
+func WritePublicPostPage(qq422016 qtio422016.Writer, data *PublicPostData) {
+//line internal/templates/pages/public.qtpl:67
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/templates/pages/public.qtpl:67
+	StreamPublicPostPage(qw422016, data)
+//line internal/templates/pages/public.qtpl:67
+	qt422016.ReleaseWriter(qw422016)
+//line internal/templates/pages/public.qtpl:67
+}
+
+//line internal/templates/pages/public.qtpl:67
+func PublicPostPage(data *PublicPostData) string {
+//line internal/templates/pages/public.qtpl:67
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/templates/pages/public.qtpl:67
+	WritePublicPostPage(qb422016, data)
+//line internal/templates/pages/public.qtpl:67
+	qs422016 := string(qb422016.B)
+//line internal/templates/pages/public.qtpl:67
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/templates/pages/public.qtpl:67
+	return qs422016
+//line internal/templates/pages/public.qtpl:67
+}
+
+//line internal/templates/pages/public.qtpl:69
+func StreamTermArchivePage(qw422016 *qt422016.Writer, data *TermArchiveData) {
+//line internal/templates/pages/public.qtpl:69
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/public.qtpl:71
+	pageContent := func() string {
+		var sb strings.Builder
+		noun := "Tag"
+		if data.Taxonomy == models.TaxonomyCategories {
+			noun = "Category"
+		}
+		sb.WriteString(`<div class="px-4 sm:px-6 lg:px-8 max-w-3xl mx-auto">
+                <p class="text-sm font-medium text-gray-500 dark:text-gray-400">` + noun + `</p>
+                <h1 class="text-3xl font-bold tracking-tight text-gray-900 dark:text-white">`)
+		sb.WriteString(html.EscapeString(data.Term.Name))
+		sb.WriteString(`</h1>`)
+		if data.Term.Description != "" {
+			sb.WriteString(`<p class="mt-2 text-gray-700 dark:text-gray-300">`)
+			sb.WriteString(html.EscapeString(data.Term.Description))
+			sb.WriteString(`</p>`)
+		}
+		if len(data.Children) > 0 {
+			sb.WriteString(`<div class="mt-4 flex flex-wrap gap-2 text-sm"><span class="text-gray-500 dark:text-gray-400">Subcategories:</span>`)
+			sb.WriteString(termLinks(data.Prefix+"/category/", data.Children))
+			sb.WriteString(`</div>`)
+		}
+		if len(data.Items) == 0 {
+			sb.WriteString(`<p class="mt-8 text-gray-500 dark:text-gray-400">Nothing has been published here yet.</p>`)
+		}
+		sb.WriteString(postList(data.Prefix, data.Items))
+		sb.WriteString(postPager(data.FirstURL, data.NextURL))
+		sb.WriteString(`<div class="mt-8"><a href="` + html.EscapeString(data.Prefix) + `" class="text-sm text-gray-600 hover:text-gray-900 dark:text-gray-400 dark:hover:text-gray-200">&larr; All posts</a></div>
+            </div>`)
+		return sb.String()
+	}
+
+//line internal/templates/pages/public.qtpl:101
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/public.qtpl:102
+	qw422016.N().S(layouts.BaseLayout(data, pageContent))
+//line internal/templates/pages/public.qtpl:102
+	qw422016.N().S(`
+`)
+//line internal/templates/pages/public.qtpl:103
+}
+
+//line internal/templates/pages/public.qtpl:103
+func WriteTermArchivePage(qq422016 qtio422016.Writer, data *TermArchiveData) {
+//line internal/templates/pages/public.qtpl:103
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/templates/pages/public.qtpl:103
+	StreamTermArchivePage(qw422016, data)
+//line internal/templates/pages/public.qtpl:103
+	qt422016.ReleaseWriter(qw422016)
+//line internal/templates/pages/public.qtpl:103
+}
+
+//line internal/templates/pages/public.qtpl:103
+func TermArchivePage(data *TermArchiveData) string {
+//line internal/templates/pages/public.qtpl:103
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/templates/pages/public.qtpl:103
+	WriteTermArchivePage(qb422016, data)
+//line internal/templates/pages/public.qtpl:103
+	qs422016 := string(qb422016.B)
+//line internal/templates/pages/public.qtpl:103
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/templates/pages/public.qtpl:103
+	return qs422016
+//line internal/templates/pages/public.qtpl:103
+}
+
+//line internal/templates/pages/public.qtpl:106
+// postList renders the linked titles and bylines of posts.
+func postList(prefix string, items []models.Content) string {
+	var sb strings.Builder
+	sb.WriteString(`<ul class="mt-8 space-y-6">`)
+	for _, item := range items {
+		sb.WriteString(`<li class="bg-white dark:bg-gray-800 p-6 rounded-lg shadow-sm">
+                <a href="`)
+		sb.WriteString(html.EscapeString(prefix + "/" + item.Slug))
+		sb.WriteString(`" class="text-xl font-semibold text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">`)
+		sb.WriteString(html.EscapeString(item.Title))
+		sb.WriteString(`</a>
+                <p class="mt-1 text-sm text-gray-500 dark:text-gray-400">`)
+		sb.WriteString(postByline(item))
+		sb.WriteString(`</p>
+            </li>`)
+	}
+	sb.WriteString(`</ul>`)
+	return sb.String()
+}
+
+// postPager renders the links to the first and next page of posts,
+// if there are any.
+func postPager(firstURL, nextURL string) string {
+	if firstURL == "" && nextURL == "" {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(`<nav class="mt-6 flex items-center justify-between text-sm">`)
+	if firstURL != "" {
+		sb.WriteString(`<a href="` + html.EscapeString(firstURL) + `" class="text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">&larr; Newest posts</a>`)
+	} else {
+		sb.WriteString(`<span></span>`)
+	}
+	if nextURL != "" {
+		sb.WriteString(`<a href="` + html.EscapeString(nextURL) + `" class="text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">Older posts &rarr;</a>`)
+	}
+	sb.WriteString(`</nav>`)
+	return sb.String()
+}
+
+// termLinks renders terms as pills linking to their archives under base.
+func termLinks(base string, terms []models.Term) string {
+	var sb strings.Builder
+	for _, term := range terms {
+		sb.WriteString(`<a href="` + html.EscapeString(base+term.Slug) + `" class="rounded-full bg-indigo-50 px-3 py-1 text-indigo-700 hover:bg-indigo-100 dark:bg-indigo-900/40 dark:text-indigo-300">`)
+		sb.WriteString(html.EscapeString(term.Name))
+		sb.WriteString(`</a>`)
+	}
+	return sb.String()
+}
+
+// postByline renders the publish date and author of a post.
+func postByline(item models.Content) string {
+	t := item.PublishTime()
+	byline := `<time datetime="` + t.UTC().Format("2006-01-02T15:04:05Z") + `">` + t.Format("January 2, 2006") + `</time>`
+	if item.Author != "" {
+		byline += " by " + html.EscapeString(item.Author)
+	}
+	return byline
+}
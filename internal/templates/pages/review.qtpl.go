@@ -0,0 +1,172 @@
+// Code generated by qtc from "review.qtpl". DO NOT EDIT.
+// See https://github.com/valyala/quicktemplate for details.
+
+//line internal/templates/pages/review.qtpl:1
+package pages
+
+//line internal/templates/pages/review.qtpl:1
+import "cms/internal/models"
+
+//line internal/templates/pages/review.qtpl:2
+import "cms/internal/templates/layouts"
+
+//line internal/templates/pages/review.qtpl:3
+import "cms/internal/workflow"
+
+//line internal/templates/pages/review.qtpl:4
+import "html"
+
+//line internal/templates/pages/review.qtpl:5
+import "strings"
+
+//line internal/templates/pages/review.qtpl:7
+import (
+	qtio422016 "io"
+
+	qt422016 "github.com/valyala/quicktemplate"
+)
+
+//line internal/templates/pages/review.qtpl:7
+var (
+	_ = qtio422016.Copy
+	_ = qt422016.AcquireByteBuffer
+)
+
+//line internal/templates/pages/review.qtpl:8
+// ReviewData struct is defined in models package
+type ReviewData = models.ReviewData
+
+//line internal/templates/pages/review.qtpl:12
+func StreamReviewPage(qw422016 *qt422016.Writer, data *ReviewData) {
+//line internal/templates/pages/review.qtpl:12
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/review.qtpl:14
+	pageContent := func() string {
+		var sb strings.Builder
+		sb.WriteString(`<div class="px-4 sm:px-6 lg:px-8">
+                <div class="sm:flex sm:items-center">
+                    <div class="sm:flex-auto">
+                        <h1 class="text-2xl font-semibold leading-6 text-gray-900 dark:text-white">Review Queue</h1>
+                        <p class="mt-2 text-sm text-gray-700 dark:text-gray-300">Items submitted for review, oldest first.</p>
+                    </div>
+                    <div class="mt-4 sm:ml-16 sm:mt-0 sm:flex-none">
+                        <a href="/content" class="text-sm font-semibold text-gray-600 hover:text-gray-900 dark:text-gray-400 dark:hover:text-gray-200">Back to list</a>
+                    </div>
+                </div>`)
+
+		if data.Message != "" {
+			sb.WriteString(`<p class="mt-4 rounded-md bg-green-50 p-3 text-sm text-green-800 dark:bg-green-800/30 dark:text-green-300">`)
+			sb.WriteString(html.EscapeString(data.Message))
+			sb.WriteString(`</p>`)
+		}
+
+		sb.WriteString(`
+                <div class="mt-8 overflow-hidden shadow ring-1 ring-black ring-opacity-5 dark:ring-white dark:ring-opacity-10 sm:rounded-lg">
+                    <table class="min-w-full divide-y divide-gray-300 dark:divide-gray-700">
+                        <thead class="bg-gray-50 dark:bg-gray-700">
+                            <tr>
+                                <th scope="col" class="py-3.5 pl-4 pr-3 text-left text-sm font-semibold text-gray-900 dark:text-gray-100 sm:pl-6">Title</th>
+                                <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Author</th>
+                                <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Status</th>
+                                <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Submitted</th>
+                                <th scope="col" class="relative py-3.5 pl-3 pr-4 sm:pr-6"><span class="sr-only">Actions</span></th>
+                            </tr>
+                        </thead>
+                        <tbody class="divide-y divide-gray-200 dark:divide-gray-700 bg-white dark:bg-gray-800">`)
+
+		if len(data.Entries) == 0 {
+			sb.WriteString(`<tr><td colspan="5" class="whitespace-nowrap py-4 pl-4 pr-3 text-sm text-gray-500 dark:text-gray-400 sm:pl-6">Nothing is waiting for review.</td></tr>`)
+		}
+		for _, entry := range data.Entries {
+			sb.WriteString(`<tr class="hover:bg-gray-50 dark:hover:bg-gray-700">
+                    <td class="whitespace-nowrap py-4 pl-4 pr-3 text-sm font-medium text-gray-900 dark:text-gray-100 sm:pl-6"><a href="/content/`)
+			sb.WriteString(html.EscapeString(entry.Item.ID))
+			sb.WriteString(`" class="text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">`)
+			sb.WriteString(html.EscapeString(entry.Item.Title))
+			sb.WriteString(`</a></td>
+                    <td class="whitespace-nowrap px-3 py-4 text-sm text-gray-500 dark:text-gray-400">`)
+			sb.WriteString(html.EscapeString(entry.Item.Author))
+			sb.WriteString(`</td>
+                    <td class="whitespace-nowrap px-3 py-4 text-sm text-gray-500 dark:text-gray-400">`)
+			sb.WriteString(html.EscapeString(workflow.StateLabel(entry.Item.Status)))
+			sb.WriteString(`</td>
+                    <td class="whitespace-nowrap px-3 py-4 text-sm text-gray-500 dark:text-gray-400">`)
+			sb.WriteString(entry.Item.UpdatedAt.Format("2006-01-02 15:04"))
+			sb.WriteString(`</td>
+                    <td class="py-4 pl-3 pr-4 text-right text-sm font-medium sm:pr-6">`)
+			sb.WriteString(transitionForms(entry.Item.ID, entry.Transitions, data.CSRFToken(), "review"))
+			sb.WriteString(`</td>
+                </tr>`)
+		}
+
+		sb.WriteString(`
+                        </tbody>
+                    </table>
+                </div>
+            </div>`)
+		return sb.String()
+	}
+
+//line internal/templates/pages/review.qtpl:79
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/review.qtpl:80
+	qw422016.N().S(layouts.BaseLayout(data, pageContent))
+//line internal/templates/pages/review.qtpl:80
+	qw422016.N().S(`
+`)
+//line internal/templates/pages/review.qtpl:81
+}
+
+//line internal/templates/pages/review.qtpl:81
+func WriteReviewPage(qq422016 qtio422016.Writer, data *ReviewData) {
+//line internal/templates/pages/review.qtpl:81
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/templates/pages/review.qtpl:81
+	StreamReviewPage(qw422016, data)
+//line internal/templates/pages/review.qtpl:81
+	qt422016.ReleaseWriter(qw422016)
+//line internal/templates/pages/review.qtpl:81
+}
+
+//line internal/templates/pages/review.qtpl:81
+func ReviewPage(data *ReviewData) string {
+//line internal/templates/pages/review.qtpl:81
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/templates/pages/review.qtpl:81
+	WriteReviewPage(qb422016, data)
+//line internal/templates/pages/review.qtpl:81
+	qs422016 := string(qb422016.B)
+//line internal/templates/pages/review.qtpl:81
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/templates/pages/review.qtpl:81
+	return qs422016
+//line internal/templates/pages/review.qtpl:81
+}
+
+//line internal/templates/pages/review.qtpl:83
+// transitionForms renders one form per workflow transition, posting to
+// /api/content/{id}/status. Transitions that need a comment get a
+// required comment field. next is passed on to choose the redirect.
+func transitionForms(id string, transitions []workflow.Transition, csrfToken, next string) string {
+	var sb strings.Builder
+	id = html.EscapeString(id)
+	sb.WriteString(`<div class="flex flex-wrap items-start justify-end gap-2">`)
+	for _, t := range transitions {
+		sb.WriteString(`<form method="POST" action="/api/content/` + id + `/status" class="flex items-center gap-2">`)
+		sb.WriteString(`<input type="hidden" name="csrf_token" value="` + csrfToken + `">`)
+		sb.WriteString(`<input type="hidden" name="status" value="` + html.EscapeString(t.To) + `">`)
+		if next != "" {
+			sb.WriteString(`<input type="hidden" name="next" value="` + html.EscapeString(next) + `">`)
+		}
+		if t.Comment {
+			sb.WriteString(`<input type="text" name="comment" required placeholder="Comment" class="px-2 py-1 border border-gray-300 dark:border-gray-600 rounded-md bg-white dark:bg-gray-700 text-sm text-gray-900 dark:text-gray-100">`)
+		}
+		sb.WriteString(`<button type="submit" class="px-3 py-1 rounded-md bg-indigo-600 text-white text-sm hover:bg-indigo-700">`)
+		sb.WriteString(html.EscapeString(t.Name()))
+		sb.WriteString(`</button></form>`)
+	}
+	sb.WriteString(`</div>`)
+	return sb.String()
+}
@@ -0,0 +1,113 @@
+// Code generated by qtc from "search.qtpl". DO NOT EDIT.
+// See https://github.com/valyala/quicktemplate for details.
+
+//line internal/templates/pages/search.qtpl:1
+package pages
+
+//line internal/templates/pages/search.qtpl:1
+import "cms/internal/models"
+
+//line internal/templates/pages/search.qtpl:2
+import "cms/internal/templates/layouts"
+
+//line internal/templates/pages/search.qtpl:3
+import "html"
+
+//line internal/templates/pages/search.qtpl:4
+import "strings"
+
+//line internal/templates/pages/search.qtpl:6
+import (
+	qtio422016 "io"
+
+	qt422016 "github.com/valyala/quicktemplate"
+)
+
+//line internal/templates/pages/search.qtpl:6
+var (
+	_ = qtio422016.Copy
+	_ = qt422016.AcquireByteBuffer
+)
+
+//line internal/templates/pages/search.qtpl:7
+// SearchData struct is defined in models package
+type SearchData = models.SearchData
+
+//line internal/templates/pages/search.qtpl:11
+func StreamSearchPage(qw422016 *qt422016.Writer, data *SearchData) {
+//line internal/templates/pages/search.qtpl:11
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/search.qtpl:13
+	pageContent := func() string {
+		var sb strings.Builder
+		sb.WriteString(`<div class="px-4 sm:px-6 lg:px-8 max-w-3xl mx-auto">
+                <h1 class="text-2xl font-semibold leading-6 text-gray-900 dark:text-white">Search</h1>
+                <form action="/search" method="GET" role="search" class="mt-6 flex gap-2">
+                    <input type="search" name="q" value="`)
+		sb.WriteString(html.EscapeString(data.Query))
+		sb.WriteString(`" placeholder="Search content" autofocus class="flex-1 rounded-md border border-gray-300 dark:border-gray-600 bg-white dark:bg-gray-700 px-4 py-2 text-gray-900 dark:text-gray-100 focus:ring-indigo-500 focus:border-indigo-500">
+                    <button type="submit" class="rounded-md bg-indigo-600 px-4 py-2 text-sm font-semibold text-white hover:bg-indigo-500">Search</button>
+                </form>`)
+
+		if data.Query != "" {
+			if len(data.Results) == 0 {
+				sb.WriteString(`<p class="mt-8 text-sm text-gray-500 dark:text-gray-400">No content matches your search.</p>`)
+			}
+			sb.WriteString(`<ul class="mt-8 space-y-6">`)
+			for _, r := range data.Results {
+				sb.WriteString(`<li class="bg-white dark:bg-gray-800 p-4 rounded-lg shadow-sm">
+                        <a href="/content/`)
+				sb.WriteString(html.EscapeString(r.ID))
+				sb.WriteString(`" class="text-lg font-medium text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">`)
+				sb.WriteString(html.EscapeString(r.Title))
+				sb.WriteString(`</a>
+                        <p class="mt-1 text-sm text-gray-700 dark:text-gray-300 [&_mark]:bg-yellow-200 dark:[&_mark]:bg-yellow-600/60">`)
+				// Snippet is escaped by the search index, only <mark> is markup
+				sb.WriteString(r.Snippet)
+				sb.WriteString(`</p>
+                    </li>`)
+			}
+			sb.WriteString(`</ul>`)
+		}
+
+		sb.WriteString(`</div>`)
+		return sb.String()
+	}
+
+//line internal/templates/pages/search.qtpl:48
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/search.qtpl:49
+	qw422016.N().S(layouts.BaseLayout(data, pageContent))
+//line internal/templates/pages/search.qtpl:49
+	qw422016.N().S(`
+`)
+//line internal/templates/pages/search.qtpl:50
+}
+
+//line internal/templates/pages/search.qtpl:50
+func WriteSearchPage(qq422016 qtio422016.Writer, data *SearchData) {
+//line internal/templates/pages/search.qtpl:50
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/templates/pages/search.qtpl:50
+	StreamSearchPage(qw422016, data)
+//line internal/templates/pages/search.qtpl:50
+	qt422016.ReleaseWriter(qw422016)
+//line internal/templates/pages/search.qtpl:50
+}
+
+//line internal/templates/pages/search.qtpl:50
+func SearchPage(data *SearchData) string {
+//line internal/templates/pages/search.qtpl:50
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/templates/pages/search.qtpl:50
+	WriteSearchPage(qb422016, data)
+//line internal/templates/pages/search.qtpl:50
+	qs422016 := string(qb422016.B)
+//line internal/templates/pages/search.qtpl:50
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/templates/pages/search.qtpl:50
+	return qs422016
+//line internal/templates/pages/search.qtpl:50
+}
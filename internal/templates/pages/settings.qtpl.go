@@ -11,72 +11,178 @@ import "cms/internal/models"
 import "cms/internal/templates/layouts"
 
 //line internal/templates/pages/settings.qtpl:3
+import "html"
+
+//line internal/templates/pages/settings.qtpl:4
 import "strings"
 
-//line internal/templates/pages/settings.qtpl:5
+//line internal/templates/pages/settings.qtpl:6
 import (
 	qtio422016 "io"
 
 	qt422016 "github.com/valyala/quicktemplate"
 )
 
-//line internal/templates/pages/settings.qtpl:5
+//line internal/templates/pages/settings.qtpl:6
 var (
 	_ = qtio422016.Copy
 	_ = qt422016.AcquireByteBuffer
 )
 
-//line internal/templates/pages/settings.qtpl:6
-type SettingsData = models.BasePageData
+//line internal/templates/pages/settings.qtpl:7
+// SettingsData struct is defined in models package
+type SettingsData = models.SettingsData
 
-//line internal/templates/pages/settings.qtpl:9
+//line internal/templates/pages/settings.qtpl:11
 func StreamSettingsPage(qw422016 *qt422016.Writer, data *SettingsData) {
-//line internal/templates/pages/settings.qtpl:9
+//line internal/templates/pages/settings.qtpl:11
 	qw422016.N().S(`
     `)
-//line internal/templates/pages/settings.qtpl:11
+//line internal/templates/pages/settings.qtpl:13
 	pageContent := func() string {
 		var sb strings.Builder
-		sb.WriteString(`<div class="text-center py-16 sm:py-24">
-                    <h1 class="text-4xl font-bold text-gray-700 dark:text-gray-200 mb-4">Settings</h1>
-                    <p class="text-lg text-gray-500 dark:text-gray-400">This page is under construction.</p>
+		sb.WriteString(`<div class="px-4 sm:px-6 lg:px-8 space-y-8">
+                <div>
+                    <h1 class="text-2xl font-semibold leading-6 text-gray-900 dark:text-white">Settings</h1>
+                    <p class="mt-2 text-sm text-gray-700 dark:text-gray-300">Personal access tokens let scripts call the JSON API with <code>Authorization: Bearer &lt;token&gt;</code>.</p>
                 </div>`)
+
+		if data.Message != "" {
+			sb.WriteString(`<p class="rounded-md bg-green-50 p-3 text-sm text-green-800 dark:bg-green-800/30 dark:text-green-300">`)
+			sb.WriteString(html.EscapeString(data.Message))
+			sb.WriteString(`</p>`)
+		}
+		if data.ErrorMessage != "" {
+			sb.WriteString(`<p class="rounded-md bg-red-50 p-3 text-sm text-red-800 dark:bg-red-800/30 dark:text-red-300">`)
+			sb.WriteString(html.EscapeString(data.ErrorMessage))
+			sb.WriteString(`</p>`)
+		}
+		if data.NewToken != "" {
+			sb.WriteString(`<div class="rounded-md border border-indigo-300 dark:border-indigo-600 p-4">
+                    <label for="new-token" class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">New token</label>
+                    <input type="text" id="new-token" readonly onclick="this.select()" value="`)
+			sb.WriteString(html.EscapeString(data.NewToken))
+			sb.WriteString(`" class="block w-full px-4 py-2 font-mono text-sm border border-gray-300 dark:border-gray-600 rounded-md bg-gray-50 dark:bg-gray-700 text-gray-900 dark:text-gray-100">
+                </div>`)
+		}
+
+		sb.WriteString(`
+                <div class="overflow-hidden shadow ring-1 ring-black ring-opacity-5 dark:ring-white dark:ring-opacity-10 sm:rounded-lg">
+                    <table class="min-w-full divide-y divide-gray-300 dark:divide-gray-700">
+                        <thead class="bg-gray-50 dark:bg-gray-700">
+                            <tr>
+                                <th scope="col" class="py-3.5 pl-4 pr-3 text-left text-sm font-semibold text-gray-900 dark:text-gray-100 sm:pl-6">Name</th>
+                                <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Token</th>
+                                <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Scopes</th>
+                                <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Created At</th>
+                                <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Last Used</th>
+                                <th scope="col" class="relative py-3.5 pl-3 pr-4 sm:pr-6"><span class="sr-only">Revoke</span></th>
+                            </tr>
+                        </thead>
+                        <tbody class="divide-y divide-gray-200 dark:divide-gray-700 bg-white dark:bg-gray-800">`)
+
+		if len(data.Tokens) == 0 {
+			sb.WriteString(`<tr><td colspan="6" class="py-4 pl-4 pr-3 text-sm text-gray-500 dark:text-gray-400 sm:pl-6">No API tokens yet.</td></tr>`)
+		}
+		for _, token := range data.Tokens {
+			lastUsed := "Never"
+			if !token.LastUsedAt.IsZero() {
+				lastUsed = token.LastUsedAt.Format("2006-01-02 15:04")
+			}
+			sb.WriteString(`<tr>
+                    <td class="whitespace-nowrap py-4 pl-4 pr-3 text-sm font-medium text-gray-900 dark:text-gray-100 sm:pl-6">`)
+			sb.WriteString(html.EscapeString(token.Name))
+			sb.WriteString(`</td>
+                    <td class="whitespace-nowrap px-3 py-4 font-mono text-sm text-gray-500 dark:text-gray-400">`)
+			sb.WriteString(html.EscapeString(token.Prefix))
+			sb.WriteString(`&hellip;</td>
+                    <td class="px-3 py-4 text-sm text-gray-500 dark:text-gray-400">`)
+			sb.WriteString(html.EscapeString(strings.Join(token.Scopes, ", ")))
+			sb.WriteString(`</td>
+                    <td class="whitespace-nowrap px-3 py-4 text-sm text-gray-500 dark:text-gray-400">`)
+			sb.WriteString(token.CreatedAt.Format("2006-01-02 15:04"))
+			sb.WriteString(`</td>
+                    <td class="whitespace-nowrap px-3 py-4 text-sm text-gray-500 dark:text-gray-400">`)
+			sb.WriteString(lastUsed)
+			sb.WriteString(`</td>
+                    <td class="relative whitespace-nowrap py-4 pl-3 pr-4 text-right text-sm font-medium sm:pr-6">
+                        <form action="/settings/tokens/`)
+			sb.WriteString(html.EscapeString(token.ID))
+			sb.WriteString(`/revoke" method="POST" onsubmit="return confirm('Revoke this token?');">`)
+			sb.WriteString(`<input type="hidden" name="csrf_token" value="`)
+			sb.WriteString(data.CSRFToken())
+			sb.WriteString(`">
+                            <button type="submit" class="text-red-600 hover:text-red-900 dark:text-red-400 dark:hover:text-red-300">Revoke</button>
+                        </form>
+                    </td>
+                </tr>`)
+		}
+
+		sb.WriteString(`
+                        </tbody>
+                    </table>
+                </div>
+
+                <form action="/settings/tokens" method="POST" class="bg-white dark:bg-gray-800 p-6 rounded-lg shadow-md space-y-4 max-w-xl">`)
+		sb.WriteString(`<input type="hidden" name="csrf_token" value="`)
+		sb.WriteString(data.CSRFToken())
+		sb.WriteString(`">`)
+		sb.WriteString(`
+                    <h2 class="text-lg font-semibold text-gray-900 dark:text-white">Create Token</h2>
+                    <div>
+                        <label for="token-name" class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Name</label>
+                        <input type="text" id="token-name" name="name" required maxlength="64" placeholder="e.g. CI deploy" class="block w-full px-4 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 focus:ring-indigo-500 focus:border-indigo-500">
+                    </div>
+                    <fieldset>
+                        <legend class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Scopes</legend>`)
+		for _, scope := range data.Scopes {
+			sb.WriteString(`<label class="flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300"><input type="checkbox" name="scopes" value="`)
+			sb.WriteString(scope)
+			sb.WriteString(`" class="rounded border-gray-300 dark:border-gray-600"> <code>`)
+			sb.WriteString(scope)
+			sb.WriteString(`</code></label>`)
+		}
+		sb.WriteString(`
+                    </fieldset>
+                    <button type="submit" class="inline-flex items-center px-5 py-2.5 border border-transparent text-sm font-medium rounded-md shadow-sm text-white bg-indigo-600 hover:bg-indigo-700 dark:bg-indigo-500 dark:hover:bg-indigo-400">Create Token</button>
+                </form>
+            </div>`)
 		return sb.String()
 	}
 
-//line internal/templates/pages/settings.qtpl:19
+//line internal/templates/pages/settings.qtpl:123
 	qw422016.N().S(`
     `)
-//line internal/templates/pages/settings.qtpl:20
+//line internal/templates/pages/settings.qtpl:124
 	qw422016.N().S(layouts.BaseLayout(data, pageContent))
-//line internal/templates/pages/settings.qtpl:20
+//line internal/templates/pages/settings.qtpl:124
 	qw422016.N().S(`
 `)
-//line internal/templates/pages/settings.qtpl:21
+//line internal/templates/pages/settings.qtpl:125
 }
 
-//line internal/templates/pages/settings.qtpl:21
+//line internal/templates/pages/settings.qtpl:125
 func WriteSettingsPage(qq422016 qtio422016.Writer, data *SettingsData) {
-//line internal/templates/pages/settings.qtpl:21
+//line internal/templates/pages/settings.qtpl:125
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line internal/templates/pages/settings.qtpl:21
+//line internal/templates/pages/settings.qtpl:125
 	StreamSettingsPage(qw422016, data)
-//line internal/templates/pages/settings.qtpl:21
+//line internal/templates/pages/settings.qtpl:125
 	qt422016.ReleaseWriter(qw422016)
-//line internal/templates/pages/settings.qtpl:21
+//line internal/templates/pages/settings.qtpl:125
 }
 
-//line internal/templates/pages/settings.qtpl:21
+//line internal/templates/pages/settings.qtpl:125
 func SettingsPage(data *SettingsData) string {
-//line internal/templates/pages/settings.qtpl:21
+//line internal/templates/pages/settings.qtpl:125
 	qb422016 := qt422016.AcquireByteBuffer()
-//line internal/templates/pages/settings.qtpl:21
+//line internal/templates/pages/settings.qtpl:125
 	WriteSettingsPage(qb422016, data)
-//line internal/templates/pages/settings.qtpl:21
+//line internal/templates/pages/settings.qtpl:125
 	qs422016 := string(qb422016.B)
-//line internal/templates/pages/settings.qtpl:21
+//line internal/templates/pages/settings.qtpl:125
 	qt422016.ReleaseByteBuffer(qb422016)
-//line internal/templates/pages/settings.qtpl:21
+//line internal/templates/pages/settings.qtpl:125
 	return qs422016
-//line internal/templates/pages/settings.qtpl:21
+//line internal/templates/pages/settings.qtpl:125
 }
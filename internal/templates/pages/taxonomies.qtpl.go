@@ -0,0 +1,288 @@
+// Code generated by qtc from "taxonomies.qtpl". DO NOT EDIT.
+// See https://github.com/valyala/quicktemplate for details.
+
+//line internal/templates/pages/taxonomies.qtpl:1
+package pages
+
+//line internal/templates/pages/taxonomies.qtpl:1
+import "cms/internal/models"
+
+//line internal/templates/pages/taxonomies.qtpl:2
+import "cms/internal/templates/layouts"
+
+//line internal/templates/pages/taxonomies.qtpl:3
+import "html"
+
+//line internal/templates/pages/taxonomies.qtpl:4
+import "strconv"
+
+//line internal/templates/pages/taxonomies.qtpl:5
+import "strings"
+
+//line internal/templates/pages/taxonomies.qtpl:7
+import (
+	qtio422016 "io"
+
+	qt422016 "github.com/valyala/quicktemplate"
+)
+
+//line internal/templates/pages/taxonomies.qtpl:7
+var (
+	_ = qtio422016.Copy
+	_ = qt422016.AcquireByteBuffer
+)
+
+//line internal/templates/pages/taxonomies.qtpl:8
+// TaxonomiesData and TermFormData structs are defined in models package
+type TaxonomiesData = models.TaxonomiesData
+type TermFormData = models.TermFormData
+
+//line internal/templates/pages/taxonomies.qtpl:13
+func StreamTaxonomiesPage(qw422016 *qt422016.Writer, data *TaxonomiesData) {
+//line internal/templates/pages/taxonomies.qtpl:13
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/taxonomies.qtpl:15
+	pageContent := func() string {
+		var sb strings.Builder
+		sb.WriteString(`<div class="px-4 sm:px-6 lg:px-8">
+                <div class="sm:flex sm:items-center">
+                    <div class="sm:flex-auto">
+                        <h1 class="text-2xl font-semibold leading-6 text-gray-900 dark:text-white">Tags &amp; Categories</h1>
+                        <p class="mt-2 text-sm text-gray-700 dark:text-gray-300">Tags are created as authors use them. Categories form a tree; a category lists the items of its subcategories too.</p>
+                    </div>
+                </div>`)
+		if data.IsSandbox() {
+			sb.WriteString(sandboxSharedNotice)
+		}
+		if data.Message != "" {
+			sb.WriteString(`<p class="mt-4 rounded-md bg-green-50 p-3 text-sm text-green-800 dark:bg-green-800/30 dark:text-green-300">`)
+			sb.WriteString(html.EscapeString(data.Message))
+			sb.WriteString(`</p>`)
+		}
+		if data.ErrorMessage != "" {
+			sb.WriteString(`<p class="mt-4 rounded-md bg-red-50 p-3 text-sm text-red-800 dark:bg-red-800/30 dark:text-red-300">`)
+			sb.WriteString(html.EscapeString(data.ErrorMessage))
+			sb.WriteString(`</p>`)
+		}
+
+		var form models.Term
+		if data.FormTaxonomy == models.TaxonomyCategories {
+			form = data.Form
+		}
+		sb.WriteString(termSection(data, models.TaxonomyCategories, "Categories", "category", data.Categories, form))
+		form = models.Term{}
+		if data.FormTaxonomy == models.TaxonomyTags {
+			form = data.Form
+		}
+		sb.WriteString(termSection(data, models.TaxonomyTags, "Tags", "tag", data.Tags, form))
+		sb.WriteString(`</div>`)
+		return sb.String()
+	}
+
+//line internal/templates/pages/taxonomies.qtpl:51
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/taxonomies.qtpl:52
+	qw422016.N().S(layouts.BaseLayout(data, pageContent))
+//line internal/templates/pages/taxonomies.qtpl:52
+	qw422016.N().S(`
+`)
+//line internal/templates/pages/taxonomies.qtpl:53
+}
+
+//line internal/templates/pages/taxonomies.qtpl:53
+func WriteTaxonomiesPage(qq422016 qtio422016.Writer, data *TaxonomiesData) {
+//line internal/templates/pages/taxonomies.qtpl:53
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/templates/pages/taxonomies.qtpl:53
+	StreamTaxonomiesPage(qw422016, data)
+//line internal/templates/pages/taxonomies.qtpl:53
+	qt422016.ReleaseWriter(qw422016)
+//line internal/templates/pages/taxonomies.qtpl:53
+}
+
+//line internal/templates/pages/taxonomies.qtpl:53
+func TaxonomiesPage(data *TaxonomiesData) string {
+//line internal/templates/pages/taxonomies.qtpl:53
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/templates/pages/taxonomies.qtpl:53
+	WriteTaxonomiesPage(qb422016, data)
+//line internal/templates/pages/taxonomies.qtpl:53
+	qs422016 := string(qb422016.B)
+//line internal/templates/pages/taxonomies.qtpl:53
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/templates/pages/taxonomies.qtpl:53
+	return qs422016
+//line internal/templates/pages/taxonomies.qtpl:53
+}
+
+//line internal/templates/pages/taxonomies.qtpl:55
+func StreamTermFormPage(qw422016 *qt422016.Writer, data *TermFormData) {
+//line internal/templates/pages/taxonomies.qtpl:55
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/taxonomies.qtpl:57
+	pageContent := func() string {
+		var sb strings.Builder
+		inputClass := `block w-full px-4 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 focus:ring-indigo-500 focus:border-indigo-500`
+		baseURL := "/admin/taxonomies/" + html.EscapeString(data.Taxonomy) + "/" + html.EscapeString(data.Term.Slug)
+
+		sb.WriteString(`<div class="bg-white dark:bg-gray-800 p-6 md:p-8 rounded-lg shadow-md w-full max-w-xl mx-auto">
+                <h1 class="text-2xl font-semibold mb-6 text-gray-900 dark:text-white">Edit: `)
+		sb.WriteString(html.EscapeString(data.Term.Name))
+		sb.WriteString(`</h1>`)
+		if data.ErrorMessage != "" {
+			sb.WriteString(`<p class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-800 dark:bg-red-800/30 dark:text-red-300">`)
+			sb.WriteString(html.EscapeString(data.ErrorMessage))
+			sb.WriteString(`</p>`)
+		}
+
+		sb.WriteString(`<form action="` + baseURL + `" method="POST" class="space-y-6">
+                <input type="hidden" name="csrf_token" value="` + data.CSRFToken() + `">
+                <div>
+                    <span class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Slug</span>
+                    <p class="text-gray-900 dark:text-gray-100 font-mono text-sm">` + html.EscapeString(data.Term.Slug) + `</p>
+                </div>
+                <div>
+                    <label for="name" class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Name</label>
+                    <input type="text" id="name" name="name" required maxlength="100" value="` + html.EscapeString(data.Term.Name) + `" class="` + inputClass + `">
+                </div>`)
+		if data.Taxonomy == models.TaxonomyCategories {
+			sb.WriteString(`<div>
+                    <label for="parent" class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Parent</label>
+                    <select id="parent" name="parent" class="` + inputClass + `">`)
+			sb.WriteString(parentOptions(data.Parents, data.Term.Parent))
+			sb.WriteString(`</select>
+                </div>`)
+		}
+		sb.WriteString(`<div>
+                    <label for="description" class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Description</label>
+                    <textarea id="description" name="description" rows="3" class="` + inputClass + `">` + html.EscapeString(data.Term.Description) + `</textarea>
+                    <p class="mt-2 text-xs text-gray-500 dark:text-gray-400">Shown on the public archive page.</p>
+                </div>
+                <div class="flex items-center justify-between pt-4 border-t border-gray-200 dark:border-gray-700">
+                    <button type="submit" class="inline-flex items-center px-5 py-2.5 border border-transparent text-sm font-medium rounded-md shadow-sm text-white bg-indigo-600 hover:bg-indigo-700 dark:bg-indigo-500 dark:hover:bg-indigo-400">Save</button>
+                    <a href="/admin/taxonomies" class="text-sm text-gray-600 hover:text-gray-900 dark:text-gray-400 dark:hover:text-gray-200">Cancel</a>
+                </div>
+            </form>
+            </div>`)
+		return sb.String()
+	}
+
+//line internal/templates/pages/taxonomies.qtpl:103
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/taxonomies.qtpl:104
+	qw422016.N().S(layouts.BaseLayout(data, pageContent))
+//line internal/templates/pages/taxonomies.qtpl:104
+	qw422016.N().S(`
+`)
+//line internal/templates/pages/taxonomies.qtpl:105
+}
+
+//line internal/templates/pages/taxonomies.qtpl:105
+func WriteTermFormPage(qq422016 qtio422016.Writer, data *TermFormData) {
+//line internal/templates/pages/taxonomies.qtpl:105
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/templates/pages/taxonomies.qtpl:105
+	StreamTermFormPage(qw422016, data)
+//line internal/templates/pages/taxonomies.qtpl:105
+	qt422016.ReleaseWriter(qw422016)
+//line internal/templates/pages/taxonomies.qtpl:105
+}
+
+//line internal/templates/pages/taxonomies.qtpl:105
+func TermFormPage(data *TermFormData) string {
+//line internal/templates/pages/taxonomies.qtpl:105
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/templates/pages/taxonomies.qtpl:105
+	WriteTermFormPage(qb422016, data)
+//line internal/templates/pages/taxonomies.qtpl:105
+	qs422016 := string(qb422016.B)
+//line internal/templates/pages/taxonomies.qtpl:105
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/templates/pages/taxonomies.qtpl:105
+	return qs422016
+//line internal/templates/pages/taxonomies.qtpl:105
+}
+
+//line internal/templates/pages/taxonomies.qtpl:108
+// termSection renders the table of one taxonomy with its add form.
+// form holds the values of a rejected add, if any.
+func termSection(data *TaxonomiesData, taxonomy, heading, noun string, entries []models.TermEntry, form models.Term) string {
+	var sb strings.Builder
+	inputClass := `px-3 py-1.5 border border-gray-300 dark:border-gray-600 rounded-md bg-white dark:bg-gray-700 text-sm text-gray-900 dark:text-gray-100`
+	sb.WriteString(`<section class="mt-10">
+            <h2 class="text-lg font-semibold text-gray-900 dark:text-white">` + heading + `</h2>
+            <div class="mt-4 overflow-hidden shadow ring-1 ring-black ring-opacity-5 dark:ring-white dark:ring-opacity-10 sm:rounded-lg">
+                <table class="min-w-full divide-y divide-gray-300 dark:divide-gray-700">
+                    <thead class="bg-gray-50 dark:bg-gray-700">
+                        <tr>
+                            <th scope="col" class="py-3.5 pl-4 pr-3 text-left text-sm font-semibold text-gray-900 dark:text-gray-100 sm:pl-6">Name</th>
+                            <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Slug</th>
+                            <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Items</th>
+                            <th scope="col" class="relative py-3.5 pl-3 pr-4 sm:pr-6"><span class="sr-only">Actions</span></th>
+                        </tr>
+                    </thead>
+                    <tbody class="divide-y divide-gray-200 dark:divide-gray-700 bg-white dark:bg-gray-800">`)
+	if len(entries) == 0 {
+		sb.WriteString(`<tr><td colspan="4" class="whitespace-nowrap py-4 pl-4 pr-3 text-sm text-gray-500 dark:text-gray-400 sm:pl-6">No ` + strings.ToLower(heading) + ` yet.</td></tr>`)
+	}
+	for _, entry := range entries {
+		name := html.EscapeString(entry.Term.Name)
+		baseURL := "/admin/taxonomies/" + taxonomy + "/" + html.EscapeString(entry.Term.Slug)
+		sb.WriteString(`<tr class="hover:bg-gray-50 dark:hover:bg-gray-700">
+                <td class="whitespace-nowrap py-4 pl-4 pr-3 text-sm font-medium text-gray-900 dark:text-gray-100 sm:pl-6">`)
+		sb.WriteString(strings.Repeat(`<span class="text-gray-400">&mdash;</span> `, entry.Depth))
+		sb.WriteString(name)
+		sb.WriteString(`</td>
+                <td class="whitespace-nowrap px-3 py-4 text-sm font-mono text-gray-500 dark:text-gray-400">` + html.EscapeString(entry.Term.Slug) + `</td>
+                <td class="whitespace-nowrap px-3 py-4 text-sm text-gray-500 dark:text-gray-400"><a href="/content?` + noun + `=` + html.EscapeString(entry.Term.Slug) + `" class="text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">` + strconv.Itoa(entry.Count) + `</a></td>
+                <td class="relative whitespace-nowrap py-4 pl-3 pr-4 text-right text-sm font-medium sm:pr-6">`)
+		if !data.IsSandbox() {
+			sb.WriteString(`<a href="` + baseURL + `/edit" class="text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">Edit<span class="sr-only">, ` + name + `</span></a>
+                    <form action="` + baseURL + `/delete" method="POST" class="inline ml-4" onsubmit="return confirm('Delete this ` + noun + ` and remove it from its items?');">
+                        <input type="hidden" name="csrf_token" value="` + data.CSRFToken() + `">
+                        <button type="submit" class="text-red-600 hover:text-red-800 dark:text-red-400 dark:hover:text-red-300">Delete<span class="sr-only">, ` + name + `</span></button>
+                    </form>`)
+		}
+		sb.WriteString(`</td>
+            </tr>`)
+	}
+	sb.WriteString(`</tbody>
+                </table>
+            </div>`)
+	if data.IsSandbox() {
+		sb.WriteString(`</section>`)
+		return sb.String()
+	}
+	sb.WriteString(`<form action="/admin/taxonomies/` + taxonomy + `" method="POST" class="mt-4 flex flex-wrap items-end gap-3 text-sm text-gray-700 dark:text-gray-300">
+                <input type="hidden" name="csrf_token" value="` + data.CSRFToken() + `">
+                <label class="flex flex-col gap-1">Name<input type="text" name="name" required maxlength="100" value="` + html.EscapeString(form.Name) + `" class="` + inputClass + `"></label>
+                <label class="flex flex-col gap-1">Slug<input type="text" name="slug" pattern="^[a-z0-9]+(?:-[a-z0-9]+)*$" placeholder="From the name" value="` + html.EscapeString(form.Slug) + `" class="` + inputClass + `"></label>`)
+	if taxonomy == models.TaxonomyCategories {
+		sb.WriteString(`<label class="flex flex-col gap-1">Parent<select name="parent" class="` + inputClass + `">`)
+		sb.WriteString(parentOptions(entries, form.Parent))
+		sb.WriteString(`</select></label>`)
+	}
+	sb.WriteString(`<label class="flex flex-col gap-1">Description<input type="text" name="description" value="` + html.EscapeString(form.Description) + `" class="` + inputClass + `"></label>
+                <button type="submit" class="px-3 py-1.5 rounded-md bg-indigo-600 text-white hover:bg-indigo-700">Add ` + noun + `</button>
+            </form>
+        </section>`)
+	return sb.String()
+}
+
+// parentOptions renders the options of a parent category select.
+func parentOptions(categories []models.TermEntry, selected string) string {
+	var sb strings.Builder
+	sb.WriteString(`<option value="">None (top level)</option>`)
+	for _, entry := range categories {
+		sb.WriteString(`<option value="` + html.EscapeString(entry.Term.Slug) + `"`)
+		if entry.Term.Slug == selected {
+			sb.WriteString(` selected`)
+		}
+		sb.WriteString(`>` + strings.Repeat("&nbsp;&nbsp;", entry.Depth) + html.EscapeString(entry.Term.Name) + `</option>`)
+	}
+	return sb.String()
+}
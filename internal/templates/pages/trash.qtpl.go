@@ -0,0 +1,151 @@
+// Code generated by qtc from "trash.qtpl". DO NOT EDIT.
+// See https://github.com/valyala/quicktemplate for details.
+
+//line internal/templates/pages/trash.qtpl:1
+package pages
+
+//line internal/templates/pages/trash.qtpl:1
+import "cms/internal/models"
+
+//line internal/templates/pages/trash.qtpl:2
+import "cms/internal/templates/layouts"
+
+//line internal/templates/pages/trash.qtpl:3
+import "html"
+
+//line internal/templates/pages/trash.qtpl:4
+import "strings"
+
+//line internal/templates/pages/trash.qtpl:6
+import (
+	qtio422016 "io"
+
+	qt422016 "github.com/valyala/quicktemplate"
+)
+
+//line internal/templates/pages/trash.qtpl:6
+var (
+	_ = qtio422016.Copy
+	_ = qt422016.AcquireByteBuffer
+)
+
+//line internal/templates/pages/trash.qtpl:7
+// TrashData struct is defined in models package
+type TrashData = models.TrashData
+
+//line internal/templates/pages/trash.qtpl:11
+func StreamTrashPage(qw422016 *qt422016.Writer, data *TrashData) {
+//line internal/templates/pages/trash.qtpl:11
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/trash.qtpl:13
+	pageContent := func() string {
+		var sb strings.Builder
+		sb.WriteString(`<div class="px-4 sm:px-6 lg:px-8">
+                <div class="sm:flex sm:items-center">
+                    <div class="sm:flex-auto">
+                        <h1 class="text-2xl font-semibold leading-6 text-gray-900 dark:text-white">Trash</h1>
+                        <p class="mt-2 text-sm text-gray-700 dark:text-gray-300">Deleted items can be restored until they are permanently deleted.</p>
+                    </div>
+                    <div class="mt-4 sm:ml-16 sm:mt-0 sm:flex-none">
+                        <a href="/content" class="text-sm font-semibold text-gray-600 hover:text-gray-900 dark:text-gray-400 dark:hover:text-gray-200">Back to list</a>
+                    </div>
+                </div>`)
+
+		if data.Message != "" {
+			sb.WriteString(`<p class="mt-4 rounded-md bg-green-50 p-3 text-sm text-green-800 dark:bg-green-800/30 dark:text-green-300">`)
+			sb.WriteString(html.EscapeString(data.Message))
+			sb.WriteString(`</p>`)
+		}
+
+		sb.WriteString(`
+                <div class="mt-8 overflow-hidden shadow ring-1 ring-black ring-opacity-5 dark:ring-white dark:ring-opacity-10 sm:rounded-lg">
+                    <table class="min-w-full divide-y divide-gray-300 dark:divide-gray-700">
+                        <thead class="bg-gray-50 dark:bg-gray-700">
+                            <tr>
+                                <th scope="col" class="py-3.5 pl-4 pr-3 text-left text-sm font-semibold text-gray-900 dark:text-gray-100 sm:pl-6">Title</th>
+                                <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Deleted At</th>
+                                <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Purged On</th>
+                                <th scope="col" class="relative py-3.5 pl-3 pr-4 sm:pr-6"><span class="sr-only">Actions</span></th>
+                            </tr>
+                        </thead>
+                        <tbody class="divide-y divide-gray-200 dark:divide-gray-700 bg-white dark:bg-gray-800">`)
+
+		if len(data.Entries) == 0 {
+			sb.WriteString(`<tr><td colspan="4" class="whitespace-nowrap py-4 pl-4 pr-3 text-sm text-gray-500 dark:text-gray-400 sm:pl-6">The trash is empty.</td></tr>`)
+		}
+		for _, entry := range data.Entries {
+			id := html.EscapeString(entry.Item.ID)
+			sb.WriteString(`<tr class="hover:bg-gray-50 dark:hover:bg-gray-700">
+                    <td class="whitespace-nowrap py-4 pl-4 pr-3 text-sm font-medium text-gray-900 dark:text-gray-100 sm:pl-6">`)
+			sb.WriteString(html.EscapeString(entry.Item.Title))
+			sb.WriteString(`</td>
+                    <td class="whitespace-nowrap px-3 py-4 text-sm text-gray-500 dark:text-gray-400">`)
+			sb.WriteString(entry.Item.DeletedAt.Format("2006-01-02 15:04"))
+			sb.WriteString(`</td>
+                    <td class="whitespace-nowrap px-3 py-4 text-sm text-gray-500 dark:text-gray-400">`)
+			if entry.PurgeAt.IsZero() {
+				sb.WriteString(`Never`)
+			} else {
+				sb.WriteString(entry.PurgeAt.Format("2006-01-02"))
+			}
+			sb.WriteString(`</td>
+                    <td class="relative whitespace-nowrap py-4 pl-3 pr-4 text-right text-sm font-medium sm:pr-6">`)
+			if entry.CanModify {
+				sb.WriteString(`<form method="POST" action="/api/content/` + id + `/restore" class="inline">
+                            <input type="hidden" name="csrf_token" value="` + data.CSRFToken() + `">
+                            <button type="submit" class="text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">Restore</button>
+                        </form>
+                        <form method="POST" action="/api/content/` + id + `/purge" class="inline ml-4" onsubmit="return confirm('Permanently delete this item? This cannot be undone.')">
+                            <input type="hidden" name="csrf_token" value="` + data.CSRFToken() + `">
+                            <button type="submit" class="text-red-600 hover:text-red-800 dark:text-red-400 dark:hover:text-red-300">Delete permanently</button>
+                        </form>`)
+			}
+			sb.WriteString(`</td>
+                </tr>`)
+		}
+
+		sb.WriteString(`
+                        </tbody>
+                    </table>
+                </div>
+            </div>`)
+		return sb.String()
+	}
+
+//line internal/templates/pages/trash.qtpl:86
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/trash.qtpl:87
+	qw422016.N().S(layouts.BaseLayout(data, pageContent))
+//line internal/templates/pages/trash.qtpl:87
+	qw422016.N().S(`
+`)
+//line internal/templates/pages/trash.qtpl:88
+}
+
+//line internal/templates/pages/trash.qtpl:88
+func WriteTrashPage(qq422016 qtio422016.Writer, data *TrashData) {
+//line internal/templates/pages/trash.qtpl:88
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/templates/pages/trash.qtpl:88
+	StreamTrashPage(qw422016, data)
+//line internal/templates/pages/trash.qtpl:88
+	qt422016.ReleaseWriter(qw422016)
+//line internal/templates/pages/trash.qtpl:88
+}
+
+//line internal/templates/pages/trash.qtpl:88
+func TrashPage(data *TrashData) string {
+//line internal/templates/pages/trash.qtpl:88
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/templates/pages/trash.qtpl:88
+	WriteTrashPage(qb422016, data)
+//line internal/templates/pages/trash.qtpl:88
+	qs422016 := string(qb422016.B)
+//line internal/templates/pages/trash.qtpl:88
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/templates/pages/trash.qtpl:88
+	return qs422016
+//line internal/templates/pages/trash.qtpl:88
+}
@@ -0,0 +1,438 @@
+// Code generated by qtc from "types.qtpl". DO NOT EDIT.
+// See https://github.com/valyala/quicktemplate for details.
+
+//line internal/templates/pages/types.qtpl:1
+package pages
+
+//line internal/templates/pages/types.qtpl:1
+import "cms/internal/models"
+
+//line internal/templates/pages/types.qtpl:2
+import "cms/internal/schema"
+
+//line internal/templates/pages/types.qtpl:3
+import "cms/internal/templates/layouts"
+
+//line internal/templates/pages/types.qtpl:4
+import "html"
+
+//line internal/templates/pages/types.qtpl:5
+import "strconv"
+
+//line internal/templates/pages/types.qtpl:6
+import "strings"
+
+//line internal/templates/pages/types.qtpl:8
+import (
+	qtio422016 "io"
+
+	qt422016 "github.com/valyala/quicktemplate"
+)
+
+//line internal/templates/pages/types.qtpl:8
+var (
+	_ = qtio422016.Copy
+	_ = qt422016.AcquireByteBuffer
+)
+
+//line internal/templates/pages/types.qtpl:9
+// TypesData, ItemsData and ItemFormData structs are defined in models package
+type TypesData = models.TypesData
+type ItemsData = models.ItemsData
+type ItemFormData = models.ItemFormData
+
+//line internal/templates/pages/types.qtpl:15
+func StreamTypesPage(qw422016 *qt422016.Writer, data *TypesData) {
+//line internal/templates/pages/types.qtpl:15
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/types.qtpl:17
+	pageContent := func() string {
+		var sb strings.Builder
+		sb.WriteString(`<div class="px-4 sm:px-6 lg:px-8">
+                <div class="sm:flex sm:items-center">
+                    <div class="sm:flex-auto">
+                        <h1 class="text-2xl font-semibold leading-6 text-gray-900 dark:text-white">Content Types</h1>
+                        <p class="mt-2 text-sm text-gray-700 dark:text-gray-300">Custom content types and their items.</p>
+                    </div>
+                </div>`)
+		if data.IsSandbox() {
+			sb.WriteString(sandboxSharedNotice)
+		}
+
+		if len(data.Types) == 0 {
+			sb.WriteString(`<p class="mt-8 text-sm text-gray-500 dark:text-gray-400">No content types are defined yet.`)
+			if data.CanManage {
+				sb.WriteString(` Define one with <code>POST /api/types</code>.`)
+			}
+			sb.WriteString(`</p></div>`)
+			return sb.String()
+		}
+
+		sb.WriteString(`
+                <div class="mt-8 overflow-hidden shadow ring-1 ring-black ring-opacity-5 dark:ring-white dark:ring-opacity-10 sm:rounded-lg">
+                    <table class="min-w-full divide-y divide-gray-300 dark:divide-gray-700">
+                        <thead class="bg-gray-50 dark:bg-gray-700">
+                            <tr>
+                                <th scope="col" class="py-3.5 pl-4 pr-3 text-left text-sm font-semibold text-gray-900 dark:text-gray-100 sm:pl-6">Type</th>
+                                <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Description</th>
+                                <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Fields</th>
+                                <th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Items</th>
+                            </tr>
+                        </thead>
+                        <tbody class="divide-y divide-gray-200 dark:divide-gray-700 bg-white dark:bg-gray-800">`)
+		for _, t := range data.Types {
+			sb.WriteString(`<tr class="hover:bg-gray-50 dark:hover:bg-gray-700">
+                    <td class="whitespace-nowrap py-4 pl-4 pr-3 text-sm font-medium sm:pl-6"><a href="/types/` + html.EscapeString(t.Name) + `" class="text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">`)
+			sb.WriteString(html.EscapeString(t.DisplayLabel()))
+			sb.WriteString(`</a></td>
+                    <td class="px-3 py-4 text-sm text-gray-500 dark:text-gray-400">`)
+			sb.WriteString(html.EscapeString(t.Description))
+			sb.WriteString(`</td>
+                    <td class="whitespace-nowrap px-3 py-4 text-sm text-gray-500 dark:text-gray-400">` + strconv.Itoa(len(t.Fields)) + `</td>
+                    <td class="whitespace-nowrap px-3 py-4 text-sm text-gray-500 dark:text-gray-400">` + strconv.Itoa(data.Counts[t.Name]) + `</td>
+                </tr>`)
+		}
+		sb.WriteString(`
+                        </tbody>
+                    </table>
+                </div>
+            </div>`)
+		return sb.String()
+	}
+
+//line internal/templates/pages/types.qtpl:70
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/types.qtpl:71
+	qw422016.N().S(layouts.BaseLayout(data, pageContent))
+//line internal/templates/pages/types.qtpl:71
+	qw422016.N().S(`
+`)
+//line internal/templates/pages/types.qtpl:72
+}
+
+//line internal/templates/pages/types.qtpl:72
+func WriteTypesPage(qq422016 qtio422016.Writer, data *TypesData) {
+//line internal/templates/pages/types.qtpl:72
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/templates/pages/types.qtpl:72
+	StreamTypesPage(qw422016, data)
+//line internal/templates/pages/types.qtpl:72
+	qt422016.ReleaseWriter(qw422016)
+//line internal/templates/pages/types.qtpl:72
+}
+
+//line internal/templates/pages/types.qtpl:72
+func TypesPage(data *TypesData) string {
+//line internal/templates/pages/types.qtpl:72
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/templates/pages/types.qtpl:72
+	WriteTypesPage(qb422016, data)
+//line internal/templates/pages/types.qtpl:72
+	qs422016 := string(qb422016.B)
+//line internal/templates/pages/types.qtpl:72
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/templates/pages/types.qtpl:72
+	return qs422016
+//line internal/templates/pages/types.qtpl:72
+}
+
+//line internal/templates/pages/types.qtpl:74
+func StreamItemsPage(qw422016 *qt422016.Writer, data *ItemsData) {
+//line internal/templates/pages/types.qtpl:74
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/types.qtpl:76
+	pageContent := func() string {
+		var sb strings.Builder
+		name := html.EscapeString(data.Type.Name)
+		sb.WriteString(`<div class="px-4 sm:px-6 lg:px-8">
+                <div class="sm:flex sm:items-center">
+                    <div class="sm:flex-auto">
+                        <h1 class="text-2xl font-semibold leading-6 text-gray-900 dark:text-white">`)
+		sb.WriteString(html.EscapeString(data.Type.DisplayLabel()))
+		sb.WriteString(`</h1>
+                        <p class="mt-2 text-sm text-gray-700 dark:text-gray-300">`)
+		sb.WriteString(html.EscapeString(data.Type.Description))
+		sb.WriteString(`</p>
+                    </div>
+                    <div class="mt-4 sm:ml-16 sm:mt-0 sm:flex-none flex items-center gap-4">
+                        <a href="/types" class="text-sm font-semibold text-gray-600 hover:text-gray-900 dark:text-gray-400 dark:hover:text-gray-200">All types</a>`)
+		if data.CanCreate {
+			sb.WriteString(`<a href="/types/` + name + `/new" class="block rounded-md bg-indigo-600 px-4 py-2 text-center text-sm font-semibold text-white shadow-sm hover:bg-indigo-500 dark:bg-indigo-500 dark:hover:bg-indigo-400">Add `)
+			sb.WriteString(html.EscapeString(data.Type.DisplayLabel()))
+			sb.WriteString(`</a>`)
+		}
+		sb.WriteString(`
+                    </div>
+                </div>`)
+		if data.IsSandbox() {
+			sb.WriteString(sandboxSharedNotice)
+		}
+
+		if data.Message != "" {
+			sb.WriteString(`<p class="mt-4 rounded-md bg-green-50 p-3 text-sm text-green-800 dark:bg-green-800/30 dark:text-green-300">`)
+			sb.WriteString(html.EscapeString(data.Message))
+			sb.WriteString(`</p>`)
+		}
+
+		sb.WriteString(`
+                <div class="mt-8 overflow-hidden shadow ring-1 ring-black ring-opacity-5 dark:ring-white dark:ring-opacity-10 sm:rounded-lg">
+                    <table class="min-w-full divide-y divide-gray-300 dark:divide-gray-700">
+                        <thead class="bg-gray-50 dark:bg-gray-700">
+                            <tr>`)
+		for _, f := range data.Columns {
+			sb.WriteString(`<th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100 first:pl-4 sm:first:pl-6">`)
+			sb.WriteString(html.EscapeString(f.DisplayLabel()))
+			sb.WriteString(`</th>`)
+		}
+		sb.WriteString(`<th scope="col" class="px-3 py-3.5 text-left text-sm font-semibold text-gray-900 dark:text-gray-100">Updated</th>
+                                <th scope="col" class="relative py-3.5 pl-3 pr-4 sm:pr-6"><span class="sr-only">Actions</span></th>
+                            </tr>
+                        </thead>
+                        <tbody class="divide-y divide-gray-200 dark:divide-gray-700 bg-white dark:bg-gray-800">`)
+
+		if len(data.Items) == 0 {
+			sb.WriteString(`<tr><td colspan="` + strconv.Itoa(len(data.Columns)+2) + `" class="whitespace-nowrap py-4 pl-4 pr-3 text-sm text-gray-500 dark:text-gray-400 sm:pl-6">No items yet.</td></tr>`)
+		}
+		for _, item := range data.Items {
+			id := html.EscapeString(item.ID)
+			sb.WriteString(`<tr class="hover:bg-gray-50 dark:hover:bg-gray-700">`)
+			for _, f := range data.Columns {
+				sb.WriteString(`<td class="max-w-xs truncate px-3 py-4 text-sm text-gray-700 dark:text-gray-300 first:pl-4 sm:first:pl-6">`)
+				sb.WriteString(html.EscapeString(f.FormValue(item.Values[f.Name])))
+				sb.WriteString(`</td>`)
+			}
+			sb.WriteString(`<td class="whitespace-nowrap px-3 py-4 text-sm text-gray-500 dark:text-gray-400">`)
+			sb.WriteString(item.UpdatedAt.Format("2006-01-02 15:04"))
+			sb.WriteString(`</td>
+                    <td class="relative whitespace-nowrap py-4 pl-3 pr-4 text-right text-sm font-medium sm:pr-6">`)
+			if data.Editable[item.ID] {
+				sb.WriteString(`<a href="/types/` + name + `/` + id + `/edit" class="text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">Edit</a>
+                        <form method="POST" action="/types/` + name + `/` + id + `/delete" class="inline ml-4" onsubmit="return confirm('Delete this item? This cannot be undone.')">
+                            <input type="hidden" name="csrf_token" value="` + data.CSRFToken() + `">
+                            <button type="submit" class="text-red-600 hover:text-red-800 dark:text-red-400 dark:hover:text-red-300">Delete</button>
+                        </form>`)
+			}
+			sb.WriteString(`</td>
+                </tr>`)
+		}
+
+		sb.WriteString(`
+                        </tbody>
+                    </table>
+                </div>
+            </div>`)
+		return sb.String()
+	}
+
+//line internal/templates/pages/types.qtpl:158
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/types.qtpl:159
+	qw422016.N().S(layouts.BaseLayout(data, pageContent))
+//line internal/templates/pages/types.qtpl:159
+	qw422016.N().S(`
+`)
+//line internal/templates/pages/types.qtpl:160
+}
+
+//line internal/templates/pages/types.qtpl:160
+func WriteItemsPage(qq422016 qtio422016.Writer, data *ItemsData) {
+//line internal/templates/pages/types.qtpl:160
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/templates/pages/types.qtpl:160
+	StreamItemsPage(qw422016, data)
+//line internal/templates/pages/types.qtpl:160
+	qt422016.ReleaseWriter(qw422016)
+//line internal/templates/pages/types.qtpl:160
+}
+
+//line internal/templates/pages/types.qtpl:160
+func ItemsPage(data *ItemsData) string {
+//line internal/templates/pages/types.qtpl:160
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/templates/pages/types.qtpl:160
+	WriteItemsPage(qb422016, data)
+//line internal/templates/pages/types.qtpl:160
+	qs422016 := string(qb422016.B)
+//line internal/templates/pages/types.qtpl:160
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/templates/pages/types.qtpl:160
+	return qs422016
+//line internal/templates/pages/types.qtpl:160
+}
+
+//line internal/templates/pages/types.qtpl:162
+func StreamItemFormPage(qw422016 *qt422016.Writer, data *ItemFormData) {
+//line internal/templates/pages/types.qtpl:162
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/types.qtpl:164
+	pageContent := func() string {
+		var sb strings.Builder
+		inputClass := `block w-full px-4 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 focus:ring-indigo-500 focus:border-indigo-500`
+		name := html.EscapeString(data.Type.Name)
+		actionURL := "/types/" + name + "/new"
+		if !data.IsNew {
+			actionURL = "/types/" + name + "/" + html.EscapeString(data.Item.ID) + "/edit"
+		}
+
+		sb.WriteString(`<div class="bg-white dark:bg-gray-800 p-6 md:p-8 rounded-lg shadow-md w-full max-w-3xl mx-auto">
+                <h1 class="text-2xl font-semibold mb-6 text-gray-900 dark:text-white">`)
+		sb.WriteString(html.EscapeString(data.PageTitle))
+		sb.WriteString(`</h1>`)
+
+		if len(data.Errors) > 0 {
+			sb.WriteString(`<p class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-800 dark:bg-red-800/30 dark:text-red-300">`)
+			if msg := data.Errors[""]; msg != "" {
+				sb.WriteString(html.EscapeString(msg))
+			} else {
+				sb.WriteString(`Please correct the highlighted fields.`)
+			}
+			sb.WriteString(`</p>`)
+		}
+
+		sb.WriteString(`<form action="` + actionURL + `" method="POST" class="space-y-6">`)
+		sb.WriteString(`<input type="hidden" name="csrf_token" value="` + data.CSRFToken() + `">`)
+		for _, f := range data.Type.Fields {
+			sb.WriteString(itemField(f, data.Form[f.Name], data.Errors[f.Name], inputClass))
+		}
+		sb.WriteString(`
+                    <div class="flex justify-end space-x-3">
+                        <a href="/types/` + name + `" class="px-4 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm text-sm font-medium text-gray-700 dark:text-gray-300 bg-white dark:bg-gray-700 hover:bg-gray-50 dark:hover:bg-gray-600">Cancel</a>
+                        <button type="submit" class="px-4 py-2 rounded-md shadow-sm text-sm font-medium text-white bg-indigo-600 hover:bg-indigo-700">Save</button>
+                    </div>
+                </form>
+            </div>`)
+		return sb.String()
+	}
+
+//line internal/templates/pages/types.qtpl:202
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/types.qtpl:203
+	qw422016.N().S(layouts.BaseLayout(data, pageContent))
+//line internal/templates/pages/types.qtpl:203
+	qw422016.N().S(`
+`)
+//line internal/templates/pages/types.qtpl:204
+}
+
+//line internal/templates/pages/types.qtpl:204
+func WriteItemFormPage(qq422016 qtio422016.Writer, data *ItemFormData) {
+//line internal/templates/pages/types.qtpl:204
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/templates/pages/types.qtpl:204
+	StreamItemFormPage(qw422016, data)
+//line internal/templates/pages/types.qtpl:204
+	qt422016.ReleaseWriter(qw422016)
+//line internal/templates/pages/types.qtpl:204
+}
+
+//line internal/templates/pages/types.qtpl:204
+func ItemFormPage(data *ItemFormData) string {
+//line internal/templates/pages/types.qtpl:204
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/templates/pages/types.qtpl:204
+	WriteItemFormPage(qb422016, data)
+//line internal/templates/pages/types.qtpl:204
+	qs422016 := string(qb422016.B)
+//line internal/templates/pages/types.qtpl:204
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/templates/pages/types.qtpl:204
+	return qs422016
+//line internal/templates/pages/types.qtpl:204
+}
+
+//line internal/templates/pages/types.qtpl:207
+// sandboxSharedNotice explains why types, taxonomies and media are
+// read-only in the sandbox.
+const sandboxSharedNotice = `<p class="mt-4 rounded-md bg-yellow-50 p-3 text-sm text-yellow-800 dark:bg-yellow-800/30 dark:text-yellow-200">This is shared by all visitors, so it is read-only in the sandbox.</p>`
+
+// itemField renders the input for one field of a content type, chosen
+// by the field type, with its help text and validation error.
+func itemField(f schema.Field, value, errorMessage, inputClass string) string {
+	var sb strings.Builder
+	id := "field-" + html.EscapeString(f.Name)
+	inputName := html.EscapeString(f.Name)
+	attrs := ` id="` + id + `" name="` + inputName + `"`
+	if f.Required && f.Type != schema.TypeBoolean {
+		attrs += ` required`
+	}
+	if errorMessage != "" {
+		inputClass += ` border-red-500 dark:border-red-400`
+		attrs += ` aria-invalid="true"`
+	}
+
+	sb.WriteString(`<div>`)
+	if f.Type == schema.TypeBoolean {
+		sb.WriteString(`<label for="` + id + `" class="flex items-center gap-2 text-sm font-medium text-gray-700 dark:text-gray-300"><input type="checkbox" value="true"` + attrs)
+		if value == "true" {
+			sb.WriteString(` checked`)
+		}
+		sb.WriteString(`> ` + html.EscapeString(f.DisplayLabel()) + `</label>`)
+	} else {
+		sb.WriteString(`<label for="` + id + `" class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">` + html.EscapeString(f.DisplayLabel()))
+		if f.Required {
+			sb.WriteString(` <span class="text-red-600">*</span>`)
+		}
+		sb.WriteString(`</label>`)
+
+		switch f.Type {
+		case schema.TypeText:
+			sb.WriteString(`<textarea rows="6"` + attrs + ` class="` + inputClass + `">` + html.EscapeString(value) + `</textarea>`)
+		case schema.TypeSelect:
+			sb.WriteString(`<select` + attrs + ` class="` + inputClass + `"><option value=""></option>`)
+			for _, o := range f.Options {
+				sb.WriteString(`<option value="` + html.EscapeString(o) + `"`)
+				if o == value {
+					sb.WriteString(` selected`)
+				}
+				sb.WriteString(`>` + html.EscapeString(o) + `</option>`)
+			}
+			sb.WriteString(`</select>`)
+		default:
+			inputType := "text"
+			switch f.Type {
+			case schema.TypeNumber, schema.TypeInteger:
+				inputType = "number"
+				step := "any"
+				if f.Type == schema.TypeInteger {
+					step = "1"
+				}
+				attrs += ` step="` + step + `"`
+				if f.Min != nil {
+					attrs += ` min="` + strconv.FormatFloat(*f.Min, 'f', -1, 64) + `"`
+				}
+				if f.Max != nil {
+					attrs += ` max="` + strconv.FormatFloat(*f.Max, 'f', -1, 64) + `"`
+				}
+			case schema.TypeDate:
+				inputType = "date"
+			case schema.TypeDateTime:
+				inputType = "datetime-local"
+			case schema.TypeEmail:
+				inputType = "email"
+			case schema.TypeURL:
+				inputType = "url"
+			}
+			if f.MaxLength > 0 {
+				attrs += ` maxlength="` + strconv.Itoa(f.MaxLength) + `"`
+			}
+			sb.WriteString(`<input type="` + inputType + `"` + attrs + ` value="` + html.EscapeString(value) + `" class="` + inputClass + `">`)
+		}
+	}
+	if errorMessage != "" {
+		sb.WriteString(`<p class="mt-2 text-sm text-red-600 dark:text-red-400">` + html.EscapeString(errorMessage) + `</p>`)
+	}
+	if f.Help != "" {
+		sb.WriteString(`<p class="mt-2 text-xs text-gray-500 dark:text-gray-400">` + html.EscapeString(f.Help) + `</p>`)
+	}
+	if f.Type == schema.TypeDateTime {
+		sb.WriteString(`<p class="mt-2 text-xs text-gray-500 dark:text-gray-400">Time in UTC.</p>`)
+	}
+	sb.WriteString(`</div>`)
+	return sb.String()
+}
@@ -0,0 +1,176 @@
+// Code generated by qtc from "userform.qtpl". DO NOT EDIT.
+// See https://github.com/valyala/quicktemplate for details.
+
+//line internal/templates/pages/userform.qtpl:1
+package pages
+
+//line internal/templates/pages/userform.qtpl:1
+import "cms/internal/models"
+
+//line internal/templates/pages/userform.qtpl:2
+import "cms/internal/templates/layouts"
+
+//line internal/templates/pages/userform.qtpl:3
+import "html"
+
+//line internal/templates/pages/userform.qtpl:4
+import "strings"
+
+//line internal/templates/pages/userform.qtpl:6
+import (
+	qtio422016 "io"
+
+	qt422016 "github.com/valyala/quicktemplate"
+)
+
+//line internal/templates/pages/userform.qtpl:6
+var (
+	_ = qtio422016.Copy
+	_ = qt422016.AcquireByteBuffer
+)
+
+//line internal/templates/pages/userform.qtpl:7
+// UserFormData struct is defined in models package
+type UserFormData = models.UserFormData
+
+//line internal/templates/pages/userform.qtpl:11
+func StreamUserFormPage(qw422016 *qt422016.Writer, data *UserFormData) {
+//line internal/templates/pages/userform.qtpl:11
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/userform.qtpl:13
+	pageContent := func() string {
+		var sb strings.Builder
+		inputClass := `block w-full px-4 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 focus:ring-indigo-500 focus:border-indigo-500`
+		key := html.EscapeString(strings.ToLower(data.User.Username))
+		actionURL := "/admin/users"
+		pageTitle := "Add User"
+		if !data.IsNew {
+			actionURL = "/admin/users/" + key
+			pageTitle = "Edit User: " + html.EscapeString(data.User.Username)
+		}
+
+		sb.WriteString(`<div class="bg-white dark:bg-gray-800 p-6 md:p-8 rounded-lg shadow-md w-full max-w-xl mx-auto">
+                <h1 class="text-2xl font-semibold mb-6 text-gray-900 dark:text-white">`)
+		sb.WriteString(pageTitle)
+		sb.WriteString(`</h1>`)
+
+		if data.ErrorMessage != "" {
+			sb.WriteString(`<p class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-800 dark:bg-red-800/30 dark:text-red-300">`)
+			sb.WriteString(html.EscapeString(data.ErrorMessage))
+			sb.WriteString(`</p>`)
+		}
+
+		sb.WriteString(`<form action="`)
+		sb.WriteString(actionURL)
+		sb.WriteString(`" method="POST" class="space-y-6">`)
+		sb.WriteString(`<input type="hidden" name="csrf_token" value="`)
+		sb.WriteString(data.CSRFToken())
+		sb.WriteString(`">`)
+		sb.WriteString(`
+                    <div>
+                        <label for="username" class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Username</label>`)
+		if data.IsNew {
+			sb.WriteString(`<input type="text" id="username" name="username" required pattern="[A-Za-z0-9_.-]{3,32}" value="`)
+			sb.WriteString(html.EscapeString(data.User.Username))
+			sb.WriteString(`" class="`)
+			sb.WriteString(inputClass)
+			sb.WriteString(`">
+                        <p class="mt-2 text-xs text-gray-500 dark:text-gray-400">3-32 characters: letters, numbers, dots, dashes and underscores.</p>`)
+		} else {
+			sb.WriteString(`<p class="text-gray-900 dark:text-gray-100">`)
+			sb.WriteString(html.EscapeString(data.User.Username))
+			sb.WriteString(`</p>`)
+		}
+		sb.WriteString(`
+                    </div>
+                    <div>
+                        <label for="role" class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Role</label>
+                        <select id="role" name="role" class="`)
+		sb.WriteString(inputClass)
+		sb.WriteString(`">`)
+		for _, role := range data.Roles {
+			sb.WriteString(`<option value="`)
+			sb.WriteString(role)
+			sb.WriteString(`"`)
+			if role == data.User.Role {
+				sb.WriteString(` selected`)
+			}
+			sb.WriteString(`>`)
+			sb.WriteString(role)
+			sb.WriteString(`</option>`)
+		}
+		sb.WriteString(`</select>
+                    </div>
+                    <div>
+                        <label for="password" class="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">Password</label>
+                        <input type="password" id="password" name="password" autocomplete="new-password" minlength="8"`)
+		if data.IsNew {
+			sb.WriteString(` required`)
+		}
+		sb.WriteString(` class="`)
+		sb.WriteString(inputClass)
+		sb.WriteString(`">`)
+		if !data.IsNew {
+			sb.WriteString(`<p class="mt-2 text-xs text-gray-500 dark:text-gray-400">Leave empty to keep the current password.</p>`)
+		}
+		sb.WriteString(`
+                    </div>
+                    <div class="flex items-center justify-between pt-4 border-t border-gray-200 dark:border-gray-700">
+                        <button type="submit" class="inline-flex items-center px-5 py-2.5 border border-transparent text-sm font-medium rounded-md shadow-sm text-white bg-indigo-600 hover:bg-indigo-700 dark:bg-indigo-500 dark:hover:bg-indigo-400">Save User</button>
+                        <a href="/admin" class="text-sm text-gray-600 hover:text-gray-900 dark:text-gray-400 dark:hover:text-gray-200">Cancel</a>
+                    </div>
+                </form>`)
+
+		if !data.IsNew {
+			sb.WriteString(`
+                <form action="/admin/users/`)
+			sb.WriteString(key)
+			sb.WriteString(`/delete" method="POST" class="mt-6" onsubmit="return confirm('Delete this user?');">`)
+			sb.WriteString(`<input type="hidden" name="csrf_token" value="`)
+			sb.WriteString(data.CSRFToken())
+			sb.WriteString(`">
+                    <button type="submit" class="text-sm text-red-600 hover:text-red-800 dark:text-red-400 dark:hover:text-red-300">Delete user</button>
+                </form>`)
+		}
+
+		sb.WriteString(`</div>`)
+		return sb.String()
+	}
+
+//line internal/templates/pages/userform.qtpl:111
+	qw422016.N().S(`
+    `)
+//line internal/templates/pages/userform.qtpl:112
+	qw422016.N().S(layouts.BaseLayout(data, pageContent))
+//line internal/templates/pages/userform.qtpl:112
+	qw422016.N().S(`
+`)
+//line internal/templates/pages/userform.qtpl:113
+}
+
+//line internal/templates/pages/userform.qtpl:113
+func WriteUserFormPage(qq422016 qtio422016.Writer, data *UserFormData) {
+//line internal/templates/pages/userform.qtpl:113
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/templates/pages/userform.qtpl:113
+	StreamUserFormPage(qw422016, data)
+//line internal/templates/pages/userform.qtpl:113
+	qt422016.ReleaseWriter(qw422016)
+//line internal/templates/pages/userform.qtpl:113
+}
+
+//line internal/templates/pages/userform.qtpl:113
+func UserFormPage(data *UserFormData) string {
+//line internal/templates/pages/userform.qtpl:113
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/templates/pages/userform.qtpl:113
+	WriteUserFormPage(qb422016, data)
+//line internal/templates/pages/userform.qtpl:113
+	qs422016 := string(qb422016.B)
+//line internal/templates/pages/userform.qtpl:113
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/templates/pages/userform.qtpl:113
+	return qs422016
+//line internal/templates/pages/userform.qtpl:113
+}
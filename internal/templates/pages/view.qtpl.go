@@ -11,36 +11,53 @@ import "cms/internal/models"
 import "cms/internal/templates/layouts"
 
 //line internal/templates/pages/view.qtpl:3
-import "strings"
+import "cms/internal/diff"
+
+//line internal/templates/pages/view.qtpl:4
+import "cms/internal/workflow"
 
 //line internal/templates/pages/view.qtpl:5
+import "html"
+
+//line internal/templates/pages/view.qtpl:6
+import "strconv"
+
+//line internal/templates/pages/view.qtpl:7
+import "strings"
+
+//line internal/templates/pages/view.qtpl:9
 import (
 	qtio422016 "io"
 
 	qt422016 "github.com/valyala/quicktemplate"
 )
 
-//line internal/templates/pages/view.qtpl:5
+//line internal/templates/pages/view.qtpl:9
 var (
 	_ = qtio422016.Copy
 	_ = qt422016.AcquireByteBuffer
 )
 
-//line internal/templates/pages/view.qtpl:6
+//line internal/templates/pages/view.qtpl:10
 // ViewData struct is defined in models package
 type ViewData = models.ViewData
 
-//line internal/templates/pages/view.qtpl:10
+//line internal/templates/pages/view.qtpl:14
 func StreamViewPage(qw422016 *qt422016.Writer, data *ViewData) {
-//line internal/templates/pages/view.qtpl:10
+//line internal/templates/pages/view.qtpl:14
 	qw422016.N().S(`
     `)
-//line internal/templates/pages/view.qtpl:12
+//line internal/templates/pages/view.qtpl:16
 	pageContent := func() string {
 		var sb strings.Builder
+		if data.Message != "" {
+			sb.WriteString(`<p class="max-w-3xl mx-auto mb-4 rounded-md bg-green-50 p-3 text-sm text-green-800 dark:bg-green-800/30 dark:text-green-300">`)
+			sb.WriteString(html.EscapeString(data.Message))
+			sb.WriteString(`</p>`)
+		}
 		sb.WriteString(`<article class="prose dark:prose-invert prose-indigo lg:prose-lg mx-auto bg-white dark:bg-gray-800 p-6 md:p-8 rounded-lg shadow-md">
                 <h1>`)
-		sb.WriteString(data.Item.Title)
+		sb.WriteString(html.EscapeString(data.Item.Title))
 		sb.WriteString(`</h1>
                 <p class="text-sm text-gray-500 dark:text-gray-400">Status: `)
 		// Add badge styling for status
@@ -51,62 +68,212 @@ func StreamViewPage(qw422016 *qt422016.Writer, data *ViewData) {
 		sb.WriteString(`<span class="inline-flex items-center rounded-md px-2 py-1 text-xs font-medium ring-1 ring-inset ring-gray-500/10 dark:ring-gray-400/20 `)
 		sb.WriteString(statusClass)
 		sb.WriteString(`">`)
-		sb.WriteString(data.Item.Status)
+		sb.WriteString(html.EscapeString(workflow.StateLabel(data.Item.Status)))
 		sb.WriteString(`</span>, Last Updated: `)
 		sb.WriteString(data.Item.UpdatedAt.Format("January 2, 2006"))
-		sb.WriteString(`</p>
-                
+		if data.Item.Status == models.StatusScheduled {
+			sb.WriteString(`, Publishes: `)
+			sb.WriteString(data.Item.PublishedAt.UTC().Format("January 2, 2006 15:04 MST"))
+		}
+		if !data.Item.ExpiresAt.IsZero() && (data.Item.Status == models.StatusScheduled || data.Item.Status == models.StatusPublished) {
+			sb.WriteString(`, Expires: `)
+			sb.WriteString(data.Item.ExpiresAt.UTC().Format("January 2, 2006 15:04 MST"))
+		}
+		sb.WriteString(`</p>`)
+		if len(data.Categories) > 0 || len(data.Tags) > 0 {
+			sb.WriteString(`<div class="not-prose mt-2 flex flex-wrap gap-2 text-sm">`)
+			for _, c := range data.Categories {
+				sb.WriteString(`<a href="/content?category=` + html.EscapeString(c.Slug) + `" class="rounded-md bg-gray-100 px-2 py-0.5 text-gray-700 hover:bg-gray-200 dark:bg-gray-700 dark:text-gray-200">` + html.EscapeString(c.Name) + `</a>`)
+			}
+			for _, t := range data.Tags {
+				sb.WriteString(`<a href="/content?tag=` + html.EscapeString(t.Slug) + `" class="rounded-full bg-indigo-50 px-2.5 py-0.5 text-indigo-700 hover:bg-indigo-100 dark:bg-indigo-900/40 dark:text-indigo-300">#` + html.EscapeString(t.Name) + `</a>`)
+			}
+			sb.WriteString(`</div>`)
+		}
+		if len(data.Transitions) > 0 {
+			sb.WriteString(`<div class="not-prose mt-4">`)
+			sb.WriteString(transitionForms(data.Item.ID, data.Transitions, data.CSRFToken(), ""))
+			sb.WriteString(`</div>`)
+		}
+		sb.WriteString(`
                 <div class="mt-6">
-                    <!-- WARNING: Assuming data.Item.Content is safe HTML. Sanitize if necessary! -->
                     `)
-		// Use WriteString to append the potentially unsafe HTML content
-		sb.WriteString(data.Item.Content)
+		// Body is rendered to sanitized HTML by the handler
+		sb.WriteString(data.Body)
 		sb.WriteString(`
                 </div>
                 
                 <div class="mt-8 border-t border-gray-200 dark:border-gray-700 pt-6 flex items-center space-x-4">
                     <a href="/content/`)
-		sb.WriteString(data.Item.ID)
+		sb.WriteString(html.EscapeString(data.Item.ID))
 		sb.WriteString(`/edit" class="text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">Edit this item</a>
-                    <a href="/content" class="text-gray-600 hover:text-gray-900 dark:text-gray-400 dark:hover:text-gray-200">Back to list</a>
+                    <a href="/content" class="text-gray-600 hover:text-gray-900 dark:text-gray-400 dark:hover:text-gray-200">Back to list</a>`)
+		if data.CanModify {
+			sb.WriteString(`<form method="POST" action="/api/content/`)
+			sb.WriteString(html.EscapeString(data.Item.ID))
+			sb.WriteString(`/trash" class="ml-auto" onsubmit="return confirm('Move this item to the trash?')">`)
+			sb.WriteString(`<input type="hidden" name="csrf_token" value="`)
+			sb.WriteString(data.CSRFToken())
+			sb.WriteString(`"><button type="submit" class="text-red-600 hover:text-red-800 dark:text-red-400 dark:hover:text-red-300">Move to trash</button></form>`)
+		}
+		sb.WriteString(`
                 </div>
             </article>`)
+		sb.WriteString(revisionHistory(data))
 		return sb.String()
 	}
 
-//line internal/templates/pages/view.qtpl:49
+//line internal/templates/pages/view.qtpl:90
 	qw422016.N().S(`
     `)
-//line internal/templates/pages/view.qtpl:50
+//line internal/templates/pages/view.qtpl:91
 	qw422016.N().S(layouts.BaseLayout(data, pageContent))
-//line internal/templates/pages/view.qtpl:50
+//line internal/templates/pages/view.qtpl:91
 	qw422016.N().S(`
 `)
-//line internal/templates/pages/view.qtpl:51
+//line internal/templates/pages/view.qtpl:92
 }
 
-//line internal/templates/pages/view.qtpl:51
+//line internal/templates/pages/view.qtpl:92
 func WriteViewPage(qq422016 qtio422016.Writer, data *ViewData) {
-//line internal/templates/pages/view.qtpl:51
+//line internal/templates/pages/view.qtpl:92
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line internal/templates/pages/view.qtpl:51
+//line internal/templates/pages/view.qtpl:92
 	StreamViewPage(qw422016, data)
-//line internal/templates/pages/view.qtpl:51
+//line internal/templates/pages/view.qtpl:92
 	qt422016.ReleaseWriter(qw422016)
-//line internal/templates/pages/view.qtpl:51
+//line internal/templates/pages/view.qtpl:92
 }
 
-//line internal/templates/pages/view.qtpl:51
+//line internal/templates/pages/view.qtpl:92
 func ViewPage(data *ViewData) string {
-//line internal/templates/pages/view.qtpl:51
+//line internal/templates/pages/view.qtpl:92
 	qb422016 := qt422016.AcquireByteBuffer()
-//line internal/templates/pages/view.qtpl:51
+//line internal/templates/pages/view.qtpl:92
 	WriteViewPage(qb422016, data)
-//line internal/templates/pages/view.qtpl:51
+//line internal/templates/pages/view.qtpl:92
 	qs422016 := string(qb422016.B)
-//line internal/templates/pages/view.qtpl:51
+//line internal/templates/pages/view.qtpl:92
 	qt422016.ReleaseByteBuffer(qb422016)
-//line internal/templates/pages/view.qtpl:51
+//line internal/templates/pages/view.qtpl:92
 	return qs422016
-//line internal/templates/pages/view.qtpl:51
+//line internal/templates/pages/view.qtpl:92
+}
+
+//line internal/templates/pages/view.qtpl:94
+// revisionHistory renders the revision list, the compare form and the
+// diff selected with ?from=&to=.
+func revisionHistory(data *ViewData) string {
+	var sb strings.Builder
+	id := html.EscapeString(data.Item.ID)
+	sb.WriteString(`<section class="max-w-3xl mx-auto mt-8 bg-white dark:bg-gray-800 p-6 md:p-8 rounded-lg shadow-md">
+            <h2 class="text-xl font-semibold mb-4 text-gray-900 dark:text-white">Revision History</h2>`)
+	if len(data.Revisions) == 0 {
+		sb.WriteString(`<p class="text-sm text-gray-500 dark:text-gray-400">No revisions yet. A revision is saved every time this item is edited.</p></section>`)
+		return sb.String()
+	}
+
+	// Compare form, defaulting to the two most recent revisions
+	from, to := data.Revisions[0].Number, data.Revisions[0].Number
+	if len(data.Revisions) > 1 {
+		from = data.Revisions[1].Number
+	}
+	if data.Diff != nil {
+		from, to = data.Diff.From.Number, data.Diff.To.Number
+	}
+	selectClass := `px-3 py-1.5 border border-gray-300 dark:border-gray-600 rounded-md bg-white dark:bg-gray-700 text-sm text-gray-900 dark:text-gray-100`
+	revisionSelect := func(name string, selected int) {
+		sb.WriteString(`<select name="` + name + `" class="` + selectClass + `">`)
+		for _, rev := range data.Revisions {
+			n := strconv.Itoa(rev.Number)
+			sb.WriteString(`<option value="` + n + `"`)
+			if rev.Number == selected {
+				sb.WriteString(` selected`)
+			}
+			sb.WriteString(`>#` + n + `</option>`)
+		}
+		sb.WriteString(`</select>`)
+	}
+	sb.WriteString(`<form method="GET" action="/content/` + id + `" class="flex items-center gap-2 mb-6 text-sm text-gray-700 dark:text-gray-300">Compare `)
+	revisionSelect("from", from)
+	sb.WriteString(` with `)
+	revisionSelect("to", to)
+	sb.WriteString(`<button type="submit" class="px-3 py-1.5 rounded-md bg-indigo-600 text-white hover:bg-indigo-700">Show diff</button></form>`)
+
+	if data.Diff != nil {
+		sb.WriteString(revisionDiff(data.Diff))
+	}
+
+	sb.WriteString(`<ul class="divide-y divide-gray-200 dark:divide-gray-700">`)
+	for i, rev := range data.Revisions {
+		n := strconv.Itoa(rev.Number)
+		sb.WriteString(`<li class="flex items-center justify-between py-3 text-sm"><div><span class="font-medium text-gray-900 dark:text-white">#` + n + `</span> <span class="text-gray-500 dark:text-gray-400">`)
+		sb.WriteString(html.EscapeString(rev.Action))
+		if rev.Action == models.RevisionStatus || rev.Action == models.RevisionSchedule {
+			sb.WriteString(` to ` + html.EscapeString(workflow.StateLabel(rev.Snapshot.Status)))
+		}
+		sb.WriteString(` by `)
+		if rev.Editor != "" {
+			sb.WriteString(html.EscapeString(rev.Editor))
+		} else {
+			sb.WriteString(`unknown`)
+		}
+		sb.WriteString(`, ` + rev.CreatedAt.Format("January 2, 2006 15:04") + `</span>`)
+		if i == 0 {
+			sb.WriteString(` <span class="text-xs text-gray-500 dark:text-gray-400">(current)</span>`)
+		}
+		if rev.Comment != "" {
+			sb.WriteString(`<p class="mt-1 whitespace-pre-line text-gray-700 dark:text-gray-300">`)
+			sb.WriteString(html.EscapeString(rev.Comment))
+			sb.WriteString(`</p>`)
+		}
+		sb.WriteString(`</div>`)
+		if data.CanModify && i > 0 {
+			sb.WriteString(`<form method="POST" action="/api/content/` + id + `/revisions/` + n + `/restore" onsubmit="return confirm('Restore revision #` + n + `?')">`)
+			sb.WriteString(`<input type="hidden" name="csrf_token" value="` + data.CSRFToken() + `">`)
+			sb.WriteString(`<button type="submit" class="text-indigo-600 hover:text-indigo-900 dark:text-indigo-400 dark:hover:text-indigo-300">Restore this revision</button></form>`)
+		}
+		sb.WriteString(`</li>`)
+	}
+	sb.WriteString(`</ul></section>`)
+	return sb.String()
+}
+
+// revisionDiff renders changed fields and the body line diff.
+func revisionDiff(d *models.RevisionDiff) string {
+	var sb strings.Builder
+	sb.WriteString(`<div class="mb-6"><h3 class="text-sm font-semibold mb-2 text-gray-900 dark:text-white">Changes from #` + strconv.Itoa(d.From.Number) + ` to #` + strconv.Itoa(d.To.Number) + `</h3>`)
+	if len(d.Fields) == 0 && !diff.Changed(d.Body) {
+		sb.WriteString(`<p class="text-sm text-gray-500 dark:text-gray-400">No differences.</p></div>`)
+		return sb.String()
+	}
+	if len(d.Fields) > 0 {
+		sb.WriteString(`<dl class="mb-3 text-sm">`)
+		for _, f := range d.Fields {
+			sb.WriteString(`<div class="flex gap-2"><dt class="font-medium text-gray-700 dark:text-gray-300">` + f.Field + `:</dt><dd><del class="text-red-700 dark:text-red-400">`)
+			sb.WriteString(html.EscapeString(f.From))
+			sb.WriteString(`</del> &rarr; <ins class="text-green-700 dark:text-green-400 no-underline">`)
+			sb.WriteString(html.EscapeString(f.To))
+			sb.WriteString(`</ins></dd></div>`)
+		}
+		sb.WriteString(`</dl>`)
+	}
+	if diff.Changed(d.Body) {
+		sb.WriteString(`<pre class="text-xs overflow-x-auto rounded-md border border-gray-200 dark:border-gray-700">`)
+		for _, line := range d.Body {
+			switch line.Op {
+			case diff.Insert:
+				sb.WriteString(`<div class="bg-green-50 text-green-800 dark:bg-green-900/30 dark:text-green-300">+ `)
+			case diff.Delete:
+				sb.WriteString(`<div class="bg-red-50 text-red-800 dark:bg-red-900/30 dark:text-red-300">- `)
+			default:
+				sb.WriteString(`<div class="text-gray-600 dark:text-gray-400">  `)
+			}
+			sb.WriteString(html.EscapeString(line.Text))
+			sb.WriteString(`</div>`)
+		}
+		sb.WriteString(`</pre>`)
+	}
+	sb.WriteString(`</div>`)
+	return sb.String()
 }
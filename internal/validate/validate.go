@@ -0,0 +1,175 @@
+// Package validate checks values against declarative per-field rules and
+// reports every violation at once.
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Errors maps field names to what is wrong with their values. The empty
+// key holds errors about the value as a whole.
+type Errors map[string]string
+
+// Error lists the problems sorted by field name.
+func (e Errors) Error() string {
+	keys := make([]string, 0, len(e))
+	for k := range e {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k == "" {
+			parts = append(parts, e[k])
+		} else {
+			parts = append(parts, k+": "+e[k])
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Rule checks a value, returning what is wrong with it or "". Rules other
+// than Required accept empty values, so optional fields need no special
+// case.
+type Rule func(v any) string
+
+// Field declares the rules for one value.
+type Field struct {
+	Name  string // Reported in Errors, e.g. "title"
+	Value any    // A string, []string or time.Time
+	Rules []Rule // Checked in order; the first violation is reported
+}
+
+// Check applies the rules of every field and returns the violations as
+// Errors, or nil if there are none.
+func Check(fields ...Field) error {
+	errs := Errors{}
+	for _, f := range fields {
+		for _, rule := range f.Rules {
+			if msg := rule(f.Value); msg != "" {
+				errs[f.Name] = msg
+				break
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// empty reports whether v is unset: nil, a blank string, an empty list
+// or the zero time.
+func empty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []string:
+		return len(v) == 0
+	case time.Time:
+		return v.IsZero()
+	}
+	return false
+}
+
+// Required rejects empty values.
+func Required(v any) string {
+	if empty(v) {
+		return "is required"
+	}
+	return ""
+}
+
+// MaxLength limits strings to n characters.
+func MaxLength(n int) Rule {
+	return func(v any) string {
+		if s, ok := v.(string); ok && utf8.RuneCountInString(s) > n {
+			return fmt.Sprintf("must be at most %d characters", n)
+		}
+		return ""
+	}
+}
+
+// Pattern requires strings to match re; hint describes the expected form,
+// e.g. "lowercase letters and hyphens".
+func Pattern(re *regexp.Regexp, hint string) Rule {
+	return func(v any) string {
+		if s, ok := v.(string); ok && !empty(s) && !re.MatchString(s) {
+			return "must be " + hint
+		}
+		return ""
+	}
+}
+
+// OneOf requires strings to be one of options.
+func OneOf(options ...string) Rule {
+	return func(v any) string {
+		s, ok := v.(string)
+		if !ok || empty(s) {
+			return ""
+		}
+		for _, o := range options {
+			if s == o {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(options, ", ")
+	}
+}
+
+// Each applies rules to every element of a []string, reporting the first
+// element that breaks one.
+func Each(rules ...Rule) Rule {
+	return func(v any) string {
+		list, _ := v.([]string)
+		for _, s := range list {
+			for _, rule := range rules {
+				if msg := rule(s); msg != "" {
+					return fmt.Sprintf("'%s' %s", s, msg)
+				}
+			}
+		}
+		return ""
+	}
+}
+
+// MaxItems limits a []string to n elements.
+func MaxItems(n int) Rule {
+	return func(v any) string {
+		if list, ok := v.([]string); ok && len(list) > n {
+			return fmt.Sprintf("must have at most %d entries", n)
+		}
+		return ""
+	}
+}
+
+// NotBefore requires a time to be at or after other, the value of the
+// field named name. Either time being zero passes.
+func NotBefore(name string, other time.Time) Rule {
+	return func(v any) string {
+		t, ok := v.(time.Time)
+		if ok && !t.IsZero() && !other.IsZero() && t.Before(other) {
+			return "must not be before " + name
+		}
+		return ""
+	}
+}
+
+// After requires a time to be later than other, the value of the field
+// named name. Either time being zero passes.
+func After(name string, other time.Time) Rule {
+	return func(v any) string {
+		t, ok := v.(time.Time)
+		if ok && !t.IsZero() && !other.IsZero() && !t.After(other) {
+			return "must be after " + name
+		}
+		return ""
+	}
+}
@@ -0,0 +1,97 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestRules(t *testing.T) {
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	slugRe := regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	tests := []struct {
+		name string
+		rule Rule
+		v    any
+		want string // "" if the value passes
+	}{
+		{"required string", Required, "x", ""},
+		{"required blank string", Required, "  ", "is required"},
+		{"required nil", Required, nil, "is required"},
+		{"required empty list", Required, []string{}, "is required"},
+		{"required list", Required, []string{"a"}, ""},
+		{"required zero time", Required, time.Time{}, "is required"},
+		{"required time", Required, t0, ""},
+
+		{"max length at limit", MaxLength(3), "abc", ""},
+		{"max length over", MaxLength(3), "abcd", "must be at most 3 characters"},
+		{"max length counts characters", MaxLength(3), "äöü", ""},
+		{"max length ignores other types", MaxLength(1), []string{"a", "b"}, ""},
+
+		{"pattern match", Pattern(slugRe, "a slug"), "a-b", ""},
+		{"pattern mismatch", Pattern(slugRe, "a slug"), "A B", "must be a slug"},
+		{"pattern skips empty", Pattern(slugRe, "a slug"), "", ""},
+
+		{"one of", OneOf("x", "y"), "y", ""},
+		{"one of mismatch", OneOf("x", "y"), "z", "must be one of x, y"},
+		{"one of skips empty", OneOf("x", "y"), "", ""},
+
+		{"max items at limit", MaxItems(2), []string{"a", "b"}, ""},
+		{"max items over", MaxItems(2), []string{"a", "b", "c"}, "must have at most 2 entries"},
+
+		{"each passes", Each(MaxLength(2)), []string{"ab", "c"}, ""},
+		{"each names the first bad element", Each(MaxLength(2)), []string{"ab", "abc", "abcd"}, "'abc' must be at most 2 characters"},
+		{"each applies rules in order", Each(MaxLength(5), Pattern(slugRe, "a slug")), []string{"Bad!"}, "'Bad!' must be a slug"},
+		{"each skips other types", Each(MaxLength(1)), "abc", ""},
+
+		{"not before later", NotBefore("start", t0), t0.Add(time.Hour), ""},
+		{"not before equal", NotBefore("start", t0), t0, ""},
+		{"not before earlier", NotBefore("start", t0), t0.Add(-time.Hour), "must not be before start"},
+		{"not before zero value", NotBefore("start", t0), time.Time{}, ""},
+		{"not before zero other", NotBefore("start", time.Time{}), t0, ""},
+
+		{"after later", After("start", t0), t0.Add(time.Second), ""},
+		{"after equal", After("start", t0), t0, "must be after start"},
+		{"after earlier", After("start", t0), t0.Add(-time.Hour), "must be after start"},
+		{"after zero value", After("start", t0), time.Time{}, ""},
+		{"after zero other", After("start", time.Time{}), t0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule(tt.v); got != tt.want {
+				t.Errorf("rule(%v) = %q, want %q", tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	err := Check(
+		Field{Name: "title", Value: "", Rules: []Rule{Required, MaxLength(3)}},
+		Field{Name: "slug", Value: "ok", Rules: []Rule{MaxLength(3)}},
+		Field{Name: "body", Value: "toolong", Rules: []Rule{MaxLength(3), OneOf("x")}},
+	)
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Check = %v, want Errors", err)
+	}
+	// Every field is checked, and only its first violation is reported
+	want := Errors{"title": "is required", "body": "must be at most 3 characters"}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("Check = %v, want %v", errs, want)
+	}
+
+	if err := Check(Field{Name: "title", Value: "x", Rules: []Rule{Required}}); err != nil {
+		t.Errorf("Check of a valid field = %v, want nil", err)
+	}
+}
+
+func TestErrorsError(t *testing.T) {
+	errs := Errors{"title": "is required", "": "is empty", "body": "is too long"}
+	want := "is empty; body: is too long; title: is required"
+	if got := errs.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,228 @@
+// Package workflow defines the editorial states of content and the role
+// permitted to move an item between them.
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"cms/internal/auth"
+)
+
+// Content states of the default workflow. Scheduled items are moved to
+// published, and published items with an expiry to archived, by the
+// publishing scheduler regardless of the transitions below.
+const (
+	StateDraft     = "draft"
+	StateInReview  = "in_review"
+	StateApproved  = "approved"
+	StateScheduled = "scheduled"
+	StatePublished = "published"
+	StateArchived  = "archived"
+)
+
+// Transition is a permitted move between two states.
+type Transition struct {
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Roles   []string `json:"roles"`             // Roles allowed to make the move
+	Comment bool     `json:"comment,omitempty"` // Whether a comment is required, e.g. for rejections
+	Label   string   `json:"label,omitempty"`   // Button text, defaults to the target state
+}
+
+// Name returns the button text of the transition.
+func (t Transition) Name() string {
+	if t.Label != "" {
+		return t.Label
+	}
+	return StateLabel(t.To)
+}
+
+// Workflow is a state machine over content statuses.
+type Workflow struct {
+	Initial     string       `json:"initial"` // State of new items
+	States      []string     `json:"states"`
+	Review      []string     `json:"review"` // States listed on the review queue
+	Transitions []Transition `json:"transitions"`
+}
+
+// Errors returned by Workflow.Check.
+var (
+	ErrUnknownState = errors.New("unknown status")
+	ErrNotAllowed   = errors.New("status change not allowed")
+	ErrNeedComment  = errors.New("a comment is required")
+)
+
+var (
+	staff = []string{auth.RoleAdmin, auth.RoleEditor}
+	all   = []string{auth.RoleAdmin, auth.RoleEditor, auth.RoleAuthor}
+)
+
+// Default returns the built-in workflow: authors submit drafts for review,
+// editors approve or reject them and publish approved items. Editors may
+// also publish their own drafts directly.
+func Default() *Workflow {
+	return &Workflow{
+		Initial: StateDraft,
+		States:  []string{StateDraft, StateInReview, StateApproved, StateScheduled, StatePublished, StateArchived},
+		Review:  []string{StateInReview},
+		Transitions: []Transition{
+			{From: StateDraft, To: StateInReview, Roles: all, Label: "Submit for review"},
+			{From: StateInReview, To: StateDraft, Roles: staff, Comment: true, Label: "Reject"},
+			{From: StateInReview, To: StateApproved, Roles: staff, Label: "Approve"},
+			{From: StateApproved, To: StateInReview, Roles: staff, Label: "Back to review"},
+			{From: StateApproved, To: StatePublished, Roles: staff, Label: "Publish"},
+			{From: StateApproved, To: StateScheduled, Roles: staff, Label: "Schedule"},
+			{From: StateDraft, To: StatePublished, Roles: staff, Label: "Publish"},
+			{From: StateDraft, To: StateScheduled, Roles: staff, Label: "Schedule"},
+			{From: StateScheduled, To: StateApproved, Roles: staff, Label: "Unschedule"},
+			{From: StateScheduled, To: StatePublished, Roles: staff, Label: "Publish now"},
+			{From: StatePublished, To: StateArchived, Roles: staff, Label: "Archive"},
+			{From: StatePublished, To: StateDraft, Roles: staff, Label: "Unpublish"},
+			{From: StateArchived, To: StateDraft, Roles: staff, Label: "Back to draft"},
+		},
+	}
+}
+
+// Load reads a workflow from a JSON file, or returns Default for an empty path.
+func Load(path string) (*Workflow, error) {
+	if path == "" {
+		return Default(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow %s: %w", path, err)
+	}
+	w := &Workflow{}
+	if err := json.Unmarshal(data, w); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow %s: %w", path, err)
+	}
+	if err := w.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid workflow %s: %w", path, err)
+	}
+	return w, nil
+}
+
+// Validate checks that the workflow only refers to its own states and to
+// known roles.
+func (w *Workflow) Validate() error {
+	if len(w.States) == 0 {
+		return errors.New("no states defined")
+	}
+	seen := make(map[string]bool, len(w.States))
+	for _, s := range w.States {
+		if s == "" || seen[s] {
+			return fmt.Errorf("empty or duplicate state '%s'", s)
+		}
+		seen[s] = true
+	}
+	if !seen[w.Initial] {
+		return fmt.Errorf("initial state '%s' is not a state", w.Initial)
+	}
+	for _, s := range w.Review {
+		if !seen[s] {
+			return fmt.Errorf("review state '%s' is not a state", s)
+		}
+	}
+	for _, t := range w.Transitions {
+		if !seen[t.From] || !seen[t.To] || t.From == t.To {
+			return fmt.Errorf("invalid transition '%s' -> '%s'", t.From, t.To)
+		}
+		for _, role := range t.Roles {
+			if !auth.ValidRole(role) {
+				return fmt.Errorf("unknown role '%s' in transition '%s' -> '%s'", role, t.From, t.To)
+			}
+		}
+	}
+	return nil
+}
+
+// Valid reports whether state belongs to the workflow.
+func (w *Workflow) Valid(state string) bool {
+	for _, s := range w.States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// Check reports whether role may move an item from one state to another,
+// with or without a comment. Staying in the same state is always allowed.
+// Items in a state the workflow does not know (saved before it changed)
+// are treated as being in the initial state.
+func (w *Workflow) Check(role, from, to string, hasComment bool) error {
+	if !w.Valid(to) {
+		return fmt.Errorf("%w '%s'", ErrUnknownState, to)
+	}
+	if !w.Valid(from) {
+		from = w.Initial
+	}
+	if from == to {
+		return nil
+	}
+	for _, t := range w.Transitions {
+		if t.From != from || t.To != to || !allows(t, role) {
+			continue
+		}
+		if t.Comment && !hasComment {
+			return fmt.Errorf("%w to move from %s to %s", ErrNeedComment, from, to)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w from %s to %s", ErrNotAllowed, from, to)
+}
+
+// Available lists the transitions role may make from state.
+func (w *Workflow) Available(role, state string) []Transition {
+	if !w.Valid(state) {
+		state = w.Initial
+	}
+	var out []Transition
+	for _, t := range w.Transitions {
+		if t.From == state && allows(t, role) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// Statuses lists the states an item in state may be saved with by role:
+// the current state followed by the targets of its transitions that need
+// no comment. New items start from the initial state.
+func (w *Workflow) Statuses(role, state string) []string {
+	if !w.Valid(state) {
+		state = w.Initial
+	}
+	out := []string{state}
+	seen := map[string]bool{state: true}
+	for _, t := range w.Available(role, state) {
+		if !t.Comment && !seen[t.To] {
+			out = append(out, t.To)
+			seen[t.To] = true
+		}
+	}
+	return out
+}
+
+func allows(t Transition, role string) bool {
+	for _, r := range t.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// StateLabel turns a state name into display text, e.g. "in_review"
+// becomes "In review".
+func StateLabel(state string) string {
+	if state == "" {
+		return ""
+	}
+	s := strings.ReplaceAll(state, "_", " ")
+	return strings.ToUpper(s[:1]) + s[1:]
+}